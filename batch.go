@@ -0,0 +1,144 @@
+package coinmarketcap
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DefaultBatchSize is the maximum number of IDs, symbols or slugs sent in a single
+// request to a batch endpoint before the client splits the call.
+const DefaultBatchSize = 100
+
+// batchKeys lists the identifier parameters that batch endpoints accept as comma-separated lists.
+var batchKeys = []string{"id", "symbol", "slug"}
+
+// WithBatchSize sets how many identifiers are sent per request to batch endpoints
+// such as GetCryptocurrencyQuotesLatest. Larger lists are split into several requests
+// whose results are merged. A size of zero or less disables splitting.
+func WithBatchSize(size int) Option {
+	return func(c *ClientConfig) {
+		c.BatchSize = size
+	}
+}
+
+// BatchError reports which batch of a split request failed.
+type BatchError struct {
+	Batch   int // 1-based index of the failed batch
+	Batches int // total number of batches
+	Param   string
+	Values  []string
+	Err     error
+}
+
+// Error implements the error interface.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch %d/%d (%s=%s) failed: %v", e.Batch, e.Batches, e.Param, strings.Join(e.Values, ","), e.Err)
+}
+
+// Unwrap returns the underlying error so errors.As can reach the APIError.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// queryBatch is one slice of a split request.
+type queryBatch struct {
+	params url.Values
+	key    string
+	values []string
+}
+
+// splitBatches splits the identifier lists in params into batches of at most size items.
+// When no list exceeds size the original parameters are returned as a single batch.
+func splitBatches(params url.Values, size int) []queryBatch {
+	if size <= 0 {
+		return []queryBatch{{params: params}}
+	}
+
+	split := false
+	for _, key := range batchKeys {
+		if len(strings.Split(params.Get(key), ",")) > size {
+			split = true
+			break
+		}
+	}
+	if !split {
+		return []queryBatch{{params: params}}
+	}
+
+	base := make(url.Values, len(params))
+	for key, values := range params {
+		base[key] = values
+	}
+	for _, key := range batchKeys {
+		base.Del(key)
+	}
+
+	var batches []queryBatch
+	for _, key := range batchKeys {
+		if params.Get(key) == "" {
+			continue
+		}
+
+		values := strings.Split(params.Get(key), ",")
+		for start := 0; start < len(values); start += size {
+			end := start + size
+			if end > len(values) {
+				end = len(values)
+			}
+
+			batch := make(url.Values, len(base)+1)
+			for k, v := range base {
+				batch[k] = v
+			}
+			batch.Set(key, strings.Join(values[start:end], ","))
+
+			batches = append(batches, queryBatch{params: batch, key: key, values: values[start:end]})
+		}
+	}
+
+	return batches
+}
+
+// getBatched runs fetch once per batch of identifiers and merges the keyed results into
+// a single response. Batches run sequentially so each one passes through the rate limiter.
+// Credit counts and elapsed times are summed across batches; batches served from the cache
+// spent no credits and are left out of the credit count.
+func getBatched[V any](c *Client, ctx context.Context, params url.Values, fetch func(context.Context, url.Values) (*APIResponse[map[string]V], error)) (*APIResponse[map[string]V], error) {
+	batches := splitBatches(params, c.batchSize)
+	if len(batches) == 1 {
+		return fetch(ctx, batches[0].params)
+	}
+
+	merged := &APIResponse[map[string]V]{
+		Data: make(map[string]V),
+	}
+
+	for i, batch := range batches {
+		resp, err := fetch(ctx, batch.params)
+		if err != nil {
+			return nil, &BatchError{
+				Batch:   i + 1,
+				Batches: len(batches),
+				Param:   batch.key,
+				Values:  batch.values,
+				Err:     err,
+			}
+		}
+
+		for key, value := range resp.Data {
+			merged.Data[key] = value
+		}
+
+		credits, elapsed := merged.Status.CreditCount, merged.Status.Elapsed
+		merged.Status = resp.Status
+		merged.Status.CreditCount = credits
+		if !resp.cached {
+			merged.Status.CreditCount += resp.Status.CreditCount
+		}
+		merged.Status.Elapsed = elapsed + resp.Status.Elapsed
+	}
+
+	return merged, nil
+}
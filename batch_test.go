@@ -0,0 +1,161 @@
+package coinmarketcap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestSplitBatches(t *testing.T) {
+	params := url.Values{
+		"id":      {"1,2,3,4,5"},
+		"convert": {"USD"},
+	}
+
+	batches := splitBatches(params, 2)
+	if len(batches) != 3 {
+		t.Fatalf("expected 3 batches, got %d", len(batches))
+	}
+
+	expected := []string{"1,2", "3,4", "5"}
+	for i, batch := range batches {
+		if batch.params.Get("id") != expected[i] {
+			t.Errorf("batch %d: expected id=%s, got %s", i, expected[i], batch.params.Get("id"))
+		}
+		if batch.params.Get("convert") != "USD" {
+			t.Errorf("batch %d: expected convert to be preserved", i)
+		}
+	}
+
+	if got := splitBatches(params, 10); len(got) != 1 || got[0].params.Get("id") != "1,2,3,4,5" {
+		t.Errorf("expected a single unchanged batch when under the limit")
+	}
+}
+
+func TestGetCryptocurrencyInfoBatching(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		ids := strings.Split(r.URL.Query().Get("id"), ",")
+		if len(ids) > 2 {
+			t.Errorf("expected at most 2 ids per request, got %d", len(ids))
+		}
+
+		var entries []string
+		for _, id := range ids {
+			entries = append(entries, fmt.Sprintf(`"%s": {"id": %s, "symbol": "C%s"}`, id, id, id))
+		}
+		fmt.Fprintf(w, `{"data": {%s}, "status": {"error_code": 0, "credit_count": 1, "elapsed": 5}}`, strings.Join(entries, ","))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1000)),
+		WithBatchSize(2),
+	)
+
+	resp, err := client.GetCryptocurrencyInfo(context.Background(), &CryptocurrencyInfoOptions{
+		ID: []int{1, 2, 3, 4, 5},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+	if len(resp.Data) != 5 {
+		t.Errorf("expected 5 merged entries, got %d", len(resp.Data))
+	}
+	if resp.Data["4"].Symbol != "C4" {
+		t.Errorf("expected entry 4 to have symbol C4, got %q", resp.Data["4"].Symbol)
+	}
+	if resp.Status.CreditCount != 3 {
+		t.Errorf("expected summed credit count 3, got %d", resp.Status.CreditCount)
+	}
+	if resp.Status.Elapsed != 15 {
+		t.Errorf("expected summed elapsed 15, got %d", resp.Status.Elapsed)
+	}
+}
+
+func TestGetBatchedSkipsCachedCredits(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		id := r.URL.Query().Get("id")
+		fmt.Fprintf(w, `{"data": {"%s": {"id": %s}}, "status": {"error_code": 0, "credit_count": 1}}`, id, id)
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1000)),
+		WithBatchSize(1),
+		WithCache(NewMemoryCache(10)),
+		WithCacheTTL("/v2/cryptocurrency/info", time.Minute),
+	)
+
+	ctx := context.Background()
+	if _, err := client.GetCryptocurrencyInfo(ctx, &CryptocurrencyInfoOptions{ID: []int{1}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := client.GetCryptocurrencyInfo(ctx, &CryptocurrencyInfoOptions{ID: []int{1, 2}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected the cached batch to skip the network, got %d requests", requests)
+	}
+	if len(resp.Data) != 2 {
+		t.Errorf("expected 2 merged entries, got %d", len(resp.Data))
+	}
+	if resp.Status.CreditCount != 1 {
+		t.Errorf("expected only the fetched batch's credit, got %d", resp.Status.CreditCount)
+	}
+}
+
+func TestGetBatchedError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Query().Get("id"), "3") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status": {"error_code": 400, "error_message": "Invalid value for \"id\""}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {}, "status": {"error_code": 0}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1000)),
+		WithBatchSize(2),
+	)
+
+	_, err := client.GetExchangeInfo(context.Background(), &ExchangeInfoOptions{ID: []int{1, 2, 3, 4}})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected BatchError, got %T", err)
+	}
+	if batchErr.Batch != 2 || batchErr.Batches != 2 {
+		t.Errorf("expected batch 2/2 to fail, got %d/%d", batchErr.Batch, batchErr.Batches)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode != 400 {
+		t.Errorf("expected wrapped APIError with code 400, got %v", err)
+	}
+}
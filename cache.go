@@ -0,0 +1,260 @@
+package coinmarketcap
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores raw API response bodies keyed by endpoint and canonical query.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the cached body for key if present and not expired.
+	Get(key string) ([]byte, bool)
+	// Set stores body under key for the given time-to-live.
+	Set(key string, body []byte, ttl time.Duration)
+}
+
+// Default cache TTLs per endpoint family.
+const (
+	CacheTTLReference  = 24 * time.Hour   // map and info endpoints
+	CacheTTLLatest     = 60 * time.Second // latest quotes, listings and metrics
+	CacheTTLHistorical = 5 * time.Minute  // historical series
+)
+
+// WithCache enables response caching using the provided Cache implementation.
+func WithCache(cache Cache) Option {
+	return func(c *ClientConfig) {
+		c.Cache = cache
+	}
+}
+
+// WithCacheTTL overrides the cache TTL for a single endpoint (e.g. "/v1/cryptocurrency/map").
+// A TTL of zero disables caching for that endpoint.
+func WithCacheTTL(endpoint string, ttl time.Duration) Option {
+	return func(c *ClientConfig) {
+		if c.CacheTTLs == nil {
+			c.CacheTTLs = make(map[string]time.Duration)
+		}
+		c.CacheTTLs[endpoint] = ttl
+	}
+}
+
+// DefaultCacheTTL returns the default cache TTL for an endpoint based on its family.
+// Reference data (map, info and DEX network endpoints) is cached for a day, latest data for a minute
+// and historical data for five minutes. Key usage information is never cached.
+func DefaultCacheTTL(endpoint string) time.Duration {
+	switch {
+	case strings.HasPrefix(endpoint, "/v1/key/"):
+		return 0
+	case strings.HasSuffix(endpoint, "/map"), strings.HasSuffix(endpoint, "/info"), strings.HasSuffix(endpoint, "/networks/list"):
+		return CacheTTLReference
+	case strings.HasSuffix(endpoint, "/latest"), strings.HasSuffix(endpoint, "-latest"):
+		return CacheTTLLatest
+	case strings.HasSuffix(endpoint, "/historical"), strings.HasSuffix(endpoint, "-historical"):
+		return CacheTTLHistorical
+	default:
+		return 0
+	}
+}
+
+// cacheTTL returns the effective cache TTL for an endpoint, honoring overrides.
+func (c *Client) cacheTTL(endpoint string) time.Duration {
+	if ttl, ok := c.cacheTTLs[endpoint]; ok {
+		return ttl
+	}
+	return DefaultCacheTTL(endpoint)
+}
+
+// CacheKey builds a cache key from an endpoint and its query parameters.
+// Parameters are sorted by name and comma-separated values are sorted, so
+// equivalent requests share a key regardless of the order they were built in.
+func CacheKey(endpoint string, params url.Values) string {
+	if len(params) == 0 {
+		return endpoint
+	}
+
+	canonical := make(url.Values, len(params))
+	for key, values := range params {
+		for _, value := range values {
+			parts := strings.Split(value, ",")
+			sort.Strings(parts)
+			canonical.Add(key, strings.Join(parts, ","))
+		}
+		sort.Strings(canonical[key])
+	}
+
+	return endpoint + "?" + canonical.Encode()
+}
+
+// MemoryCache is an in-memory LRU Cache with per-entry expiry.
+type MemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key       string
+	body      []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCache creates an in-memory LRU cache holding at most capacity entries.
+// A capacity of zero or less means the cache is unbounded.
+func NewMemoryCache(capacity int) *MemoryCache {
+	return &MemoryCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get implements Cache.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*memoryCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		m.order.Remove(elem)
+		delete(m.entries, key)
+		return nil, false
+	}
+
+	m.order.MoveToFront(elem)
+	return entry.body, true
+}
+
+// Set implements Cache.
+func (m *MemoryCache) Set(key string, body []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expiresAt := time.Now().Add(ttl)
+	if elem, ok := m.entries[key]; ok {
+		entry := elem.Value.(*memoryCacheEntry)
+		entry.body = body
+		entry.expiresAt = expiresAt
+		m.order.MoveToFront(elem)
+		return
+	}
+
+	m.entries[key] = m.order.PushFront(&memoryCacheEntry{
+		key:       key,
+		body:      body,
+		expiresAt: expiresAt,
+	})
+
+	for m.capacity > 0 && m.order.Len() > m.capacity {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// Len returns the number of entries currently held, including expired ones not yet evicted.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// FileCache is a Cache that persists entries as files in a directory,
+// so cached reference data survives process restarts.
+type FileCache struct {
+	dir string
+}
+
+type fileCacheEntry struct {
+	Key       string    `json:"key"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Body      []byte    `json:"body"`
+}
+
+// NewFileCache creates a filesystem-backed cache rooted at dir, creating it if needed.
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &FileCache{dir: dir}, nil
+}
+
+// path returns the file path for a cache key.
+func (f *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get implements Cache.
+func (f *FileCache) Get(key string) ([]byte, bool) {
+	path := f.path(key)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry fileCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key {
+		return nil, false
+	}
+
+	if time.Now().After(entry.ExpiresAt) {
+		os.Remove(path)
+		return nil, false
+	}
+
+	return entry.Body, true
+}
+
+// Set implements Cache. Write errors are ignored since a cache miss is always safe.
+func (f *FileCache) Set(key string, body []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(fileCacheEntry{
+		Key:       key,
+		ExpiresAt: time.Now().Add(ttl),
+		Body:      body,
+	})
+	if err != nil {
+		return
+	}
+
+	// Write to a temporary file first so concurrent readers never see a partial entry.
+	tmp, err := os.CreateTemp(f.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return
+	}
+	tmp.Close()
+
+	if err := os.Rename(tmp.Name(), f.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,154 @@
+package coinmarketcap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestCacheKey(t *testing.T) {
+	a := url.Values{"symbol": {"ETH,BTC"}, "convert": {"USD"}}
+	b := url.Values{"convert": {"USD"}, "symbol": {"BTC,ETH"}}
+
+	if CacheKey("/v2/cryptocurrency/quotes/latest", a) != CacheKey("/v2/cryptocurrency/quotes/latest", b) {
+		t.Errorf("expected equivalent queries to share a cache key")
+	}
+
+	if CacheKey("/v1/cryptocurrency/map", nil) != "/v1/cryptocurrency/map" {
+		t.Errorf("expected bare endpoint as key, got %s", CacheKey("/v1/cryptocurrency/map", nil))
+	}
+}
+
+func TestDefaultCacheTTL(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		expected time.Duration
+	}{
+		{"/v1/cryptocurrency/map", CacheTTLReference},
+		{"/v1/fiat/map", CacheTTLReference},
+		{"/v2/cryptocurrency/info", CacheTTLReference},
+		{"/v4/dex/networks/list", CacheTTLReference},
+		{"/v4/dex/spot-pairs/latest", CacheTTLLatest},
+		{"/v2/cryptocurrency/quotes/latest", CacheTTLLatest},
+		{"/v3/index/cmc100-latest", CacheTTLLatest},
+		{"/v2/cryptocurrency/ohlcv/historical", CacheTTLHistorical},
+		{"/v1/key/info", 0},
+		{"/v1/tools/postman", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			if ttl := DefaultCacheTTL(tt.endpoint); ttl != tt.expected {
+				t.Errorf("expected TTL %v, got %v", tt.expected, ttl)
+			}
+		})
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	cache := NewMemoryCache(2)
+
+	cache.Set("a", []byte("1"), time.Minute)
+	cache.Set("b", []byte("2"), time.Minute)
+
+	// Touch "a" so "b" becomes the least recently used entry.
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("expected cache hit for a")
+	}
+
+	cache.Set("c", []byte("3"), time.Minute)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	if body, ok := cache.Get("c"); !ok || string(body) != "3" {
+		t.Errorf("expected cache hit for c, got %q", body)
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", cache.Len())
+	}
+
+	cache.Set("expired", []byte("x"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok := cache.Get("expired"); ok {
+		t.Error("expected expired entry to miss")
+	}
+}
+
+func TestFileCache(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cache.Set("/v1/fiat/map", []byte(`{"data":[]}`), time.Minute)
+
+	body, ok := cache.Get("/v1/fiat/map")
+	if !ok || string(body) != `{"data":[]}` {
+		t.Errorf("expected cache hit, got %q", body)
+	}
+
+	if _, ok := cache.Get("/v1/cryptocurrency/map"); ok {
+		t.Error("expected cache miss for unknown key")
+	}
+
+	cache.Set("short", []byte("x"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok := cache.Get("short"); ok {
+		t.Error("expected expired entry to miss")
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": [{"id": 2781, "name": "United States Dollar", "sign": "$", "symbol": "USD"}], "status": {"error_code": 0, "credit_count": 1}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1000)),
+		WithCache(NewMemoryCache(10)),
+		WithCacheTTL("/v1/key/info", time.Minute),
+	)
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		resp, err := client.GetFiatMap(ctx, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(resp.Data) != 1 || resp.Data[0].Symbol != "USD" {
+			t.Fatalf("unexpected data: %+v", resp.Data)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("expected 1 upstream call, got %d", calls)
+	}
+
+	// Endpoints without a TTL are never cached.
+	if _, err := get[interface{}](client, ctx, "/v1/tools/postman", &RequestOptions[interface{}]{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := get[interface{}](client, ctx, "/v1/tools/postman", &RequestOptions[interface{}]{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 upstream calls, got %d", calls)
+	}
+
+	if ttl := client.cacheTTL("/v1/key/info"); ttl != time.Minute {
+		t.Errorf("expected overridden TTL of 1m, got %v", ttl)
+	}
+}
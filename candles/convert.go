@@ -0,0 +1,123 @@
+package candles
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// FromOHLCV normalizes OHLCV data from GetCryptocurrencyOHLCVHistorical into a series.
+// A candle's time is its TimeOpen, or else its TimeClose or Timestamp. Missing open or
+// close prices are taken from each other and missing highs and lows from the open and
+// close; candles without any of those prices or a time are left out, so they show up
+// as gaps.
+func FromOHLCV(interval time.Duration, quotes []cmc.OHLCV) (Series, error) {
+	candles := make([]Candle, 0, len(quotes))
+	for _, q := range quotes {
+		c, ok := fromOHLCV(q)
+		if ok {
+			candles = append(candles, c)
+		}
+	}
+	return Normalize(interval, candles)
+}
+
+func fromOHLCV(q cmc.OHLCV) (Candle, bool) {
+	var c Candle
+	switch {
+	case q.TimeOpen != nil:
+		c.Time = *q.TimeOpen
+	case q.TimeClose != nil:
+		c.Time = *q.TimeClose
+	case q.Timestamp != nil:
+		c.Time = *q.Timestamp
+	default:
+		return Candle{}, false
+	}
+
+	switch {
+	case q.Open != nil && q.Close != nil:
+		c.Open, c.Close = *q.Open, *q.Close
+	case q.Open != nil:
+		c.Open, c.Close = *q.Open, *q.Open
+	case q.Close != nil:
+		c.Open, c.Close = *q.Close, *q.Close
+	default:
+		return Candle{}, false
+	}
+
+	c.High, c.Low = max(c.Open, c.Close), min(c.Open, c.Close)
+	if q.High != nil {
+		c.High = max(c.High, *q.High)
+	}
+	if q.Low != nil {
+		c.Low = min(c.Low, *q.Low)
+	}
+	if q.Volume != nil {
+		c.Volume = *q.Volume
+	}
+	if q.MarketCap != nil {
+		c.MarketCap = *q.MarketCap
+	}
+	return c, true
+}
+
+// FromHistoricalQuotes builds synthetic candles from the price points of
+// GetCryptocurrencyQuotesHistorical in the convert currency: the first, highest,
+// lowest and last price in each interval. Quotes only carry a rolling 24h volume, so a
+// candle's volume is an estimate: the last Volume24h scaled to the interval. Points
+// without a price are skipped.
+func FromHistoricalQuotes(interval time.Duration, convert string, quotes []cmc.HistoricalQuote) (Series, error) {
+	points := make([]cmc.HistoricalQuote, 0, len(quotes))
+	for _, q := range quotes {
+		if quote := quoteIn(q.Quote, convert); quote != nil && quote.Price != nil {
+			points = append(points, q)
+		}
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) })
+
+	candles := make([]Candle, 0, len(points))
+	for _, point := range points {
+		quote := quoteIn(point.Quote, convert)
+		price := *quote.Price
+
+		var volume, marketCap float64
+		if quote.Volume24h != nil && interval > 0 {
+			volume = *quote.Volume24h * float64(interval) / float64(24*time.Hour)
+		}
+		if quote.MarketCap != nil {
+			marketCap = *quote.MarketCap
+		}
+
+		t := point.Timestamp
+		if interval > 0 {
+			t = align(t, interval)
+		}
+		if n := len(candles); n > 0 && candles[n-1].Time.Equal(t) {
+			c := &candles[n-1]
+			c.High = max(c.High, price)
+			c.Low = min(c.Low, price)
+			c.Close = price
+			c.Volume = volume
+			c.MarketCap = marketCap
+			continue
+		}
+		candles = append(candles, Candle{Time: t, Open: price, High: price, Low: price, Close: price, Volume: volume, MarketCap: marketCap})
+	}
+	return Normalize(interval, candles)
+}
+
+// quoteIn returns the quote in convert, matching the currency case-insensitively.
+func quoteIn(quotes map[string]*cmc.Quote, convert string) *cmc.Quote {
+	if quote, ok := quotes[convert]; ok {
+		return quote
+	}
+	for currency, quote := range quotes {
+		if strings.EqualFold(currency, convert) {
+			return quote
+		}
+	}
+	return nil
+}
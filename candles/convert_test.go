@@ -0,0 +1,91 @@
+package candles
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+	"github.com/Davincible/go-coinmarketcap/cmctest"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestFromOHLCV(t *testing.T) {
+	closeTime := day.Add(2*24*time.Hour - time.Millisecond)
+	series, err := FromOHLCV(24*time.Hour, []cmc.OHLCV{
+		{TimeOpen: ptr(day), Open: ptr(10.0), High: ptr(12.0), Low: ptr(9.0), Close: ptr(11.0), Volume: ptr(5.0)},
+		{TimeClose: &closeTime, Close: ptr(13.0), High: ptr(12.5)},
+		{TimeOpen: ptr(day.Add(3 * 24 * time.Hour))},
+		{Open: ptr(1.0), Close: ptr(1.0)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Candle{
+		{Time: day, Open: 10, High: 12, Low: 9, Close: 11, Volume: 5},
+		{Time: day.Add(24 * time.Hour), Open: 13, High: 13, Low: 13, Close: 13},
+	}
+	if !reflect.DeepEqual(series.Candles, want) {
+		t.Errorf("expected %+v, got %+v", want, series.Candles)
+	}
+}
+
+func TestFromHistoricalQuotes(t *testing.T) {
+	point := func(minutes int, price, volume float64) cmc.HistoricalQuote {
+		return cmc.HistoricalQuote{
+			Timestamp: day.Add(time.Duration(minutes) * time.Minute),
+			Quote:     map[string]*cmc.Quote{"USD": {Price: ptr(price), Volume24h: ptr(volume)}},
+		}
+	}
+
+	series, err := FromHistoricalQuotes(time.Hour, "usd", []cmc.HistoricalQuote{
+		point(50, 11, 2400),
+		point(5, 10, 2400),
+		point(30, 14, 2400),
+		point(70, 9, 4800),
+		{Timestamp: day.Add(90 * time.Minute), Quote: map[string]*cmc.Quote{"EUR": {Price: ptr(1.0)}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Candle{
+		{Time: day, Open: 10, High: 14, Low: 10, Close: 11, Volume: 100},
+		{Time: hour(1), Open: 9, High: 9, Low: 9, Close: 9, Volume: 200},
+	}
+	if !reflect.DeepEqual(series.Candles, want) {
+		t.Errorf("expected %+v, got %+v", want, series.Candles)
+	}
+}
+
+func TestFromOHLCVWithFakeServer(t *testing.T) {
+	fake := cmctest.NewServer()
+	defer fake.Close()
+	fake.SetFixture("/v2/cryptocurrency/ohlcv/historical", map[string][]map[string]any{
+		"1": {
+			{"time_open": "2024-01-01T00:00:00.000Z", "open": 42000, "high": 43000, "low": 41000, "close": 42500, "volume": 1e9},
+			{"time_open": "2024-01-02T00:00:00.000Z", "open": nil, "high": nil, "low": nil, "close": nil, "volume": nil},
+			{"time_open": "2024-01-03T00:00:00.000Z", "open": 42500, "high": 45000, "low": 42000, "close": 44000, "volume": 2e9},
+		},
+	})
+
+	client := fake.Client()
+	resp, err := client.GetCryptocurrencyOHLCVHistorical(context.Background(), &cmc.CryptocurrencyOHLCVHistoricalOptions{ID: []int{1}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	series, err := FromOHLCV(24*time.Hour, resp.Data["1"])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gaps := series.Gaps(); len(series.Candles) != 2 || len(gaps) != 1 || !gaps[0].Equal(day.Add(24*time.Hour)) {
+		t.Fatalf("expected the empty candle to become a gap, got %+v", series)
+	}
+	if filled := series.Fill(); filled.Candles[1].Close != 42500 || !filled.Candles[1].Filled {
+		t.Errorf("expected the gap to be filled forward, got %+v", filled.Candles[1])
+	}
+}
@@ -0,0 +1,189 @@
+// Package candles turns OHLCV data and historical quotes into dense, time-aligned
+// candle series that can be resampled and gap-filled:
+//
+//	resp, err := client.GetCryptocurrencyOHLCVHistorical(ctx, opts)
+//	series, err := candles.FromOHLCV(24*time.Hour, resp.Data["1"])
+//	weekly, err := series.Fill().Resample(7 * 24 * time.Hour)
+//
+// Candle times are aligned with time.Time.Truncate, which puts daily candles at
+// midnight UTC and weekly candles on Mondays. Calendar intervals such as months are
+// not supported.
+package candles
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// Candle is one interval of a series.
+type Candle struct {
+	Time      time.Time // the open time, aligned to the series interval
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    float64
+	MarketCap float64
+	Filled    bool // synthesized by Fill for a missing interval
+}
+
+// Duplicate is a candle time that occurred more than once in the input of Normalize.
+type Duplicate struct {
+	Time        time.Time
+	Count       int
+	Conflicting bool // the copies differ; the last one was kept
+}
+
+// Series is a sorted candle series without duplicate times. It may have gaps until
+// Fill is called.
+type Series struct {
+	Interval   time.Duration
+	Candles    []Candle
+	Duplicates []Duplicate // the duplicates Normalize merged
+}
+
+// Normalize aligns candles to interval, sorts them and merges duplicate times, keeping
+// the last copy and recording it in Duplicates.
+func Normalize(interval time.Duration, candles []Candle) (Series, error) {
+	if interval <= 0 {
+		return Series{}, fmt.Errorf("candles: interval must be positive, got %v", interval)
+	}
+
+	sorted := make([]Candle, len(candles))
+	for i, c := range candles {
+		c.Time = align(c.Time, interval)
+		sorted[i] = c
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	series := Series{Interval: interval, Candles: make([]Candle, 0, len(sorted))}
+	for _, c := range sorted {
+		last := len(series.Candles) - 1
+		if last < 0 || !series.Candles[last].Time.Equal(c.Time) {
+			series.Candles = append(series.Candles, c)
+			continue
+		}
+
+		if n := len(series.Duplicates) - 1; n >= 0 && series.Duplicates[n].Time.Equal(c.Time) {
+			series.Duplicates[n].Count++
+			series.Duplicates[n].Conflicting = series.Duplicates[n].Conflicting || series.Candles[last] != c
+		} else {
+			series.Duplicates = append(series.Duplicates, Duplicate{Time: c.Time, Count: 2, Conflicting: series.Candles[last] != c})
+		}
+		series.Candles[last] = c
+	}
+	return series, nil
+}
+
+// Resample aggregates the series into a coarser interval, which must be a multiple of
+// the series interval: the first open, highest high, lowest low, last close, summed
+// volume and last market cap. A resampled candle is Filled only if all its parts were.
+func (s Series) Resample(interval time.Duration) (Series, error) {
+	if interval <= 0 || s.Interval <= 0 || interval%s.Interval != 0 {
+		return Series{}, fmt.Errorf("candles: cannot resample %v candles to %v", s.Interval, interval)
+	}
+
+	resampled := Series{Interval: interval}
+	for _, c := range s.Candles {
+		c.Time = align(c.Time, interval)
+		last := len(resampled.Candles) - 1
+		if last < 0 || !resampled.Candles[last].Time.Equal(c.Time) {
+			resampled.Candles = append(resampled.Candles, c)
+			continue
+		}
+
+		bucket := &resampled.Candles[last]
+		bucket.High = max(bucket.High, c.High)
+		bucket.Low = min(bucket.Low, c.Low)
+		bucket.Close = c.Close
+		bucket.Volume += c.Volume
+		bucket.MarketCap = c.MarketCap
+		bucket.Filled = bucket.Filled && c.Filled
+	}
+	return resampled, nil
+}
+
+// Fill returns the series with every gap filled forward: a missing interval becomes a
+// flat, Filled candle at the previous close with no volume.
+func (s Series) Fill() Series {
+	filled := Series{Interval: s.Interval, Duplicates: s.Duplicates}
+	for _, c := range s.Candles {
+		if n := len(filled.Candles); n > 0 {
+			prev := filled.Candles[n-1]
+			for t := prev.Time.Add(s.Interval); t.Before(c.Time); t = t.Add(s.Interval) {
+				filled.Candles = append(filled.Candles, Candle{
+					Time:      t,
+					Open:      prev.Close,
+					High:      prev.Close,
+					Low:       prev.Close,
+					Close:     prev.Close,
+					MarketCap: prev.MarketCap,
+					Filled:    true,
+				})
+			}
+		}
+		filled.Candles = append(filled.Candles, c)
+	}
+	return filled
+}
+
+// Gaps returns the open times of the intervals missing between the first and last candle.
+func (s Series) Gaps() []time.Time {
+	var gaps []time.Time
+	for i := 1; i < len(s.Candles); i++ {
+		for t := s.Candles[i-1].Time.Add(s.Interval); t.Before(s.Candles[i].Time); t = t.Add(s.Interval) {
+			gaps = append(gaps, t)
+		}
+	}
+	return gaps
+}
+
+// At returns the candle that opens at t, aligned to the series interval.
+func (s Series) At(t time.Time) (Candle, bool) {
+	t = align(t, s.Interval)
+	i := sort.Search(len(s.Candles), func(i int) bool { return !s.Candles[i].Time.Before(t) })
+	if i < len(s.Candles) && s.Candles[i].Time.Equal(t) {
+		return s.Candles[i], true
+	}
+	return Candle{}, false
+}
+
+// Closes returns the close of every candle.
+func (s Series) Closes() []float64 {
+	closes := make([]float64, len(s.Candles))
+	for i, c := range s.Candles {
+		closes[i] = c.Close
+	}
+	return closes
+}
+
+// Duration returns the length of an API interval such as "5m", "4h", "7d" or "daily".
+// Calendar intervals ("monthly", "yearly") have no fixed length and return false.
+func Duration(interval cmc.Interval) (time.Duration, bool) {
+	switch interval {
+	case cmc.IntervalHourly:
+		return time.Hour, true
+	case cmc.IntervalDaily:
+		return 24 * time.Hour, true
+	case cmc.IntervalWeekly:
+		return 7 * 24 * time.Hour, true
+	}
+
+	s := string(interval)
+	if n, err := strconv.Atoi(strings.TrimSuffix(s, "d")); err == nil && strings.HasSuffix(s, "d") && n > 0 {
+		return time.Duration(n) * 24 * time.Hour, true
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 && (strings.HasSuffix(s, "m") || strings.HasSuffix(s, "h")) {
+		return d, true
+	}
+	return 0, false
+}
+
+func align(t time.Time, interval time.Duration) time.Time {
+	return t.UTC().Truncate(interval)
+}
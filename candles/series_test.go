@@ -0,0 +1,105 @@
+package candles
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+var day = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func hour(n int) time.Time { return day.Add(time.Duration(n) * time.Hour) }
+
+func TestNormalize(t *testing.T) {
+	series, err := Normalize(time.Hour, []Candle{
+		{Time: hour(2).Add(30 * time.Minute), Close: 3},
+		{Time: hour(0), Close: 1},
+		{Time: hour(2), Close: 2},
+		{Time: hour(1).In(time.FixedZone("CET", 3600)), Close: 4},
+		{Time: hour(1), Close: 4},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := series.Closes(); !reflect.DeepEqual(got, []float64{1, 4, 2}) {
+		t.Errorf("expected sorted candles keeping the last duplicate, got %v", got)
+	}
+	want := []Duplicate{
+		{Time: hour(1), Count: 2, Conflicting: false},
+		{Time: hour(2), Count: 2, Conflicting: true},
+	}
+	if !reflect.DeepEqual(series.Duplicates, want) {
+		t.Errorf("expected duplicates %+v, got %+v", want, series.Duplicates)
+	}
+
+	if _, err := Normalize(0, nil); err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+}
+
+func TestResampleAndFill(t *testing.T) {
+	series, err := Normalize(time.Hour, []Candle{
+		{Time: hour(0), Open: 10, High: 12, Low: 9, Close: 11, Volume: 1, MarketCap: 100},
+		{Time: hour(1), Open: 11, High: 15, Low: 10, Close: 14, Volume: 2, MarketCap: 140},
+		{Time: hour(4), Open: 13, High: 13, Low: 7, Close: 8, Volume: 4, MarketCap: 80},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gaps := series.Gaps(); !reflect.DeepEqual(gaps, []time.Time{hour(2), hour(3)}) {
+		t.Errorf("unexpected gaps %v", gaps)
+	}
+
+	filled := series.Fill()
+	if len(filled.Candles) != 5 || len(filled.Gaps()) != 0 {
+		t.Fatalf("expected 5 dense candles, got %+v", filled.Candles)
+	}
+	flat := Candle{Time: hour(2), Open: 14, High: 14, Low: 14, Close: 14, MarketCap: 140, Filled: true}
+	if filled.Candles[2] != flat {
+		t.Errorf("expected a flat filled candle, got %+v", filled.Candles[2])
+	}
+
+	resampled, err := filled.Resample(2 * time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Candle{
+		{Time: hour(0), Open: 10, High: 15, Low: 9, Close: 14, Volume: 3, MarketCap: 140},
+		{Time: hour(2), Open: 14, High: 14, Low: 14, Close: 14, MarketCap: 140, Filled: true},
+		{Time: hour(4), Open: 13, High: 13, Low: 7, Close: 8, Volume: 4, MarketCap: 80},
+	}
+	if !reflect.DeepEqual(resampled.Candles, want) {
+		t.Errorf("expected %+v, got %+v", want, resampled.Candles)
+	}
+	if c, ok := resampled.At(hour(5)); !ok || c.Close != 8 {
+		t.Errorf("expected At to find the candle containing hour 5, got %+v", c)
+	}
+
+	if _, err := series.Resample(90 * time.Minute); err == nil {
+		t.Error("expected an error for an interval that is not a multiple")
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		interval cmc.Interval
+		want     time.Duration
+		ok       bool
+	}{
+		{cmc.Interval5m, 5 * time.Minute, true},
+		{cmc.Interval4h, 4 * time.Hour, true},
+		{cmc.Interval7d, 7 * 24 * time.Hour, true},
+		{cmc.IntervalDaily, 24 * time.Hour, true},
+		{cmc.IntervalMonthly, 0, false},
+		{"1s", 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := Duration(tt.interval); got != tt.want || ok != tt.ok {
+			t.Errorf("Duration(%q): expected %v %v, got %v %v", tt.interval, tt.want, tt.ok, got, ok)
+		}
+	}
+}
@@ -5,12 +5,14 @@ import (
 	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/time/rate"
@@ -26,14 +28,31 @@ const (
 	MaxRetries        = 3
 )
 
+// apiKeyHeader carries the API key on every request.
+const apiKeyHeader = "X-CMC_PRO_API_KEY"
+
 // ClientConfig holds configuration options for the CoinMarketCap client.
 type ClientConfig struct {
-	APIKey     string
-	BaseURL    string
-	HTTPClient *http.Client
-	RateLimit  rate.Limit
-	Sandbox    bool
-	UserAgent  string
+	APIKey      string
+	APIKeys     []string
+	KeyPool     *KeyPool
+	BaseURL     string
+	HTTPClient  *http.Client
+	RateLimit   rate.Limit
+	Sandbox     bool
+	UserAgent   string
+	Cache       Cache
+	CacheTTLs   map[string]time.Duration
+	BatchSize   int
+	RetryPolicy RetryPolicy
+	Middleware  []Middleware
+	Observers   []Observer
+
+	DailyCreditBudget   int
+	MonthlyCreditBudget int
+
+	Plan          Plan
+	EndpointPlans map[string]Plan
 }
 
 // Client represents a CoinMarketCap API client with rate limiting and retry capabilities.
@@ -43,6 +62,17 @@ type Client struct {
 	httpClient  *http.Client
 	rateLimiter *rate.Limiter
 	userAgent   string
+	cache       Cache
+	cacheTTLs   map[string]time.Duration
+	batchSize   int
+	retryPolicy RetryPolicy
+	credits     *CreditMeter
+	keys        *KeyPool
+	observers   []Observer
+	handler     Handler
+
+	plan          atomic.Int32
+	endpointPlans map[string]Plan
 }
 
 // Option represents a functional option for configuring the Client.
@@ -97,10 +127,12 @@ func WithUserAgent(userAgent string) Option {
 // If no API key is provided, requests will fail with authentication errors.
 func NewClient(opts ...Option) *Client {
 	config := &ClientConfig{
-		BaseURL:    DefaultBaseURL,
-		HTTPClient: &http.Client{Timeout: DefaultTimeout},
-		RateLimit:  rate.Limit(DefaultRateLimit) / 60, // convert per-minute to per-second
-		UserAgent:  "go-coinmarketcap/1.0",
+		BaseURL:     DefaultBaseURL,
+		HTTPClient:  &http.Client{Timeout: DefaultTimeout},
+		RateLimit:   rate.Limit(DefaultRateLimit) / 60, // convert per-minute to per-second
+		UserAgent:   "go-coinmarketcap/1.0",
+		BatchSize:   DefaultBatchSize,
+		RetryPolicy: DefaultRetryPolicy(),
 	}
 
 	for _, opt := range opts {
@@ -111,13 +143,37 @@ func NewClient(opts ...Option) *Client {
 		config.BaseURL = SandboxBaseURL
 	}
 
-	return &Client{
+	if config.KeyPool == nil && len(config.APIKeys) > 0 {
+		keys := make([]PooledKey, len(config.APIKeys))
+		for i, key := range config.APIKeys {
+			keys[i] = PooledKey{Key: key}
+		}
+		config.KeyPool = NewKeyPool(RoundRobin, keys...)
+	}
+	if config.KeyPool != nil {
+		config.KeyPool.init(config.RateLimit)
+	}
+
+	c := &Client{
 		apiKey:      config.APIKey,
 		baseURL:     config.BaseURL,
 		httpClient:  config.HTTPClient,
 		rateLimiter: rate.NewLimiter(config.RateLimit, 1),
 		userAgent:   config.UserAgent,
+		cache:       config.Cache,
+		cacheTTLs:   config.CacheTTLs,
+		batchSize:   config.BatchSize,
+		retryPolicy: config.RetryPolicy,
+		credits:     NewCreditMeter(config.DailyCreditBudget, config.MonthlyCreditBudget),
+		keys:        config.KeyPool,
+		observers:   config.Observers,
+
+		endpointPlans: config.EndpointPlans,
 	}
+	c.plan.Store(int32(config.Plan))
+	c.handler = chain(c.send, config.Middleware)
+
+	return c
 }
 
 // RequestOptions holds optional parameters for API requests.
@@ -127,16 +183,78 @@ type RequestOptions[T any] struct {
 }
 
 // doRequest performs the actual HTTP request with rate limiting, retries, and error handling.
-func (c *Client) doRequest(ctx context.Context, endpoint string, opts *RequestOptions[any]) (*http.Response, error) {
-	if err := c.rateLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limiter error: %w", err)
+// A fresh *http.Request is built for every attempt and backoff waits end early when ctx is done.
+// Attempts and time spent waiting are added to stats. With a key pool, each attempt picks a
+// key and an attempt whose key is taken out of rotation fails over to the next one without
+// counting as a retry; the key that served the response is returned.
+func (c *Client) doRequest(ctx context.Context, r *Request, stats *RequestStats) (*http.Response, *pooledKey, error) {
+	endpoint := r.Endpoint
+	reqURL := c.baseURL + endpoint
+	if len(r.Query) > 0 {
+		reqURL += "?" + r.Query.Encode()
 	}
 
-	reqURL := c.baseURL + endpoint
-	if opts != nil && opts.QueryParams != nil && len(opts.QueryParams) > 0 {
-		reqURL += "?" + opts.QueryParams.Encode()
+	policy := c.retryPolicy
+
+	for retries := 0; ; {
+		limiter, apiKey := c.rateLimiter, c.apiKey
+		var key *pooledKey
+		if c.keys != nil && r.Header.Get(apiKeyHeader) == "" {
+			var err error
+			if key, err = c.keys.acquire(); err != nil {
+				return nil, nil, err
+			}
+			limiter, apiKey = key.limiter, key.key
+		}
+
+		waitStart := time.Now()
+		err := limiter.Wait(ctx)
+		stats.RateLimitWait += time.Since(waitStart)
+		if err != nil {
+			return nil, nil, fmt.Errorf("rate limiter error: %w", err)
+		}
+
+		req, err := c.newRequest(ctx, reqURL, apiKey, r.Header)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		stats.Attempts++
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil || retries >= policy.MaxRetries {
+				return nil, nil, &TransportError{Endpoint: endpoint, Attempts: stats.Attempts, Op: OpRequest, Err: err}
+			}
+			if err := stats.backoff(ctx, policy.backoff(retries)); err != nil {
+				return nil, nil, err
+			}
+			retries++
+			continue
+		}
+		resp.Body = &countingReader{ReadCloser: resp.Body, n: &stats.BytesReceived}
+
+		if resp.StatusCode < 400 {
+			return resp, key, nil
+		}
+
+		apiErr := parseErrorResponse(resp)
+		delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if key != nil && c.keys.report(key, apiErr, delay) {
+			continue
+		}
+		if retries >= policy.MaxRetries || !policy.shouldRetry(apiErr) {
+			return nil, nil, apiErr
+		}
+
+		if err := stats.backoff(ctx, policy.retryDelay(retries, delay, ok)); err != nil {
+			return nil, nil, err
+		}
+		retries++
 	}
+}
 
+// newRequest builds a GET request with the standard headers, apiKey and any per-request overrides.
+func (c *Client) newRequest(ctx context.Context, reqURL, apiKey string, header http.Header) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -146,89 +264,182 @@ func (c *Client) doRequest(ctx context.Context, endpoint string, opts *RequestOp
 	req.Header.Set("Accept-Encoding", "gzip")
 	req.Header.Set("User-Agent", c.userAgent)
 
-	if c.apiKey != "" {
-		req.Header.Set("X-CMC_PRO_API_KEY", c.apiKey)
+	if apiKey != "" {
+		req.Header.Set(apiKeyHeader, apiKey)
 	}
 
-	if opts != nil && opts.Headers != nil {
-		for key, value := range opts.Headers {
-			req.Header.Set(key, value)
+	for key, values := range header {
+		req.Header.Del(key)
+		for _, value := range values {
+			req.Header.Add(key, value)
 		}
 	}
 
-	var resp *http.Response
-	var lastErr error
+	return req, nil
+}
+
+// parseErrorResponse reads and closes an HTTP error response, extracting the CMC error code when present.
+func parseErrorResponse(resp *http.Response) *APIError {
+	body, _ := getResponseBody(resp)
+	resp.Body.Close()
 
-	for attempt := 0; attempt <= MaxRetries; attempt++ {
-		resp, lastErr = c.httpClient.Do(req)
-		if lastErr != nil {
-			if attempt < MaxRetries {
-				time.Sleep(DefaultRetryDelay * time.Duration(attempt+1))
-				continue
-			}
-			return nil, fmt.Errorf("request failed after %d attempts: %w", MaxRetries+1, lastErr)
+	// Try to parse the error response to get the error code
+	var errorResp struct {
+		Status Status `json:"status"`
+	}
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    string(body),
+	}
+
+	if json.Unmarshal(body, &errorResp) == nil {
+		apiErr.ErrorCode = errorResp.Status.ErrorCode
+		if errorResp.Status.ErrorMessage != nil {
+			apiErr.Message = *errorResp.Status.ErrorMessage
 		}
+	}
 
-		if resp.StatusCode == http.StatusTooManyRequests && attempt < MaxRetries {
-			resp.Body.Close()
-			retryAfter := resp.Header.Get("Retry-After")
-			if retryAfter != "" {
-				if seconds, err := strconv.Atoi(retryAfter); err == nil {
-					time.Sleep(time.Duration(seconds) * time.Second)
-				} else {
-					time.Sleep(DefaultRetryDelay * time.Duration(attempt+1))
-				}
-			} else {
-				time.Sleep(DefaultRetryDelay * time.Duration(attempt+1))
-			}
-			continue
+	return apiErr
+}
+
+// getResponseBody reads and potentially decompresses the response body.
+// Failures are returned as a *TransportError with Op set to OpRead or OpDecompress.
+func getResponseBody(resp *http.Response) ([]byte, error) {
+	// Check if response is gzip compressed
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, &TransportError{Op: OpRead, Err: err}
 		}
+		return body, nil
+	}
 
-		break
+	gzReader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, &TransportError{Op: OpDecompress, Err: err}
 	}
+	defer gzReader.Close()
 
-	if resp.StatusCode >= 400 {
-		body, _ := getResponseBody(resp)
-		resp.Body.Close()
+	body, err := io.ReadAll(gzReader)
+	if err != nil {
+		return nil, &TransportError{Op: OpDecompress, Err: err}
+	}
+	return body, nil
+}
 
-		// Try to parse the error response to get the error code
-		var errorResp struct {
-			Status Status `json:"status"`
+// fetch runs a request for an endpoint through the middleware chain and returns the
+// undecoded response.
+func (c *Client) fetch(ctx context.Context, endpoint string, opts *RequestOptions[any]) (*Response, error) {
+	req := &Request{
+		Endpoint: endpoint,
+		Query:    make(url.Values),
+		Header:   make(http.Header),
+	}
+	if opts != nil {
+		for key, values := range opts.QueryParams {
+			req.Query[key] = append([]string(nil), values...)
 		}
+		for key, value := range opts.Headers {
+			req.Header.Set(key, value)
+		}
+	}
+
+	return c.handler(ctx, req)
+}
+
+// send is the innermost Handler. It reports each request to the configured observers.
+func (c *Client) send(ctx context.Context, req *Request) (*Response, error) {
+	stats := RequestStats{Endpoint: req.Endpoint, Start: time.Now()}
+	for _, observer := range c.observers {
+		ctx = observer.RequestStart(ctx, req.Endpoint)
+	}
 
-		apiErr := &APIError{
-			StatusCode: resp.StatusCode,
-			Message:    string(body),
+	resp, err := c.execute(ctx, req, &stats)
+
+	if len(c.observers) > 0 {
+		stats.finish(resp, err)
+		for _, observer := range c.observers {
+			observer.RequestEnd(ctx, stats)
 		}
+	}
+	return resp, err
+}
 
-		if json.Unmarshal(body, &errorResp) == nil {
-			apiErr.ErrorCode = errorResp.Status.ErrorCode
-			if errorResp.Status.ErrorMessage != nil {
-				apiErr.Message = *errorResp.Status.ErrorMessage
-			}
+// execute serves a request. Endpoints outside the client's plan fail without being sent.
+// When a cache is configured, fresh entries are served without touching the network and
+// successful responses are stored using the endpoint's TTL. Requests that reach the
+// network are checked against the credit budget and their credit cost is recorded.
+func (c *Client) execute(ctx context.Context, req *Request, stats *RequestStats) (*Response, error) {
+	endpoint := req.Endpoint
+
+	if err := c.checkPlan(endpoint); err != nil {
+		return nil, err
+	}
+
+	var cacheKey string
+	ttl := c.cacheTTL(endpoint)
+	if c.cache != nil && ttl > 0 {
+		cacheKey = CacheKey(endpoint, req.Query)
+		if body, ok := c.cache.Get(cacheKey); ok {
+			resp := &Response{StatusCode: http.StatusOK, Body: body, Cached: true}
+			resp.Status, _ = decodeStatus(body)
+			return resp, nil
 		}
+	}
 
-		return nil, apiErr
+	if !isCreditFree(endpoint) {
+		if err := c.credits.reserve(); err != nil {
+			return nil, err
+		}
+		defer c.credits.release()
 	}
 
-	return resp, nil
-}
+	resp, key, err := c.doRequest(ctx, req, stats)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-// getResponseBody reads and potentially decompresses the response body
-func getResponseBody(resp *http.Response) ([]byte, error) {
-	var reader io.ReadCloser = resp.Body
+	body, err := getResponseBody(resp)
+	if err != nil {
+		var transportErr *TransportError
+		if errors.As(err, &transportErr) {
+			transportErr.Endpoint = endpoint
+			transportErr.Attempts = stats.Attempts
+		}
+		return nil, err
+	}
 
-	// Check if response is gzip compressed
-	if resp.Header.Get("Content-Encoding") == "gzip" {
-		gzReader, err := gzip.NewReader(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+	status, ok := decodeStatus(body)
+	if ok {
+		c.credits.Record(endpoint, status.CreditCount)
+		if key != nil {
+			key.credits.Record(endpoint, status.CreditCount)
+		}
+		if cacheKey != "" && status.ErrorCode == 0 {
+			c.cache.Set(cacheKey, body, ttl)
 		}
-		defer gzReader.Close()
-		reader = gzReader
 	}
 
-	return io.ReadAll(reader)
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		Status:     status,
+		Attempts:   stats.Attempts,
+	}, nil
+}
+
+// decodeStatus extracts the status envelope from a response body.
+func decodeStatus(body []byte) (Status, bool) {
+	var envelope struct {
+		Status Status `json:"status"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return Status{}, false
+	}
+	return envelope.Status, true
 }
 
 // get performs a GET request to the specified endpoint and returns a typed response.
@@ -242,41 +453,43 @@ func get[T any](c *Client, ctx context.Context, endpoint string, opts *RequestOp
 		}
 	}
 
-	resp, err := c.doRequest(ctx, endpoint, reqOpts)
+	raw, err := c.fetch(ctx, endpoint, reqOpts)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	body, err := getResponseBody(resp)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
 
 	var apiResp APIResponse[T]
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := json.Unmarshal(raw.Body, &apiResp); err != nil {
+		return nil, raw.decodeError(endpoint, err)
 	}
 
 	if apiResp.Status.ErrorCode != 0 {
-		errorMsg := "API error"
-		if apiResp.Status.ErrorMessage != nil {
-			errorMsg = *apiResp.Status.ErrorMessage
-		}
-		return nil, &APIError{
-			StatusCode: resp.StatusCode,
-			ErrorCode:  apiResp.Status.ErrorCode,
-			Message:    errorMsg,
-		}
+		return nil, newStatusError(apiResp.Status, raw.StatusCode)
 	}
 
+	apiResp.cached = raw.Cached
 	return &apiResp, nil
 }
 
+// newStatusError builds the APIError for a response whose status reports an error code.
+func newStatusError(status Status, statusCode int) *APIError {
+	errorMsg := "API error"
+	if status.ErrorMessage != nil {
+		errorMsg = *status.ErrorMessage
+	}
+	return &APIError{
+		StatusCode: statusCode,
+		ErrorCode:  status.ErrorCode,
+		Message:    errorMsg,
+	}
+}
+
 // APIError represents an error response from the CoinMarketCap API.
+// Use errors.Is with ErrRateLimited, ErrInvalidAPIKey and the other sentinels to
+// check its category.
 type APIError struct {
 	StatusCode int
-	ErrorCode  int
+	ErrorCode  ErrorCode
 	Message    string
 }
 
@@ -290,27 +503,17 @@ func (e *APIError) Error() string {
 
 // IsRateLimit returns true if the error is due to rate limiting.
 func (e *APIError) IsRateLimit() bool {
-	return e.StatusCode == http.StatusTooManyRequests ||
-		e.ErrorCode == 1008 || // API_KEY_PLAN_MINUTE_RATE_LIMIT_REACHED
-		e.ErrorCode == 1009 || // API_KEY_PLAN_DAILY_RATE_LIMIT_REACHED
-		e.ErrorCode == 1010 || // API_KEY_PLAN_MONTHLY_RATE_LIMIT_REACHED
-		e.ErrorCode == 1011 // IP_RATE_LIMIT_REACHED
+	return e.StatusCode == http.StatusTooManyRequests || e.ErrorCode.IsRateLimit()
 }
 
 // IsAuthError returns true if the error is due to authentication issues.
 func (e *APIError) IsAuthError() bool {
-	return e.StatusCode == http.StatusUnauthorized ||
-		e.ErrorCode == 1001 || // API_KEY_INVALID
-		e.ErrorCode == 1002 || // API_KEY_MISSING
-		e.ErrorCode == 1005 || // API_KEY_REQUIRED
-		e.ErrorCode == 1007 // API_KEY_DISABLED
+	return e.StatusCode == http.StatusUnauthorized || e.ErrorCode.IsAuth()
 }
 
 // IsPaymentRequired returns true if the error requires payment or plan upgrade.
 func (e *APIError) IsPaymentRequired() bool {
-	return e.StatusCode == http.StatusPaymentRequired ||
-		e.ErrorCode == 1003 || // API_KEY_PLAN_REQUIRES_PAYMENT
-		e.ErrorCode == 1004 // API_KEY_PLAN_PAYMENT_EXPIRED
+	return e.StatusCode == http.StatusPaymentRequired || e.ErrorCode.IsPayment()
 }
 
 // ParamBuilder provides a fluent interface for building URL query parameters.
@@ -0,0 +1,236 @@
+// Package cmcalert evaluates alert rules over quotes and sends the alerts to sinks.
+//
+//	rules, err := cmcalert.LoadRules("alerts.json")
+//	engine, err := cmcalert.New(rules, cmcalert.WithSinks(cmcalert.LogSink(nil)))
+//
+//	watcher := cmcwatch.New(client, []int{1, 1027})
+//	go watcher.Run(ctx)
+//	engine.Watch(ctx, watcher.Updates())
+//
+// Rules can also be evaluated on quotes fetched by other means with Evaluate.
+package cmcalert
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+	"github.com/Davincible/go-coinmarketcap/cmcwatch"
+)
+
+// Alert is a fired rule.
+type Alert struct {
+	Rule    Rule
+	ID      int
+	Symbol  string
+	Convert string
+	Value   float64 // the value that met the threshold
+	Quote   cmc.Quote
+	Time    time.Time // the quote's LastUpdated, or when the rule was evaluated
+}
+
+// String describes the alert, e.g. "btc-100k: BTC/USD price 100250, at or above 100000".
+func (a Alert) String() string {
+	cond := conditions[a.Rule.Condition]
+	direction := "at or above"
+	if !cond.above {
+		direction = "at or below"
+	}
+	return fmt.Sprintf("%s: %s/%s %s %g, %s %g", a.Rule.Name, a.Symbol, a.Convert, cond.label, a.Value, direction, a.Rule.Threshold)
+}
+
+// Option configures an Engine.
+type Option func(*Engine)
+
+// WithSinks adds sinks that receive every alert, in order.
+func WithSinks(sinks ...Sink) Option {
+	return func(e *Engine) {
+		e.sinks = append(e.sinks, sinks...)
+	}
+}
+
+// WithErrorHandler receives the errors of sinks. By default they are dropped.
+func WithErrorHandler(fn func(error)) Option {
+	return func(e *Engine) {
+		e.onError = fn
+	}
+}
+
+// Engine evaluates rules and keeps, per rule, asset and currency, the state used for
+// hysteresis and cooldowns. It is safe for concurrent use.
+type Engine struct {
+	rules   []Rule
+	sinks   []Sink
+	onError func(error)
+	now     func() time.Time
+
+	mu    sync.Mutex
+	state map[stateKey]*ruleState
+}
+
+type stateKey struct {
+	rule    int
+	id      int
+	convert string
+}
+
+type ruleState struct {
+	disarmed bool
+	fired    time.Time
+	baseline float64 // for DominanceShift; NaN until the first quote
+}
+
+// New validates rules and creates an engine.
+func New(rules []Rule, opts ...Option) (*Engine, error) {
+	for _, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	e := &Engine{
+		rules:   append([]Rule(nil), rules...),
+		onError: func(error) {},
+		now:     time.Now,
+		state:   make(map[stateKey]*ruleState),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e, nil
+}
+
+// Rules returns the engine's rules.
+func (e *Engine) Rules() []Rule {
+	return append([]Rule(nil), e.rules...)
+}
+
+// Evaluate runs one poll cycle: it evaluates every rule on every quote of assets, sends
+// the alerts to the sinks and returns them.
+func (e *Engine) Evaluate(ctx context.Context, assets ...cmc.CryptocurrencyQuote) []Alert {
+	var alerts []Alert
+	for i := range assets {
+		alerts = append(alerts, e.check(&assets[i])...)
+	}
+	e.notify(ctx, alerts)
+	return alerts
+}
+
+// Watch evaluates the rules on every update until updates is closed, which returns nil,
+// or ctx is done, which returns ctx's error.
+func (e *Engine) Watch(ctx context.Context, updates <-chan cmcwatch.Update) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case update, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			asset := update.Asset
+			quote := update.Quote
+			asset.Quote = map[string]*cmc.Quote{update.Convert: &quote}
+			e.Evaluate(ctx, asset)
+		}
+	}
+}
+
+// Reset forgets all hysteresis, cooldown and baseline state.
+func (e *Engine) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.state = make(map[stateKey]*ruleState)
+}
+
+// check evaluates the rules on every quote of an asset.
+func (e *Engine) check(asset *cmc.CryptocurrencyQuote) []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	converts := make([]string, 0, len(asset.Quote))
+	for convert := range asset.Quote {
+		converts = append(converts, convert)
+	}
+	sort.Strings(converts)
+
+	now := e.now()
+	var alerts []Alert
+	for _, convert := range converts {
+		quote := asset.Quote[convert]
+		if quote == nil {
+			continue
+		}
+		for i, rule := range e.rules {
+			if !rule.matches(asset, convert) {
+				continue
+			}
+			if alert, ok := e.apply(i, rule, asset, convert, quote, now); ok {
+				alerts = append(alerts, alert)
+			}
+		}
+	}
+	return alerts
+}
+
+// apply evaluates one rule on one quote and updates its state.
+func (e *Engine) apply(i int, rule Rule, asset *cmc.CryptocurrencyQuote, convert string, quote *cmc.Quote, now time.Time) (Alert, bool) {
+	key := stateKey{i, asset.ID, convert}
+	state, ok := e.state[key]
+	if !ok {
+		state = &ruleState{baseline: math.NaN()}
+		e.state[key] = state
+	}
+
+	cond := conditions[rule.Condition]
+	value, ok := cond.value(quote, &state.baseline)
+	if !ok {
+		return Alert{}, false
+	}
+
+	met, rearm := value >= rule.Threshold, value < rule.Threshold-rule.Hysteresis
+	if !cond.above {
+		met, rearm = value <= rule.Threshold, value > rule.Threshold+rule.Hysteresis
+	}
+	if state.disarmed {
+		state.disarmed = !rearm
+		return Alert{}, false
+	}
+	if !met || (!state.fired.IsZero() && now.Sub(state.fired) < rule.Cooldown) {
+		return Alert{}, false
+	}
+
+	state.disarmed = true
+	state.fired = now
+	if rule.Condition == DominanceShift {
+		state.baseline = *quote.MarketCapDominance
+	}
+
+	alert := Alert{
+		Rule:    rule,
+		ID:      asset.ID,
+		Symbol:  asset.Symbol,
+		Convert: convert,
+		Value:   value,
+		Quote:   *quote,
+		Time:    now,
+	}
+	if quote.LastUpdated != nil {
+		alert.Time = *quote.LastUpdated
+	}
+	return alert, true
+}
+
+// notify sends alerts to every sink in order.
+func (e *Engine) notify(ctx context.Context, alerts []Alert) {
+	for _, alert := range alerts {
+		for _, sink := range e.sinks {
+			if err := sink.Notify(ctx, alert); err != nil {
+				e.onError(err)
+			}
+		}
+	}
+}
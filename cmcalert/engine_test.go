@@ -0,0 +1,165 @@
+package cmcalert
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+	"github.com/Davincible/go-coinmarketcap/cmctest"
+	"github.com/Davincible/go-coinmarketcap/cmcwatch"
+)
+
+func ptr(v float64) *float64 { return &v }
+
+func asset(id int, symbol string, quote cmc.Quote) cmc.CryptocurrencyQuote {
+	return cmc.CryptocurrencyQuote{ID: id, Symbol: symbol, Quote: map[string]*cmc.Quote{"USD": &quote}}
+}
+
+func TestEngineHysteresisAndCooldown(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	engine, err := New([]Rule{
+		{Name: "btc-high", Symbol: "btc", Condition: PriceAbove, Threshold: 100, Hysteresis: 5, Cooldown: 10 * time.Minute},
+		{Name: "dump", Condition: Change1hBelow, Threshold: -5},
+		{Name: "eur-only", Convert: "EUR", Condition: PriceAbove, Threshold: 0},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	engine.now = func() time.Time { return now }
+
+	steps := []struct {
+		advance time.Duration
+		price   float64
+		change  float64
+		want    []string
+	}{
+		{0, 90, 0, nil},
+		{time.Minute, 101, 0, []string{"btc-high"}},
+		{time.Minute, 99, -6, []string{"dump"}},           // within hysteresis, no flapping
+		{time.Minute, 102, -7, nil},                       // still disarmed
+		{time.Minute, 94, -4, nil},                        // rearms
+		{time.Minute, 103, -6, []string{"dump"}},          // armed but in cooldown
+		{10 * time.Minute, 104, -6, []string{"btc-high"}}, // cooldown over
+	}
+	for i, step := range steps {
+		now = now.Add(step.advance)
+		alerts := engine.Evaluate(context.Background(),
+			asset(1, "BTC", cmc.Quote{Price: ptr(step.price), PercentChange1h: ptr(step.change)}))
+
+		var names []string
+		for _, alert := range alerts {
+			names = append(names, alert.Rule.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(step.want, ",") {
+			t.Errorf("step %d: expected %v, got %v", i, step.want, names)
+		}
+	}
+}
+
+func TestEngineConditions(t *testing.T) {
+	engine, err := New([]Rule{
+		{Name: "dominance", ID: 1, Condition: DominanceShift, Threshold: 2},
+		{Name: "volume", Condition: VolumeSpike, Threshold: 150},
+		{Name: "swing", Condition: Change1hBeyond, Threshold: 3},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	evaluate := func(dominance, volumeChange, change float64) []Alert {
+		return engine.Evaluate(context.Background(), asset(1, "BTC", cmc.Quote{
+			MarketCapDominance: ptr(dominance),
+			VolumeChange24h:    ptr(volumeChange),
+			PercentChange1h:    ptr(change),
+		}))
+	}
+
+	if alerts := evaluate(50, 20, 1); len(alerts) != 0 {
+		t.Fatalf("expected the first quote to only set the baseline, got %v", alerts)
+	}
+	alerts := evaluate(47.5, 200, -3.5)
+	if len(alerts) != 3 {
+		t.Fatalf("expected all rules to fire, got %v", alerts)
+	}
+	if alerts[0].Value != 2.5 || alerts[2].Value != 3.5 {
+		t.Errorf("unexpected values %v and %v", alerts[0].Value, alerts[2].Value)
+	}
+	if got := alerts[0].String(); got != "dominance: BTC/USD dominance shift (points) 2.5, at or above 2" {
+		t.Errorf("unexpected message %q", got)
+	}
+
+	// The dominance baseline moves to 47.5 after firing.
+	if alerts := evaluate(46, 20, 0); len(alerts) != 0 {
+		t.Errorf("expected no alerts below the new baseline's threshold, got %v", alerts)
+	}
+	if alerts := evaluate(45, 20, 0); len(alerts) != 1 || alerts[0].Rule.Name != "dominance" {
+		t.Errorf("expected a shift from the new baseline, got %v", alerts)
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules(strings.NewReader(`[
+		{"name": "btc-100k", "symbol": "BTC", "condition": "price_above", "threshold": 100000, "hysteresis": 1000, "cooldown": "1h"},
+		{"name": "eth-dump", "id": 1027, "convert": "EUR", "condition": "change_1h_below", "threshold": -5}
+	]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Rule{Name: "btc-100k", Symbol: "BTC", Condition: PriceAbove, Threshold: 100000, Hysteresis: 1000, Cooldown: time.Hour}
+	if len(rules) != 2 || rules[0] != want || rules[1].ID != 1027 || rules[1].Convert != "EUR" {
+		t.Errorf("unexpected rules %+v", rules)
+	}
+
+	invalid := []string{
+		`[{"name": "x", "condition": "price_sideways"}]`,
+		`[{"name": "x", "condition": "price_above", "cooldown": "soon"}]`,
+		`[{"name": "x", "condition": "price_above", "hysteresis": -1}]`,
+		`[{"name": "x", "condition": "price_above", "threshhold": 1}]`,
+	}
+	for _, input := range invalid {
+		if _, err := ParseRules(strings.NewReader(input)); err == nil {
+			t.Errorf("expected an error for %s", input)
+		}
+	}
+	if _, err := New([]Rule{{Name: "x"}}); err == nil {
+		t.Error("expected New to reject a rule without a condition")
+	}
+}
+
+func TestEngineWatch(t *testing.T) {
+	fake := cmctest.NewServer()
+	defer fake.Close()
+
+	watcher := cmcwatch.New(fake.Client(), []int{1, 1027}, cmcwatch.WithInterval(10*time.Millisecond), cmcwatch.WithDelay(0))
+
+	alerts := make(chan Alert, 10)
+	engine, err := New([]Rule{{Name: "btc-high", Symbol: "BTC", Condition: PriceAbove, Threshold: 80000}},
+		WithSinks(ChannelSink(alerts)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Run(ctx)
+	done := make(chan error, 1)
+	go func() { done <- engine.Watch(ctx, watcher.Updates()) }()
+
+	fake.UpdateCoin("BTC", func(c *cmctest.Coin) { c.Price = 85000 })
+	select {
+	case alert := <-alerts:
+		if alert.ID != 1 || alert.Value != 85000 {
+			t.Errorf("unexpected alert %+v", alert)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected an alert")
+	}
+
+	cancel()
+	if err := <-done; err != nil && !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package cmcalert
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// Condition is what a rule watches for.
+type Condition string
+
+// Supported conditions. Thresholds are in the quote's currency for prices, in percent
+// for changes, and in percentage points for dominance.
+const (
+	// PriceAbove fires when Price is at or above the threshold.
+	PriceAbove Condition = "price_above"
+	// PriceBelow fires when Price is at or below the threshold.
+	PriceBelow Condition = "price_below"
+	// Change1hAbove fires when PercentChange1h is at or above the threshold.
+	Change1hAbove Condition = "change_1h_above"
+	// Change1hBelow fires when PercentChange1h is at or below the threshold, e.g. -5.
+	Change1hBelow Condition = "change_1h_below"
+	// Change1hBeyond fires when PercentChange1h moves the threshold or more either way.
+	Change1hBeyond Condition = "change_1h_beyond"
+	// DominanceShift fires when MarketCapDominance moved the threshold or more since
+	// the rule first saw the asset or last fired.
+	DominanceShift Condition = "dominance_shift"
+	// VolumeSpike fires when VolumeChange24h, the 24h volume change in percent, is at
+	// or above the threshold.
+	VolumeSpike Condition = "volume_spike"
+)
+
+// Rule declares an alert. A rule fires when its condition holds, then stays quiet until
+// the value moves Hysteresis back past the threshold, and never fires more often than
+// once per Cooldown for the same asset and currency. Rules start armed, so a condition
+// that already holds fires on the first evaluation.
+type Rule struct {
+	Name       string
+	ID         int    // asset ID; zero matches any asset
+	Symbol     string // asset symbol, ignoring case; empty matches any asset
+	Convert    string // quote currency, ignoring case; empty matches any currency
+	Condition  Condition
+	Threshold  float64
+	Hysteresis float64
+	Cooldown   time.Duration
+}
+
+// validate checks that the rule can be evaluated.
+func (r Rule) validate() error {
+	if _, ok := conditions[r.Condition]; !ok {
+		return fmt.Errorf("cmcalert: rule %q: unknown condition %q", r.Name, r.Condition)
+	}
+	if r.Hysteresis < 0 || r.Cooldown < 0 {
+		return fmt.Errorf("cmcalert: rule %q: hysteresis and cooldown must not be negative", r.Name)
+	}
+	return nil
+}
+
+// matches reports whether the rule applies to an asset's quote in convert.
+func (r Rule) matches(asset *cmc.CryptocurrencyQuote, convert string) bool {
+	return (r.ID == 0 || r.ID == asset.ID) &&
+		(r.Symbol == "" || strings.EqualFold(r.Symbol, asset.Symbol)) &&
+		(r.Convert == "" || strings.EqualFold(r.Convert, convert))
+}
+
+// condition describes how a Condition reads a quote. Rules with above fire at or above
+// the threshold and rearm below threshold-hysteresis; the others mirror that.
+type condition struct {
+	above bool
+	label string
+	value func(q *cmc.Quote, baseline *float64) (float64, bool)
+}
+
+var conditions = map[Condition]condition{
+	PriceAbove:     {above: true, label: "price", value: field(func(q *cmc.Quote) *float64 { return q.Price })},
+	PriceBelow:     {above: false, label: "price", value: field(func(q *cmc.Quote) *float64 { return q.Price })},
+	Change1hAbove:  {above: true, label: "1h change %", value: field(func(q *cmc.Quote) *float64 { return q.PercentChange1h })},
+	Change1hBelow:  {above: false, label: "1h change %", value: field(func(q *cmc.Quote) *float64 { return q.PercentChange1h })},
+	Change1hBeyond: {above: true, label: "1h change % (absolute)", value: absolute(field(func(q *cmc.Quote) *float64 { return q.PercentChange1h }))},
+	VolumeSpike:    {above: true, label: "24h volume change %", value: field(func(q *cmc.Quote) *float64 { return q.VolumeChange24h })},
+	DominanceShift: {above: true, label: "dominance shift (points)", value: shift(func(q *cmc.Quote) *float64 { return q.MarketCapDominance })},
+}
+
+func field(get func(*cmc.Quote) *float64) func(*cmc.Quote, *float64) (float64, bool) {
+	return func(q *cmc.Quote, _ *float64) (float64, bool) {
+		if v := get(q); v != nil {
+			return *v, true
+		}
+		return 0, false
+	}
+}
+
+func absolute(value func(*cmc.Quote, *float64) (float64, bool)) func(*cmc.Quote, *float64) (float64, bool) {
+	return func(q *cmc.Quote, baseline *float64) (float64, bool) {
+		v, ok := value(q, baseline)
+		return math.Abs(v), ok
+	}
+}
+
+// shift measures the distance from a baseline, which is set on first sight.
+func shift(get func(*cmc.Quote) *float64) func(*cmc.Quote, *float64) (float64, bool) {
+	return func(q *cmc.Quote, baseline *float64) (float64, bool) {
+		v := get(q)
+		if v == nil {
+			return 0, false
+		}
+		if math.IsNaN(*baseline) {
+			*baseline = *v
+		}
+		return math.Abs(*v - *baseline), true
+	}
+}
+
+// ruleFile is the JSON form of a Rule, with the cooldown as a duration string like "15m".
+type ruleFile struct {
+	Name       string    `json:"name"`
+	ID         int       `json:"id,omitempty"`
+	Symbol     string    `json:"symbol,omitempty"`
+	Convert    string    `json:"convert,omitempty"`
+	Condition  Condition `json:"condition"`
+	Threshold  float64   `json:"threshold"`
+	Hysteresis float64   `json:"hysteresis,omitempty"`
+	Cooldown   string    `json:"cooldown,omitempty"`
+}
+
+// ParseRules reads a JSON array of rules:
+//
+//	[{"name": "btc-100k", "symbol": "BTC", "condition": "price_above",
+//	  "threshold": 100000, "hysteresis": 1000, "cooldown": "1h"}]
+func ParseRules(r io.Reader) ([]Rule, error) {
+	var files []ruleFile
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&files); err != nil {
+		return nil, fmt.Errorf("cmcalert: decode rules: %w", err)
+	}
+
+	rules := make([]Rule, len(files))
+	for i, f := range files {
+		rules[i] = Rule{
+			Name:       f.Name,
+			ID:         f.ID,
+			Symbol:     f.Symbol,
+			Convert:    f.Convert,
+			Condition:  f.Condition,
+			Threshold:  f.Threshold,
+			Hysteresis: f.Hysteresis,
+		}
+		if f.Cooldown != "" {
+			cooldown, err := time.ParseDuration(f.Cooldown)
+			if err != nil {
+				return nil, fmt.Errorf("cmcalert: rule %q: cooldown: %w", f.Name, err)
+			}
+			rules[i].Cooldown = cooldown
+		}
+		if err := rules[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// LoadRules reads rules from a JSON file; see ParseRules.
+func LoadRules(path string) ([]Rule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cmcalert: %w", err)
+	}
+	defer f.Close()
+
+	return ParseRules(f)
+}
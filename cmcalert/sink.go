@@ -0,0 +1,105 @@
+package cmcalert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Sink receives alerts.
+type Sink interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(ctx context.Context, alert Alert) error
+
+// Notify calls f.
+func (f SinkFunc) Notify(ctx context.Context, alert Alert) error {
+	return f(ctx, alert)
+}
+
+// ChannelSink sends alerts on ch, blocking until they are received or ctx is done.
+func ChannelSink(ch chan<- Alert) Sink {
+	return SinkFunc(func(ctx context.Context, alert Alert) error {
+		select {
+		case ch <- alert:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// LogSink writes alerts to logger, or to the standard logger if nil.
+func LogSink(logger *log.Logger) Sink {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return SinkFunc(func(_ context.Context, alert Alert) error {
+		logger.Printf("alert %s", alert)
+		return nil
+	})
+}
+
+// WebhookPayload is the JSON body WebhookSink posts.
+type WebhookPayload struct {
+	Rule      string    `json:"rule"`
+	Condition Condition `json:"condition"`
+	Threshold float64   `json:"threshold"`
+	ID        int       `json:"id"`
+	Symbol    string    `json:"symbol"`
+	Convert   string    `json:"convert"`
+	Value     float64   `json:"value"`
+	Price     *float64  `json:"price,omitempty"`
+	Time      time.Time `json:"time"`
+	Message   string    `json:"message"`
+}
+
+// WebhookSink posts each alert to url as a WebhookPayload. Responses other than 2xx are
+// errors. A nil client uses http.DefaultClient.
+func WebhookSink(url string, client *http.Client) Sink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return SinkFunc(func(ctx context.Context, alert Alert) error {
+		body, err := json.Marshal(WebhookPayload{
+			Rule:      alert.Rule.Name,
+			Condition: alert.Rule.Condition,
+			Threshold: alert.Rule.Threshold,
+			ID:        alert.ID,
+			Symbol:    alert.Symbol,
+			Convert:   alert.Convert,
+			Value:     alert.Value,
+			Price:     alert.Quote.Price,
+			Time:      alert.Time,
+			Message:   alert.String(),
+		})
+		if err != nil {
+			return fmt.Errorf("cmcalert: encode webhook: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("cmcalert: webhook: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("cmcalert: webhook: %w", err)
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("cmcalert: webhook %s returned %s", url, resp.Status)
+		}
+		return nil
+	})
+}
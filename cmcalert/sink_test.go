@@ -0,0 +1,64 @@
+package cmcalert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+func TestSinks(t *testing.T) {
+	var payloads []WebhookPayload
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		payloads = append(payloads, payload)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	var errs []error
+	engine, err := New([]Rule{{Name: "btc-high", Condition: PriceAbove, Threshold: 100}},
+		WithSinks(WebhookSink(server.URL, nil), LogSink(log.New(&logs, "", 0))),
+		WithErrorHandler(func(err error) { errs = append(errs, err) }),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	engine.Evaluate(context.Background(), asset(1, "BTC", cmc.Quote{Price: ptr(120)}))
+	if len(payloads) != 1 || payloads[0].Rule != "btc-high" || payloads[0].Symbol != "BTC" || *payloads[0].Price != 120 {
+		t.Fatalf("unexpected payloads %+v", payloads)
+	}
+	if !strings.Contains(logs.String(), "alert btc-high: BTC/USD price 120") {
+		t.Errorf("unexpected log %q", logs.String())
+	}
+
+	status = http.StatusBadGateway
+	engine.Reset()
+	engine.Evaluate(context.Background(), asset(1, "BTC", cmc.Quote{Price: ptr(120)}))
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "502") {
+		t.Errorf("expected the webhook failure to be reported, got %v", errs)
+	}
+
+	// A full channel gives up when the context ends.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := ChannelSink(make(chan Alert)).Notify(ctx, Alert{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+// Package cmcobserve provides coinmarketcap.Observer implementations: a collector that
+// serves request metrics in the Prometheus text exposition format, and a span tracer.
+// Neither needs a Prometheus or OpenTelemetry dependency:
+//
+//	metrics := cmcobserve.NewPrometheusCollector()
+//	tracer := cmcobserve.NewTracer(func(span cmcobserve.Span) {
+//		log.Printf("%s took %v", span.Name, span.Duration())
+//	})
+//
+//	client := coinmarketcap.NewClient(
+//		coinmarketcap.WithAPIKey(os.Getenv("CMC_API_KEY")),
+//		coinmarketcap.WithObserver(metrics, tracer),
+//	)
+//
+//	http.Handle("/metrics", metrics)
+package cmcobserve
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// DefaultNamespace prefixes every metric name.
+const DefaultNamespace = "coinmarketcap"
+
+// DefaultBuckets are the histogram upper bounds, in seconds, for latencies and waits.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// CollectorOption configures a PrometheusCollector.
+type CollectorOption func(*PrometheusCollector)
+
+// WithNamespace replaces DefaultNamespace as the metric name prefix.
+func WithNamespace(namespace string) CollectorOption {
+	return func(c *PrometheusCollector) {
+		c.namespace = namespace
+	}
+}
+
+// WithBuckets sets the histogram upper bounds in seconds. They must be sorted.
+func WithBuckets(buckets ...float64) CollectorOption {
+	return func(c *PrometheusCollector) {
+		c.buckets = buckets
+	}
+}
+
+// PrometheusCollector aggregates RequestStats into counters and histograms labeled by
+// endpoint. It implements http.Handler to serve them. It is safe for concurrent use.
+type PrometheusCollector struct {
+	namespace string
+	buckets   []float64
+
+	mu       sync.Mutex
+	counters map[string]map[string]float64 // metric -> labels -> value
+	hists    map[string]map[string]*histogram
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// metric describes one exposed metric family.
+type metric struct {
+	name, help string
+	histogram  bool
+}
+
+var metrics = []metric{
+	{name: "requests_total", help: "API requests by endpoint, HTTP status code and CMC error code."},
+	{name: "request_duration_seconds", help: "Total request latency, including rate limiter waits and retries.", histogram: true},
+	{name: "rate_limit_wait_seconds", help: "Time spent waiting for the client rate limiter.", histogram: true},
+	{name: "backoff_wait_seconds_total", help: "Time spent sleeping between retries."},
+	{name: "retries_total", help: "Attempts made after the first one."},
+	{name: "response_bytes_total", help: "Response body bytes received before decompression."},
+	{name: "credits_total", help: "API credits charged, as reported by status.credit_count."},
+	{name: "cache_hits_total", help: "Requests served from the client cache."},
+	{name: "transport_errors_total", help: "Network, decompression and decoding failures by operation."},
+}
+
+// NewPrometheusCollector creates an empty collector.
+func NewPrometheusCollector(opts ...CollectorOption) *PrometheusCollector {
+	c := &PrometheusCollector{
+		namespace: DefaultNamespace,
+		buckets:   DefaultBuckets,
+		counters:  make(map[string]map[string]float64),
+		hists:     make(map[string]map[string]*histogram),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// RequestStart implements coinmarketcap.Observer.
+func (c *PrometheusCollector) RequestStart(ctx context.Context, endpoint string) context.Context {
+	return ctx
+}
+
+// RequestEnd implements coinmarketcap.Observer.
+func (c *PrometheusCollector) RequestEnd(ctx context.Context, stats cmc.RequestStats) {
+	endpoint := labels("endpoint", stats.Endpoint)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.add("requests_total", labels(
+		"code", strconv.Itoa(stats.StatusCode),
+		"endpoint", stats.Endpoint,
+		"error_code", strconv.Itoa(int(stats.ErrorCode)),
+	), 1)
+	c.observe("request_duration_seconds", endpoint, stats.Latency.Seconds())
+	c.observe("rate_limit_wait_seconds", endpoint, stats.RateLimitWait.Seconds())
+	c.add("backoff_wait_seconds_total", endpoint, stats.BackoffWait.Seconds())
+	c.add("retries_total", endpoint, float64(stats.Retries()))
+	c.add("response_bytes_total", endpoint, float64(stats.BytesReceived))
+	c.add("credits_total", endpoint, float64(stats.CreditCount))
+	if stats.Cached {
+		c.add("cache_hits_total", endpoint, 1)
+	}
+
+	var transportErr *cmc.TransportError
+	if errors.As(stats.Err, &transportErr) {
+		c.add("transport_errors_total", labels("endpoint", stats.Endpoint, "op", transportErr.Op), 1)
+	}
+}
+
+// add increments a counter. Callers must hold c.mu.
+func (c *PrometheusCollector) add(name, labels string, value float64) {
+	family := c.counters[name]
+	if family == nil {
+		family = make(map[string]float64)
+		c.counters[name] = family
+	}
+	family[labels] += value
+}
+
+// observe records a histogram sample. Callers must hold c.mu.
+func (c *PrometheusCollector) observe(name, labels string, value float64) {
+	family := c.hists[name]
+	if family == nil {
+		family = make(map[string]*histogram)
+		c.hists[name] = family
+	}
+	h := family[labels]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(c.buckets))}
+		family[labels] = h
+	}
+
+	for i, bound := range c.buckets {
+		if value <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format, version 0.0.4.
+func (c *PrometheusCollector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+	for _, m := range metrics {
+		name := c.namespace + "_" + m.name
+		if m.histogram {
+			c.writeHistogram(cw, name, m.help, c.hists[m.name])
+		} else {
+			writeCounter(cw, name, m.help, c.counters[m.name])
+		}
+	}
+
+	if err := bw.Flush(); err != nil && cw.err == nil {
+		cw.err = err
+	}
+	return cw.n, cw.err
+}
+
+// ServeHTTP serves the metrics for scraping.
+func (c *PrometheusCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+func writeCounter(w io.Writer, name, help string, family map[string]float64) {
+	if len(family) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	for _, key := range sortedKeys(family) {
+		fmt.Fprintf(w, "%s{%s} %s\n", name, key, formatFloat(family[key]))
+	}
+}
+
+func (c *PrometheusCollector) writeHistogram(w io.Writer, name, help string, family map[string]*histogram) {
+	if len(family) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	for _, key := range sortedKeys(family) {
+		h := family[key]
+		var cumulative uint64
+		for i, bound := range c.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "%s_bucket{%s,le=%q} %d\n", name, key, formatFloat(bound), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, key, h.count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", name, key, formatFloat(h.sum))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, key, h.count)
+	}
+}
+
+// labels renders name/value pairs as a Prometheus label set without braces.
+func labels(pairs ...string) string {
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, pairs[i]+`="`+labelEscaper.Replace(pairs[i+1])+`"`)
+	}
+	return strings.Join(parts, ",")
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// countingWriter tracks bytes written and the first error for WriteTo.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
@@ -0,0 +1,161 @@
+package cmcobserve
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+	"github.com/Davincible/go-coinmarketcap/cmctest"
+	"golang.org/x/time/rate"
+)
+
+func TestPrometheusExposition(t *testing.T) {
+	collector := NewPrometheusCollector(WithNamespace("cmc"), WithBuckets(0.1, 1))
+	ctx := context.Background()
+
+	collector.RequestEnd(ctx, cmc.RequestStats{
+		Endpoint:      "/v1/fiat/map",
+		StatusCode:    200,
+		Latency:       300 * time.Millisecond,
+		RateLimitWait: 50 * time.Millisecond,
+		Attempts:      2,
+		BackoffWait:   250 * time.Millisecond,
+		BytesReceived: 512,
+		CreditCount:   1,
+	})
+	collector.RequestEnd(ctx, cmc.RequestStats{
+		Endpoint:   "/v1/fiat/map",
+		StatusCode: 200,
+		Latency:    time.Millisecond,
+		Cached:     true,
+	})
+	collector.RequestEnd(ctx, cmc.RequestStats{
+		Endpoint:   "/v2/cryptocurrency/info",
+		StatusCode: 401,
+		ErrorCode:  cmc.ErrorCodeAPIKeyInvalid,
+		Latency:    2 * time.Second,
+		Attempts:   1,
+		Err:        errors.New("API error 1001"),
+	})
+	collector.RequestEnd(ctx, cmc.RequestStats{
+		Endpoint: "/v2/cryptocurrency/info",
+		Latency:  10 * time.Millisecond,
+		Attempts: 1,
+		Err:      &cmc.TransportError{Endpoint: "/v2/cryptocurrency/info", Op: cmc.OpDecode, Attempts: 1},
+	})
+
+	var b strings.Builder
+	if _, err := collector.WriteTo(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `# HELP cmc_requests_total API requests by endpoint, HTTP status code and CMC error code.
+# TYPE cmc_requests_total counter
+cmc_requests_total{code="0",endpoint="/v2/cryptocurrency/info",error_code="0"} 1
+cmc_requests_total{code="200",endpoint="/v1/fiat/map",error_code="0"} 2
+cmc_requests_total{code="401",endpoint="/v2/cryptocurrency/info",error_code="1001"} 1
+# HELP cmc_request_duration_seconds Total request latency, including rate limiter waits and retries.
+# TYPE cmc_request_duration_seconds histogram
+cmc_request_duration_seconds_bucket{endpoint="/v1/fiat/map",le="0.1"} 1
+cmc_request_duration_seconds_bucket{endpoint="/v1/fiat/map",le="1"} 2
+cmc_request_duration_seconds_bucket{endpoint="/v1/fiat/map",le="+Inf"} 2
+cmc_request_duration_seconds_sum{endpoint="/v1/fiat/map"} 0.301
+cmc_request_duration_seconds_count{endpoint="/v1/fiat/map"} 2
+cmc_request_duration_seconds_bucket{endpoint="/v2/cryptocurrency/info",le="0.1"} 1
+cmc_request_duration_seconds_bucket{endpoint="/v2/cryptocurrency/info",le="1"} 1
+cmc_request_duration_seconds_bucket{endpoint="/v2/cryptocurrency/info",le="+Inf"} 2
+cmc_request_duration_seconds_sum{endpoint="/v2/cryptocurrency/info"} 2.01
+cmc_request_duration_seconds_count{endpoint="/v2/cryptocurrency/info"} 2
+# HELP cmc_rate_limit_wait_seconds Time spent waiting for the client rate limiter.
+# TYPE cmc_rate_limit_wait_seconds histogram
+cmc_rate_limit_wait_seconds_bucket{endpoint="/v1/fiat/map",le="0.1"} 2
+cmc_rate_limit_wait_seconds_bucket{endpoint="/v1/fiat/map",le="1"} 2
+cmc_rate_limit_wait_seconds_bucket{endpoint="/v1/fiat/map",le="+Inf"} 2
+cmc_rate_limit_wait_seconds_sum{endpoint="/v1/fiat/map"} 0.05
+cmc_rate_limit_wait_seconds_count{endpoint="/v1/fiat/map"} 2
+cmc_rate_limit_wait_seconds_bucket{endpoint="/v2/cryptocurrency/info",le="0.1"} 2
+cmc_rate_limit_wait_seconds_bucket{endpoint="/v2/cryptocurrency/info",le="1"} 2
+cmc_rate_limit_wait_seconds_bucket{endpoint="/v2/cryptocurrency/info",le="+Inf"} 2
+cmc_rate_limit_wait_seconds_sum{endpoint="/v2/cryptocurrency/info"} 0
+cmc_rate_limit_wait_seconds_count{endpoint="/v2/cryptocurrency/info"} 2
+# HELP cmc_backoff_wait_seconds_total Time spent sleeping between retries.
+# TYPE cmc_backoff_wait_seconds_total counter
+cmc_backoff_wait_seconds_total{endpoint="/v1/fiat/map"} 0.25
+cmc_backoff_wait_seconds_total{endpoint="/v2/cryptocurrency/info"} 0
+# HELP cmc_retries_total Attempts made after the first one.
+# TYPE cmc_retries_total counter
+cmc_retries_total{endpoint="/v1/fiat/map"} 1
+cmc_retries_total{endpoint="/v2/cryptocurrency/info"} 0
+# HELP cmc_response_bytes_total Response body bytes received before decompression.
+# TYPE cmc_response_bytes_total counter
+cmc_response_bytes_total{endpoint="/v1/fiat/map"} 512
+cmc_response_bytes_total{endpoint="/v2/cryptocurrency/info"} 0
+# HELP cmc_credits_total API credits charged, as reported by status.credit_count.
+# TYPE cmc_credits_total counter
+cmc_credits_total{endpoint="/v1/fiat/map"} 1
+cmc_credits_total{endpoint="/v2/cryptocurrency/info"} 0
+# HELP cmc_cache_hits_total Requests served from the client cache.
+# TYPE cmc_cache_hits_total counter
+cmc_cache_hits_total{endpoint="/v1/fiat/map"} 1
+# HELP cmc_transport_errors_total Network, decompression and decoding failures by operation.
+# TYPE cmc_transport_errors_total counter
+cmc_transport_errors_total{endpoint="/v2/cryptocurrency/info",op="decode"} 1
+`
+	if b.String() != expected {
+		t.Errorf("unexpected exposition:\n%s", b.String())
+	}
+}
+
+func TestPrometheusCollectorWithClient(t *testing.T) {
+	fake := cmctest.NewServer()
+	defer fake.Close()
+
+	collector := NewPrometheusCollector()
+	client := cmc.NewClient(
+		cmc.WithAPIKey(cmctest.APIKey),
+		cmc.WithBaseURL(fake.URL),
+		cmc.WithRateLimit(rate.Limit(1000)),
+		cmc.WithRetryPolicy(cmc.RetryPolicy{
+			MaxRetries:           1,
+			BaseDelay:            time.Millisecond,
+			RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		}),
+		cmc.WithCache(cmc.NewMemoryCache(10)),
+		cmc.WithObserver(collector),
+	)
+	ctx := context.Background()
+
+	fake.FailNext("/v2/cryptocurrency/quotes/latest", http.StatusServiceUnavailable, cmc.ErrorCodeInternalServerError, "try again")
+	for i := 0; i < 2; i++ { // the second request is a cache hit and charges no credits
+		if _, err := client.GetCryptocurrencyQuotesLatest(ctx, &cmc.CryptocurrencyQuotesOptions{Symbol: []string{"BTC"}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	collector.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := recorder.Body.String()
+
+	if !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", recorder.Header().Get("Content-Type"))
+	}
+	for _, want := range []string{
+		`coinmarketcap_requests_total{code="200",endpoint="/v2/cryptocurrency/quotes/latest",error_code="0"} 2`,
+		`coinmarketcap_cache_hits_total{endpoint="/v2/cryptocurrency/quotes/latest"} 1`,
+		`coinmarketcap_retries_total{endpoint="/v2/cryptocurrency/quotes/latest"} 1`,
+		`coinmarketcap_credits_total{endpoint="/v2/cryptocurrency/quotes/latest"} 1`,
+		`coinmarketcap_request_duration_seconds_count{endpoint="/v2/cryptocurrency/quotes/latest"} 2`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected metrics to contain %s, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, `coinmarketcap_response_bytes_total{endpoint="/v2/cryptocurrency/quotes/latest"} 0`) {
+		t.Error("expected response bytes to be counted")
+	}
+}
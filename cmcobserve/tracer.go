@@ -0,0 +1,124 @@
+package cmcobserve
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// Span is a finished unit of work. Attribute names follow the OpenTelemetry HTTP
+// conventions where one exists and use a cmc. prefix otherwise.
+type Span struct {
+	TraceID    string
+	SpanID     string
+	ParentID   string // empty for root spans
+	Name       string
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]any
+	Err        error
+}
+
+// Duration returns how long the span lasted.
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Tracer records a span for every API request. Requests made inside a span started
+// with StartSpan become its children. It is safe for concurrent use.
+type Tracer struct {
+	export func(Span)
+
+	mu    sync.Mutex
+	spans []Span
+}
+
+// NewTracer creates a tracer that passes finished spans to export. With a nil export
+// function, spans are kept in memory and returned by Spans.
+func NewTracer(export func(Span)) *Tracer {
+	return &Tracer{export: export}
+}
+
+// spanKey stores the active span of a tracer in a context.
+type spanKey struct{ t *Tracer }
+
+// StartSpan starts a span named name, as a child of the active span in ctx if any.
+// Call end, with the operation's error or nil, when the work is done.
+func (t *Tracer) StartSpan(ctx context.Context, name string) (_ context.Context, end func(err error)) {
+	span := &Span{
+		SpanID:     newID(8),
+		Name:       name,
+		Start:      time.Now(),
+		Attributes: make(map[string]any),
+	}
+	if parent, ok := ctx.Value(spanKey{t}).(*Span); ok {
+		span.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+	} else {
+		span.TraceID = newID(16)
+	}
+
+	return context.WithValue(ctx, spanKey{t}, span), func(err error) {
+		span.End = time.Now()
+		span.Err = err
+		t.finish(*span)
+	}
+}
+
+// RequestStart implements coinmarketcap.Observer.
+func (t *Tracer) RequestStart(ctx context.Context, endpoint string) context.Context {
+	ctx, _ = t.StartSpan(ctx, "GET "+endpoint)
+	return ctx
+}
+
+// RequestEnd implements coinmarketcap.Observer.
+func (t *Tracer) RequestEnd(ctx context.Context, stats cmc.RequestStats) {
+	span, ok := ctx.Value(spanKey{t}).(*Span)
+	if !ok {
+		return
+	}
+
+	span.End = time.Now()
+	span.Err = stats.Err
+	span.Attributes["http.request.method"] = "GET"
+	span.Attributes["url.path"] = stats.Endpoint
+	span.Attributes["http.response.status_code"] = stats.StatusCode
+	span.Attributes["cmc.error_code"] = int(stats.ErrorCode)
+	span.Attributes["cmc.credit_count"] = stats.CreditCount
+	span.Attributes["cmc.attempts"] = stats.Attempts
+	span.Attributes["cmc.retries"] = stats.Retries()
+	span.Attributes["cmc.rate_limit_wait"] = stats.RateLimitWait
+	span.Attributes["cmc.backoff_wait"] = stats.BackoffWait
+	span.Attributes["cmc.response_bytes"] = stats.BytesReceived
+	span.Attributes["cmc.cached"] = stats.Cached
+	t.finish(*span)
+}
+
+// Spans returns the spans finished so far when the tracer has no export function.
+func (t *Tracer) Spans() []Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Span(nil), t.spans...)
+}
+
+func (t *Tracer) finish(span Span) {
+	if t.export != nil {
+		t.export(span)
+		return
+	}
+
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+}
+
+// newID returns n random bytes as hex, the format used for trace and span IDs.
+func newID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,77 @@
+package cmcobserve
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+	"github.com/Davincible/go-coinmarketcap/cmctest"
+)
+
+func TestTracerSpans(t *testing.T) {
+	fake := cmctest.NewServer()
+	defer fake.Close()
+
+	tracer := NewTracer(nil)
+	client := fake.Client(cmc.WithObserver(tracer))
+
+	ctx, end := tracer.StartSpan(context.Background(), "refresh portfolio")
+	if _, err := client.GetFiatMap(ctx, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err := client.GetExchangeInfo(ctx, &cmc.ExchangeInfoOptions{Slug: []string{"nope"}})
+	end(err)
+
+	spans := tracer.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+
+	fiat, info, root := spans[0], spans[1], spans[2]
+	if root.Name != "refresh portfolio" || root.ParentID != "" || root.Err == nil {
+		t.Errorf("unexpected root span %+v", root)
+	}
+	for _, span := range []Span{fiat, info} {
+		if span.TraceID != root.TraceID || span.ParentID != root.SpanID {
+			t.Errorf("expected %s to be a child of the root span", span.Name)
+		}
+		if span.End.Before(span.Start) || span.Duration() <= 0 {
+			t.Errorf("expected %s to have a positive duration", span.Name)
+		}
+	}
+
+	if fiat.Name != "GET /v1/fiat/map" || fiat.Err != nil {
+		t.Errorf("unexpected fiat span %+v", fiat)
+	}
+	if fiat.Attributes["http.response.status_code"] != 200 || fiat.Attributes["cmc.attempts"] != 1 {
+		t.Errorf("unexpected fiat attributes %v", fiat.Attributes)
+	}
+	if _, ok := fiat.Attributes["cmc.rate_limit_wait"]; !ok {
+		t.Error("expected the rate limiter wait to be recorded")
+	}
+
+	if !errors.Is(info.Err, cmc.ErrBadRequest) || info.Attributes["cmc.error_code"] != int(cmc.ErrorCodeBadRequest) {
+		t.Errorf("expected the failed request to be recorded, got %v and %v", info.Err, info.Attributes)
+	}
+}
+
+func TestTracerExport(t *testing.T) {
+	var exported []Span
+	tracer := NewTracer(func(span Span) { exported = append(exported, span) })
+
+	ctx, end := tracer.StartSpan(context.Background(), "outer")
+	_, endInner := tracer.StartSpan(ctx, "inner")
+	endInner(nil)
+	end(nil)
+
+	if len(exported) != 2 || exported[0].Name != "inner" || exported[1].Name != "outer" {
+		t.Fatalf("unexpected spans %+v", exported)
+	}
+	if len(tracer.Spans()) != 0 {
+		t.Error("expected exported spans not to be kept")
+	}
+	if len(exported[0].TraceID) != 32 || len(exported[0].SpanID) != 16 {
+		t.Errorf("unexpected ID lengths in %+v", exported[0])
+	}
+}
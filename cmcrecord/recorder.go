@@ -0,0 +1,330 @@
+// Package cmcrecord provides an http.RoundTripper that records CoinMarketCap API
+// traffic to a cassette file and replays it, so integration-style tests can run
+// deterministically without network access:
+//
+//	mode, _ := cmcrecord.ParseMode(os.Getenv("CMC_RECORD_MODE"))
+//	rec, err := cmcrecord.New("testdata/quotes.json", mode)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//
+//	client := coinmarketcap.NewClient(
+//		coinmarketcap.WithAPIKey(os.Getenv("CMC_API_KEY")),
+//		coinmarketcap.WithHTTPClient(rec.Client()),
+//	)
+//
+// Interactions are matched on endpoint path and canonical query, so the base URL and
+// parameter order do not matter. API keys are never written to cassettes.
+package cmcrecord
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// Mode selects how a Recorder handles requests.
+type Mode int
+
+const (
+	// Passthrough forwards requests without recording or replaying them.
+	Passthrough Mode = iota
+	// Record forwards requests and writes every interaction to the cassette,
+	// replacing any interactions recorded earlier.
+	Record
+	// Replay serves recorded interactions and never touches the network.
+	Replay
+)
+
+// String returns the mode name accepted by ParseMode.
+func (m Mode) String() string {
+	switch m {
+	case Record:
+		return "record"
+	case Replay:
+		return "replay"
+	default:
+		return "passthrough"
+	}
+}
+
+// ParseMode parses "record", "replay" or "passthrough". An empty string selects Replay,
+// which is the safe default for CI.
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "replay":
+		return Replay, nil
+	case "record":
+		return Record, nil
+	case "passthrough":
+		return Passthrough, nil
+	default:
+		return Replay, fmt.Errorf("cmcrecord: unknown mode %q", s)
+	}
+}
+
+// ErrInteractionNotFound is matched by errors.Is when replay finds no recorded
+// interaction for a request.
+var ErrInteractionNotFound = errors.New("cmcrecord: interaction not found")
+
+// apiKeyParam is the query parameter CoinMarketCap accepts as an alternative to the header.
+const apiKeyParam = "CMC_PRO_API_KEY"
+
+// skippedHeaders are response headers that are not written to cassettes.
+var skippedHeaders = map[string]bool{
+	"Content-Encoding": true,
+	"Content-Length":   true,
+	"Date":             true,
+	"Set-Cookie":       true,
+}
+
+// Interaction is a recorded request and its response. Bodies are stored decompressed.
+type Interaction struct {
+	Endpoint   string              `json:"endpoint"`
+	Query      string              `json:"query,omitempty"`
+	StatusCode int                 `json:"status_code"`
+	Header     map[string][]string `json:"header,omitempty"`
+	Body       json.RawMessage     `json:"body,omitempty"`
+	RawBody    string              `json:"raw_body,omitempty"` // set instead of Body when the body is not JSON
+}
+
+type cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithTransport sets the transport used to reach the network in Record and Passthrough
+// modes. It defaults to http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// Recorder is an http.RoundTripper that records, replays or passes through requests.
+// It is safe for concurrent use.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	served       map[string]int
+}
+
+// New creates a Recorder for the cassette at path. In Replay mode the cassette must exist.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		served:    make(map[string]int),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if mode == Replay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cmcrecord: failed to read cassette: %w", err)
+		}
+		var c cassette
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("cmcrecord: failed to parse cassette %s: %w", path, err)
+		}
+		r.interactions = c.Interactions
+	}
+
+	return r, nil
+}
+
+// Mode returns the recorder's mode.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an *http.Client that uses the recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns the interactions loaded or recorded so far.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch r.mode {
+	case Replay:
+		return r.replay(req)
+	case Record:
+		return r.record(req)
+	default:
+		return r.transport.RoundTrip(req)
+	}
+}
+
+// matchKey returns the endpoint and canonical query of a request, without the API key.
+func matchKey(u *url.URL) (endpoint, query string) {
+	params := u.Query()
+	params.Del(apiKeyParam)
+
+	key := cmc.CacheKey(u.Path, params)
+	endpoint, query, _ = strings.Cut(key, "?")
+	return endpoint, query
+}
+
+// replay serves the recorded interactions for a request in order, repeating the last one
+// once they are used up.
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	endpoint, query := matchKey(req.URL)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var matches []Interaction
+	for _, interaction := range r.interactions {
+		if interaction.Endpoint == endpoint && interaction.Query == query {
+			matches = append(matches, interaction)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: %s?%s", ErrInteractionNotFound, endpoint, query)
+	}
+
+	key := endpoint + "?" + query
+	index := r.served[key]
+	if index >= len(matches) {
+		index = len(matches) - 1
+	}
+	r.served[key]++
+
+	return matches[index].response(req), nil
+}
+
+// record forwards a request and appends the decompressed response to the cassette.
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := readBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("cmcrecord: failed to read response: %w", err)
+	}
+
+	endpoint, query := matchKey(req.URL)
+	interaction := Interaction{
+		Endpoint:   endpoint,
+		Query:      query,
+		StatusCode: resp.StatusCode,
+		Header:     make(map[string][]string),
+	}
+	for name, values := range resp.Header {
+		if !skippedHeaders[http.CanonicalHeaderKey(name)] {
+			interaction.Header[name] = values
+		}
+	}
+	if json.Valid(body) {
+		interaction.Body = json.RawMessage(body)
+	} else {
+		interaction.RawBody = string(body)
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	err = r.save()
+	r.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return interaction.response(req), nil
+}
+
+// save writes the cassette atomically. Callers must hold r.mu.
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(cassette{Interactions: r.interactions}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cmcrecord: failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("cmcrecord: failed to create cassette directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), ".cassette-*")
+	if err != nil {
+		return fmt.Errorf("cmcrecord: failed to write cassette: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("cmcrecord: failed to write cassette: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("cmcrecord: failed to write cassette: %w", err)
+	}
+	return os.Rename(tmp.Name(), r.path)
+}
+
+// response builds an HTTP response for an interaction.
+func (i Interaction) response(req *http.Request) *http.Response {
+	body := []byte(i.RawBody)
+	if len(i.Body) > 0 {
+		body = i.Body
+	}
+
+	header := make(http.Header, len(i.Header))
+	for name, values := range i.Header {
+		header[name] = append([]string(nil), values...)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.StatusCode, http.StatusText(i.StatusCode)),
+		StatusCode:    i.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// readBody reads a response body, decompressing gzip content.
+func readBody(resp *http.Response) ([]byte, error) {
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		return io.ReadAll(resp.Body)
+	}
+
+	reader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
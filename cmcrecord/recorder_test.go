@@ -0,0 +1,152 @@
+package cmcrecord
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+	"github.com/Davincible/go-coinmarketcap/cmctest"
+	"golang.org/x/time/rate"
+)
+
+func newClient(baseURL string, rec *Recorder) *cmc.Client {
+	return cmc.NewClient(
+		cmc.WithAPIKey(cmctest.APIKey),
+		cmc.WithBaseURL(baseURL),
+		cmc.WithHTTPClient(rec.Client()),
+		cmc.WithRateLimit(rate.Limit(1000)),
+		cmc.WithRetryPolicy(cmc.RetryPolicy{}),
+	)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "quotes.json")
+	ctx := context.Background()
+
+	fake := cmctest.NewServer()
+	rec, err := New(path, Record)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	recorded, err := newClient(fake.URL, rec).GetCryptocurrencyQuotesLatest(ctx, &cmc.CryptocurrencyQuotesOptions{
+		Symbol: []string{"BTC", "ETH"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error while recording: %v", err)
+	}
+	fake.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected cassette to be written: %v", err)
+	}
+	if strings.Contains(string(data), cmctest.APIKey) {
+		t.Error("cassette must not contain the API key")
+	}
+
+	replayer, err := New(path, Replay)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The base URL and symbol order differ from the recording.
+	client := newClient("http://replay.invalid", replayer)
+	replayed, err := client.GetCryptocurrencyQuotesLatest(ctx, &cmc.CryptocurrencyQuotesOptions{
+		Symbol: []string{"ETH", "BTC"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error while replaying: %v", err)
+	}
+
+	want := *cmc.GetPrimaryQuote(recorded.Data["BTC"]).Quote["USD"].Price
+	if got := *cmc.GetPrimaryQuote(replayed.Data["BTC"]).Quote["USD"].Price; got != want {
+		t.Errorf("expected replayed price %v, got %v", want, got)
+	}
+
+	_, err = client.GetCryptocurrencyMap(ctx, nil)
+	if !errors.Is(err, ErrInteractionNotFound) {
+		t.Errorf("expected ErrInteractionNotFound, got %v", err)
+	}
+}
+
+func TestRecordDecompressesBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(`{"data": [{"id": 2781, "name": "United States Dollar", "sign": "$", "symbol": "USD"}], "status": {"error_code": 0}}`))
+		gz.Close()
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "fiat.json")
+	rec, err := New(path, Record)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := newClient(server.URL, rec).GetFiatMap(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Data) != 1 || resp.Data[0].Symbol != "USD" {
+		t.Errorf("unexpected data: %+v", resp.Data)
+	}
+
+	interactions := rec.Interactions()
+	if len(interactions) != 1 || !strings.Contains(string(interactions[0].Body), "United States Dollar") {
+		t.Fatalf("expected decompressed body, got %+v", interactions)
+	}
+	if _, ok := interactions[0].Header["Content-Encoding"]; ok {
+		t.Error("Content-Encoding must not be recorded")
+	}
+}
+
+func TestPassthroughDoesNotWrite(t *testing.T) {
+	fake := cmctest.NewServer()
+	defer fake.Close()
+
+	path := filepath.Join(t.TempDir(), "unused.json")
+	rec, err := New(path, Passthrough)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := newClient(fake.URL, rec).GetFiatMap(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected no cassette in passthrough mode, got %v", err)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Mode
+		wantErr  bool
+	}{
+		{"", Replay, false},
+		{"record", Record, false},
+		{"REPLAY", Replay, false},
+		{"passthrough", Passthrough, false},
+		{"rewind", Replay, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			mode, err := ParseMode(tt.input)
+			if (err != nil) != tt.wantErr || mode != tt.expected {
+				t.Errorf("expected (%v, err=%v), got (%v, %v)", tt.expected, tt.wantErr, mode, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,610 @@
+// Package cmcresolve maps symbols, slugs, names and contract addresses to
+// CoinMarketCap IDs. A Resolver is built from /v1/cryptocurrency/map, keeps itself
+// up to date in the background and can persist its snapshot to disk, so services
+// start without spending credits on the map:
+//
+//	resolver := cmcresolve.New(client, cmcresolve.WithSnapshot("cache/cmc-map.json"))
+//	if err := resolver.Start(ctx); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	asset, err := resolver.Resolve("USDT")
+//	var ambiguous *cmcresolve.AmbiguousError
+//	if errors.As(err, &ambiguous) {
+//		asset = ambiguous.Candidates[0] // ranked best first
+//	}
+//
+// Symbols are not unique. Lookups that can match several assets return them ranked:
+// active before inactive, then by CoinMarketCap rank, with unranked assets last.
+package cmcresolve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// DefaultRefreshInterval is how often Start refreshes the map in the background.
+const DefaultRefreshInterval = 24 * time.Hour
+
+var (
+	// ErrNotFound is returned when no asset matches a lookup.
+	ErrNotFound = errors.New("cmcresolve: no matching asset")
+	// ErrAmbiguous is matched by errors.Is when Resolve finds several assets.
+	ErrAmbiguous = errors.New("cmcresolve: ambiguous query")
+)
+
+// AmbiguousError is returned by Resolve when a symbol or name matches several assets.
+type AmbiguousError struct {
+	Query      string
+	Candidates []Asset // ranked best first
+}
+
+// Error implements the error interface.
+func (e *AmbiguousError) Error() string {
+	names := make([]string, len(e.Candidates))
+	for i, asset := range e.Candidates {
+		names[i] = asset.String()
+	}
+	return fmt.Sprintf("cmcresolve: %q matches %d assets: %s", e.Query, len(e.Candidates), strings.Join(names, ", "))
+}
+
+// Is reports whether target is ErrAmbiguous.
+func (e *AmbiguousError) Is(target error) bool {
+	return target == ErrAmbiguous
+}
+
+// Asset is a cryptocurrency known to the resolver.
+type Asset struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Symbol    string     `json:"symbol"`
+	Slug      string     `json:"slug"`
+	Rank      int        `json:"rank,omitempty"` // zero when unranked
+	Active    bool       `json:"active"`
+	Contracts []Contract `json:"contracts,omitempty"`
+}
+
+// String describes the asset for error messages and logs.
+func (a Asset) String() string {
+	s := fmt.Sprintf("%s (%s, id %d", a.Symbol, a.Slug, a.ID)
+	if a.Rank > 0 {
+		s += fmt.Sprintf(", rank %d", a.Rank)
+	}
+	if !a.Active {
+		s += ", inactive"
+	}
+	return s + ")"
+}
+
+// Contract is a token contract deployed on a platform such as Ethereum.
+type Contract struct {
+	Platform cmc.Platform `json:"platform"` // TokenAddress is not set
+	Address  string       `json:"address"`
+}
+
+// Option configures a Resolver.
+type Option func(*Resolver)
+
+// WithSnapshot makes the resolver load its data from path on Start and save it
+// there after every refresh.
+func WithSnapshot(path string) Option {
+	return func(r *Resolver) {
+		r.path = path
+	}
+}
+
+// WithRefreshInterval replaces DefaultRefreshInterval. An interval of zero or less
+// turns off background refreshes: Start then only calls the API when it has no data.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(r *Resolver) {
+		r.interval = interval
+	}
+}
+
+// WithInactive includes inactive assets, which are needed to resolve delisted coins.
+func WithInactive() Option {
+	return func(r *Resolver) {
+		r.inactive = true
+	}
+}
+
+// WithErrorHandler receives errors from background refreshes. By default they are dropped
+// and the resolver keeps serving its previous data.
+func WithErrorHandler(fn func(error)) Option {
+	return func(r *Resolver) {
+		r.onError = fn
+	}
+}
+
+// Resolver looks up CoinMarketCap IDs. It is safe for concurrent use.
+type Resolver struct {
+	client   *cmc.Client
+	path     string
+	interval time.Duration
+	inactive bool
+	onError  func(error)
+
+	writeMu sync.Mutex // serializes updates so they do not drop each other's changes
+
+	mu      sync.RWMutex
+	updated time.Time
+	assets  []Asset
+	byID    map[int]int // indices into assets
+	bySlug  map[string]int
+	bySym   map[string][]int // ranked
+	byName  map[string][]int // ranked
+	byAddr  map[string][]int // ranked
+}
+
+// New creates an empty resolver. Call Start, Refresh or Load before looking anything up.
+func New(client *cmc.Client, opts ...Option) *Resolver {
+	r := &Resolver{
+		client:   client,
+		interval: DefaultRefreshInterval,
+		onError:  func(error) {},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	r.set(time.Time{}, nil)
+	return r
+}
+
+// Start loads the snapshot, if one is configured, and refreshes from the API when
+// there is none or it is older than the refresh interval. It then refreshes in the
+// background every interval until ctx is done, unless the interval is zero or less.
+// Start only fails when the resolver ends up with no data.
+func (r *Resolver) Start(ctx context.Context) error {
+	if r.path != "" {
+		if err := r.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+			r.onError(err)
+		}
+	}
+
+	stale := r.interval > 0 && time.Since(r.Updated()) >= r.interval
+	if stale || r.Len() == 0 {
+		if err := r.Refresh(ctx); err != nil {
+			if r.Len() == 0 {
+				return err
+			}
+			r.onError(err)
+		}
+	}
+
+	if r.interval > 0 {
+		go r.run(ctx)
+	}
+	return nil
+}
+
+func (r *Resolver) run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Refresh(ctx); err != nil && ctx.Err() == nil {
+				r.onError(err)
+			}
+		}
+	}
+}
+
+// Refresh rebuilds the resolver from /v1/cryptocurrency/map and saves the snapshot.
+// Contract addresses learned from /v2/cryptocurrency/info are kept.
+func (r *Resolver) Refresh(ctx context.Context) error {
+	opts := &cmc.CryptocurrencyMapOptions{}
+	if r.inactive {
+		status := cmc.StatusActive + "," + cmc.StatusInactive
+		opts.ListingStatus = &status
+	}
+
+	entries, err := r.client.IterateCryptocurrencyMap(ctx, opts).All()
+	if err != nil {
+		return fmt.Errorf("cmcresolve: failed to refresh: %w", err)
+	}
+
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	r.mu.RLock()
+	previous := r.assets
+	r.mu.RUnlock()
+
+	known := make(map[int][]Contract, len(previous))
+	for _, asset := range previous {
+		known[asset.ID] = asset.Contracts
+	}
+
+	assets := make([]Asset, len(entries))
+	for i, entry := range entries {
+		assets[i] = fromMap(entry)
+		assets[i].Contracts = mergeContracts(assets[i].Contracts, known[entry.ID])
+	}
+
+	r.set(time.Now(), assets)
+	if r.path != "" {
+		return r.Save()
+	}
+	return nil
+}
+
+// LoadContracts fetches /v2/cryptocurrency/info for ids to learn every contract address
+// of those assets, beyond the main one the map reports, and saves the snapshot.
+func (r *Resolver) LoadContracts(ctx context.Context, ids ...int) error {
+	resp, err := r.client.GetCryptocurrencyInfo(ctx, &cmc.CryptocurrencyInfoOptions{ID: ids})
+	if err != nil {
+		return fmt.Errorf("cmcresolve: failed to load contracts: %w", err)
+	}
+
+	r.addInfo(resp.Data)
+	if r.path != "" {
+		return r.Save()
+	}
+	return nil
+}
+
+// snapshot is the file format written by Save.
+type snapshot struct {
+	Updated time.Time `json:"updated"`
+	Assets  []Asset   `json:"assets"`
+}
+
+// Load replaces the resolver's data with the snapshot file.
+func (r *Resolver) Load() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("cmcresolve: failed to read snapshot: %w", err)
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("cmcresolve: failed to parse snapshot %s: %w", r.path, err)
+	}
+
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	r.set(snap.Updated, snap.Assets)
+	return nil
+}
+
+// Save writes the resolver's data to the snapshot file, replacing it atomically.
+func (r *Resolver) Save() error {
+	r.mu.RLock()
+	data, err := json.Marshal(snapshot{Updated: r.updated, Assets: r.assets})
+	r.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("cmcresolve: failed to encode snapshot: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("cmcresolve: failed to create snapshot directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), ".snapshot-*")
+	if err != nil {
+		return fmt.Errorf("cmcresolve: failed to write snapshot: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("cmcresolve: failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("cmcresolve: failed to write snapshot: %w", err)
+	}
+	return os.Rename(tmp.Name(), r.path)
+}
+
+// Updated returns when the resolver's data was fetched from the API.
+func (r *Resolver) Updated() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.updated
+}
+
+// Len returns the number of assets known to the resolver.
+func (r *Resolver) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.assets)
+}
+
+// ID returns the asset with a CoinMarketCap ID.
+func (r *Resolver) ID(id int) (Asset, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.byID[id]
+	if !ok {
+		return Asset{}, false
+	}
+	return r.assets[i], true
+}
+
+// Slug returns the asset with a slug, such as "tether". Slugs are unique.
+func (r *Resolver) Slug(slug string) (Asset, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.bySlug[strings.ToLower(slug)]
+	if !ok {
+		return Asset{}, false
+	}
+	return r.assets[i], true
+}
+
+// Symbol returns every asset with a ticker symbol, ranked best first.
+func (r *Resolver) Symbol(symbol string) []Asset {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.collect(r.bySym[strings.ToUpper(symbol)])
+}
+
+// Name returns every asset with a name, ignoring case, ranked best first.
+func (r *Resolver) Name(name string) []Asset {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.collect(r.byName[strings.ToLower(name)])
+}
+
+// Find returns every asset matching query as an ID, slug, symbol or name. Exact ID and
+// slug matches come first, followed by symbol and then name matches, each ranked.
+func (r *Resolver) Find(query string) []Asset {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var indices []int
+	if id, err := strconv.Atoi(query); err == nil {
+		if i, ok := r.byID[id]; ok {
+			indices = append(indices, i)
+		}
+	}
+	if i, ok := r.bySlug[strings.ToLower(query)]; ok {
+		indices = append(indices, i)
+	}
+	indices = append(indices, r.bySym[strings.ToUpper(query)]...)
+	indices = append(indices, r.byName[strings.ToLower(query)]...)
+
+	seen := make(map[int]bool, len(indices))
+	var unique []int
+	for _, i := range indices {
+		if !seen[i] {
+			seen[i] = true
+			unique = append(unique, i)
+		}
+	}
+	return r.collect(unique)
+}
+
+// Resolve returns the single asset query identifies. IDs and slugs always resolve to
+// one asset; symbols and names that match several return an *AmbiguousError listing
+// the candidates. Use Best to take the top-ranked candidate instead.
+func (r *Resolver) Resolve(query string) (Asset, error) {
+	if id, err := strconv.Atoi(query); err == nil {
+		if asset, ok := r.ID(id); ok {
+			return asset, nil
+		}
+	}
+	if asset, ok := r.Slug(query); ok {
+		return asset, nil
+	}
+
+	candidates := r.Find(query)
+	switch len(candidates) {
+	case 0:
+		return Asset{}, fmt.Errorf("%w for %q", ErrNotFound, query)
+	case 1:
+		return candidates[0], nil
+	default:
+		return Asset{}, &AmbiguousError{Query: query, Candidates: candidates}
+	}
+}
+
+// Best returns the top-ranked asset matching query.
+func (r *Resolver) Best(query string) (Asset, error) {
+	candidates := r.Find(query)
+	if len(candidates) == 0 {
+		return Asset{}, fmt.Errorf("%w for %q", ErrNotFound, query)
+	}
+	return candidates[0], nil
+}
+
+// Contract returns the asset deployed at address on platform, which is matched against
+// the platform's name, slug or symbol ("Ethereum", "ethereum", "ETH") and may be empty
+// to match any platform. Addresses the resolver does not know are looked up with
+// /v2/cryptocurrency/info and remembered.
+func (r *Resolver) Contract(ctx context.Context, platform, address string) (Asset, error) {
+	if asset, ok := r.contract(platform, address); ok {
+		return asset, nil
+	}
+
+	resp, err := r.client.GetCryptocurrencyInfo(ctx, &cmc.CryptocurrencyInfoOptions{Address: []string{address}})
+	if errors.Is(err, cmc.ErrBadRequest) {
+		return Asset{}, fmt.Errorf("%w for contract %s", ErrNotFound, address)
+	}
+	if err != nil {
+		return Asset{}, fmt.Errorf("cmcresolve: failed to look up contract %s: %w", address, err)
+	}
+	r.addInfo(resp.Data)
+
+	if asset, ok := r.contract(platform, address); ok {
+		return asset, nil
+	}
+	return Asset{}, fmt.Errorf("%w for contract %s on %q", ErrNotFound, address, platform)
+}
+
+func (r *Resolver) contract(platform, address string) (Asset, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, i := range r.byAddr[strings.ToLower(address)] {
+		for _, contract := range r.assets[i].Contracts {
+			if strings.EqualFold(contract.Address, address) && matchPlatform(contract.Platform, platform) {
+				return r.assets[i], true
+			}
+		}
+	}
+	return Asset{}, false
+}
+
+func matchPlatform(p cmc.Platform, query string) bool {
+	return query == "" || strings.EqualFold(p.Name, query) || strings.EqualFold(p.Slug, query) || strings.EqualFold(p.Symbol, query)
+}
+
+// addInfo merges the contract addresses from /v2/cryptocurrency/info into the resolver,
+// adding assets it did not know.
+func (r *Resolver) addInfo(infos map[string]cmc.CryptocurrencyInfo) {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	r.mu.RLock()
+	assets := append([]Asset(nil), r.assets...)
+	byID := r.byID
+	updated := r.updated
+	r.mu.RUnlock()
+
+	for _, info := range infos {
+		var contracts []Contract
+		for _, address := range info.ContractAddress {
+			platform := address.Platform
+			platform.TokenAddress = ""
+			contracts = append(contracts, Contract{Platform: platform, Address: address.ContractAddress})
+		}
+
+		if i, ok := byID[info.ID]; ok {
+			assets[i].Contracts = mergeContracts(assets[i].Contracts, contracts)
+			continue
+		}
+		assets = append(assets, Asset{
+			ID:        info.ID,
+			Name:      info.Name,
+			Symbol:    info.Symbol,
+			Slug:      info.Slug,
+			Active:    true,
+			Contracts: contracts,
+		})
+	}
+
+	r.set(updated, assets)
+}
+
+// set replaces the resolver's data and rebuilds the indexes.
+func (r *Resolver) set(updated time.Time, assets []Asset) {
+	sort.SliceStable(assets, func(i, j int) bool {
+		return better(assets[i], assets[j])
+	})
+
+	byID := make(map[int]int, len(assets))
+	bySlug := make(map[string]int, len(assets))
+	bySym := make(map[string][]int)
+	byName := make(map[string][]int)
+	byAddr := make(map[string][]int)
+
+	// Appending in ranked order keeps every index ranked.
+	for i, asset := range assets {
+		byID[asset.ID] = i
+		if _, ok := bySlug[strings.ToLower(asset.Slug)]; !ok {
+			bySlug[strings.ToLower(asset.Slug)] = i
+		}
+		bySym[strings.ToUpper(asset.Symbol)] = append(bySym[strings.ToUpper(asset.Symbol)], i)
+		byName[strings.ToLower(asset.Name)] = append(byName[strings.ToLower(asset.Name)], i)
+		for _, contract := range asset.Contracts {
+			address := strings.ToLower(contract.Address)
+			if n := len(byAddr[address]); n == 0 || byAddr[address][n-1] != i {
+				byAddr[address] = append(byAddr[address], i)
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.updated, r.assets = updated, assets
+	r.byID, r.bySlug, r.bySym, r.byName, r.byAddr = byID, bySlug, bySym, byName, byAddr
+}
+
+// collect copies the assets at indices. Callers must hold r.mu.
+func (r *Resolver) collect(indices []int) []Asset {
+	if len(indices) == 0 {
+		return nil
+	}
+	assets := make([]Asset, len(indices))
+	for i, index := range indices {
+		assets[i] = r.assets[index]
+	}
+	return assets
+}
+
+// better reports whether a ranks above b: active first, then by rank with unranked
+// assets last, then by ID.
+func better(a, b Asset) bool {
+	if a.Active != b.Active {
+		return a.Active
+	}
+	if (a.Rank > 0) != (b.Rank > 0) {
+		return a.Rank > 0
+	}
+	if a.Rank != b.Rank {
+		return a.Rank < b.Rank
+	}
+	return a.ID < b.ID
+}
+
+func fromMap(entry cmc.CryptocurrencyMap) Asset {
+	asset := Asset{
+		ID:     entry.ID,
+		Name:   entry.Name,
+		Symbol: entry.Symbol,
+		Slug:   entry.Slug,
+		Active: entry.IsActive == nil || *entry.IsActive == 1,
+	}
+	if entry.Rank != nil {
+		asset.Rank = *entry.Rank
+	}
+	if entry.Platform != nil && entry.Platform.TokenAddress != "" {
+		platform := *entry.Platform
+		platform.TokenAddress = ""
+		asset.Contracts = []Contract{{Platform: platform, Address: entry.Platform.TokenAddress}}
+	}
+	return asset
+}
+
+// mergeContracts returns a new slice with the contracts of both, without duplicates.
+func mergeContracts(a, b []Contract) []Contract {
+	if len(b) == 0 {
+		return a
+	}
+
+	merged := append([]Contract(nil), a...)
+	for _, contract := range b {
+		duplicate := false
+		for _, existing := range merged {
+			if existing.Platform.ID == contract.Platform.ID && strings.EqualFold(existing.Address, contract.Address) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			merged = append(merged, contract)
+		}
+	}
+	return merged
+}
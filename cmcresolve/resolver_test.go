@@ -0,0 +1,247 @@
+package cmcresolve
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+	"github.com/Davincible/go-coinmarketcap/cmctest"
+)
+
+var ethereum = cmc.Platform{ID: 1027, Name: "Ethereum", Symbol: "ETH", Slug: "ethereum"}
+
+func testCoins() []cmctest.Coin {
+	withAddress := func(address string) *cmc.Platform {
+		platform := ethereum
+		platform.TokenAddress = address
+		return &platform
+	}
+
+	return []cmctest.Coin{
+		{ID: 1, Name: "Bitcoin", Symbol: "BTC", Slug: "bitcoin", Rank: 1},
+		{ID: 1027, Name: "Ethereum", Symbol: "ETH", Slug: "ethereum", Rank: 2},
+		{ID: 825, Name: "Tether USDt", Symbol: "USDT", Slug: "tether", Rank: 3,
+			Platform: withAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")},
+		{ID: 30000, Name: "Fake Tether", Symbol: "USDT", Slug: "fake-tether",
+			Platform: withAddress("0x1111111111111111111111111111111111111111")},
+		{ID: 20000, Name: "Tether Wrapped", Symbol: "USDT", Slug: "tether-wrapped", Rank: 900},
+		{ID: 10000, Name: "Old Tether", Symbol: "USDT", Slug: "old-tether", Rank: 50, Inactive: true},
+	}
+}
+
+func ids(assets []Asset) []int {
+	result := make([]int, len(assets))
+	for i, asset := range assets {
+		result[i] = asset.ID
+	}
+	return result
+}
+
+func equalIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestResolverLookups(t *testing.T) {
+	fake := cmctest.NewServer(cmctest.WithCoins(testCoins()...))
+	defer fake.Close()
+
+	resolver := New(fake.Client(), WithInactive())
+	if err := resolver.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolver.Len() != 6 {
+		t.Fatalf("expected 6 assets, got %d", resolver.Len())
+	}
+
+	if got := ids(resolver.Symbol("usdt")); !equalIDs(got, []int{825, 20000, 30000, 10000}) {
+		t.Errorf("expected USDT candidates ranked active, ranked, unranked, inactive; got %v", got)
+	}
+
+	tests := []struct {
+		query   string
+		want    int
+		wantErr error
+	}{
+		{query: "BTC", want: 1},
+		{query: "bitcoin", want: 1},
+		{query: "825", want: 825},
+		{query: "Fake-Tether", want: 30000},
+		{query: "tether usdt", want: 825},
+		{query: "USDT", wantErr: ErrAmbiguous},
+		{query: "DOGE", wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			asset, err := resolver.Resolve(tt.query)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || asset.ID != tt.want {
+				t.Fatalf("expected ID %d, got %+v and %v", tt.want, asset, err)
+			}
+		})
+	}
+
+	_, err := resolver.Resolve("USDT")
+	var ambiguous *AmbiguousError
+	if !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 4 || ambiguous.Candidates[0].ID != 825 {
+		t.Errorf("expected the ranked candidates, got %v", err)
+	}
+	if best, err := resolver.Best("USDT"); err != nil || best.ID != 825 {
+		t.Errorf("expected Best to pick Tether, got %+v and %v", best, err)
+	}
+	if got := ids(resolver.Find("ethereum")); !equalIDs(got, []int{1027}) {
+		t.Errorf("expected a slug match without duplicates, got %v", got)
+	}
+}
+
+func TestResolverContracts(t *testing.T) {
+	fake := cmctest.NewServer(cmctest.WithCoins(testCoins()...))
+	defer fake.Close()
+
+	resolver := New(fake.Client())
+	ctx := context.Background()
+	if err := resolver.Refresh(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	asset, err := resolver.Contract(ctx, "Ethereum", "0xdac17f958d2ee523a2206206994597c13d831ec7")
+	if err != nil || asset.ID != 825 {
+		t.Fatalf("expected Tether from the map, got %+v and %v", asset, err)
+	}
+	if _, err := resolver.Contract(ctx, "solana", "0xdac17f958d2ee523a2206206994597c13d831ec7"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound on another platform, got %v", err)
+	}
+
+	tron := cmc.Platform{ID: 1958, Name: "Tron", Symbol: "TRX", Slug: "tron"}
+	fake.SetFixture("/v2/cryptocurrency/info", map[string]cmc.CryptocurrencyInfo{
+		"825": {ID: 825, Name: "Tether USDt", Symbol: "USDT", Slug: "tether", ContractAddress: []cmc.ContractAddress{
+			{ContractAddress: "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", Platform: tron},
+		}},
+	})
+
+	requests := len(fake.Requests())
+	asset, err = resolver.Contract(ctx, "TRX", "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t")
+	if err != nil || asset.ID != 825 || len(asset.Contracts) != 2 {
+		t.Fatalf("expected the info lookup to add the Tron contract, got %+v and %v", asset, err)
+	}
+	if _, err := resolver.Contract(ctx, "tron", "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(fake.Requests()) - requests; got != 1 {
+		t.Errorf("expected the contract to be remembered after one request, got %d requests", got)
+	}
+
+	if err := resolver.Refresh(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if asset, _ := resolver.ID(825); len(asset.Contracts) != 2 {
+		t.Errorf("expected a refresh to keep learned contracts, got %+v", asset.Contracts)
+	}
+}
+
+func TestResolverSnapshotAndBackgroundRefresh(t *testing.T) {
+	fake := cmctest.NewServer(cmctest.WithCoins(testCoins()...))
+	defer fake.Close()
+
+	path := filepath.Join(t.TempDir(), "cache", "map.json")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first := New(fake.Client(), WithSnapshot(path))
+	if err := first.Start(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected a snapshot to be written: %v", err)
+	}
+
+	// A fresh snapshot is used without calling the API.
+	requests := len(fake.Requests())
+	second := New(fake.Client(), WithSnapshot(path))
+	if err := second.Start(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.Requests()) != requests {
+		t.Error("expected the snapshot to be used instead of the API")
+	}
+	if asset, err := second.Resolve("bitcoin"); err != nil || asset.ID != 1 {
+		t.Errorf("expected bitcoin from the snapshot, got %+v and %v", asset, err)
+	}
+
+	// A short interval refreshes in the background and picks up renames.
+	errs := make(chan error, 100)
+	third := New(fake.Client(), WithRefreshInterval(10*time.Millisecond), WithErrorHandler(func(err error) {
+		errs <- err
+	}))
+	if err := third.Start(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	updated := third.Updated()
+	fake.UpdateCoin("BTC", func(c *cmctest.Coin) { c.Name = "Bitcoin Renamed" })
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(third.Name("Bitcoin Renamed")) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected a background refresh")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !third.Updated().After(updated) {
+		t.Error("expected Updated to advance")
+	}
+	cancel()
+	if len(errs) != 0 {
+		t.Errorf("unexpected refresh error: %v", <-errs)
+	}
+}
+
+func TestResolverWithoutBackgroundRefresh(t *testing.T) {
+	fake := cmctest.NewServer(cmctest.WithCoins(testCoins()...))
+	defer fake.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		requests := len(fake.Requests())
+		resolver := New(fake.Client(), WithRefreshInterval(interval))
+		if err := resolver.Start(ctx); err != nil {
+			t.Fatalf("%v: unexpected error: %v", interval, err)
+		}
+		if resolver.Len() == 0 {
+			t.Fatalf("%v: expected Start to load the map when there is no data", interval)
+		}
+
+		loaded := len(fake.Requests())
+		time.Sleep(50 * time.Millisecond)
+		if loaded == requests || len(fake.Requests()) != loaded {
+			t.Errorf("%v: expected one refresh and none in the background, got %d requests", interval, len(fake.Requests())-requests)
+		}
+	}
+}
+
+func TestResolverStartFailsWithoutData(t *testing.T) {
+	fake := cmctest.NewServer(cmctest.WithAPIKeys("other-key"))
+	defer fake.Close()
+
+	resolver := New(fake.Client())
+	if err := resolver.Start(context.Background()); !errors.Is(err, cmc.ErrInvalidAPIKey) {
+		t.Fatalf("expected ErrInvalidAPIKey, got %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package cmctest
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// category is a category of the fake: the active coins sharing a tag.
+type category struct {
+	id    string
+	name  string
+	tag   string
+	coins []Coin
+}
+
+// categories returns a category for every tag of the active coins, largest market cap
+// first.
+func (s *Server) categories() []category {
+	var categories []category
+	index := make(map[string]int)
+	for _, coin := range s.activeCoins() {
+		for _, tag := range coin.Tags {
+			i, ok := index[tag]
+			if !ok {
+				i = len(categories)
+				index[tag] = i
+				categories = append(categories, category{id: stableID("category", tag), name: tagName(tag), tag: tag})
+			}
+			categories[i].coins = append(categories[i].coins, coin)
+		}
+	}
+
+	sort.SliceStable(categories, func(i, j int) bool {
+		return categories[i].marketCap() > categories[j].marketCap()
+	})
+	return categories
+}
+
+func (c category) marketCap() float64 {
+	var total float64
+	for _, coin := range c.coins {
+		total += coin.MarketCap()
+	}
+	return total
+}
+
+// summary aggregates the category's coins. Market cap change is the change of the
+// total over 24 hours; average price change is the plain mean of the coins' changes.
+func (c category) summary(now time.Time) cmc.Category {
+	var marketCap, previousCap, volume, changes float64
+	for _, coin := range c.coins {
+		marketCap += coin.MarketCap()
+		previousCap += coin.MarketCap() / (1 + coin.PercentChange24h/100)
+		volume += coin.Volume24h
+		changes += coin.PercentChange24h
+	}
+	avgChange := changes / float64(len(c.coins))
+	capChange := (marketCap/previousCap - 1) * 100
+
+	return cmc.Category{
+		ID:              c.id,
+		Name:            c.name,
+		Title:           c.name,
+		Description:     c.name + " tokens",
+		NumTokens:       len(c.coins),
+		AvgPriceChange:  &avgChange,
+		MarketCap:       &marketCap,
+		MarketCapChange: &capChange,
+		Volume:          &volume,
+		LastUpdated:     now,
+	}
+}
+
+// tagName turns a tag such as "smart-contracts" into a name such as "Smart Contracts".
+// Short tags are acronyms.
+func tagName(tag string) string {
+	if len(tag) <= 3 {
+		return strings.ToUpper(tag)
+	}
+	words := strings.Split(tag, "-")
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// stableID derives a 24-digit hex ID, the form of the API's category and airdrop IDs,
+// from a kind and a name.
+func stableID(kind, name string) string {
+	h := fnv.New128a()
+	h.Write([]byte(kind + "/" + name))
+	return fmt.Sprintf("%x", h.Sum(nil)[:12])
+}
+
+// cryptocurrencyCategories serves the categories, optionally only those holding the
+// coins given by slug or symbol or with the given IDs.
+func (s *Server) cryptocurrencyCategories(query url.Values) (any, int, *apiError) {
+	ids, slugs, symbols := splitList(query.Get("id")), splitList(query.Get("slug")), splitList(query.Get("symbol"))
+
+	now := s.now().UTC()
+	var data []cmc.Category
+	for _, c := range s.categories() {
+		if len(ids)+len(slugs)+len(symbols) > 0 && !contains(ids, c.id) && !c.holds(slugs, symbols) {
+			continue
+		}
+		data = append(data, c.summary(now))
+	}
+
+	data, err := page(query, data, len(data))
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, len(data), nil
+}
+
+// holds reports whether the category has a coin with one of the slugs or symbols.
+func (c category) holds(slugs, symbols []string) bool {
+	for _, coin := range c.coins {
+		if contains(slugs, coin.Slug) || contains(symbols, coin.Symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+// cryptocurrencyCategory serves one category with a page of its coins' listings.
+func (s *Server) cryptocurrencyCategory(query url.Values) (any, int, *apiError) {
+	id := query.Get("id")
+	if id == "" {
+		return nil, 0, badRequest(`"id" is required`)
+	}
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, c := range s.categories() {
+		if c.id != id {
+			continue
+		}
+
+		coins, err := page(query, c.coins, 100)
+		if err != nil {
+			return nil, 0, err
+		}
+		listings := make([]cmc.CryptocurrencyListing, len(coins))
+		for i, coin := range coins {
+			listings[i] = s.cryptocurrencyListing(coin, targets)
+		}
+		return cmc.CategoryDetail{Category: c.summary(s.now().UTC()), Coins: listings}, len(listings), nil
+	}
+	return nil, 0, badRequest(`Invalid value for "id": "%s"`, id)
+}
+
+// airdrops returns an airdrop for every active coin. Its status cycles with the coin's
+// rank: ongoing, ended and upcoming, with dates relative to the current day.
+func (s *Server) airdrops() []cmc.Airdrop {
+	const day = 24 * time.Hour
+	today := s.now().UTC().Truncate(day)
+
+	var airdrops []cmc.Airdrop
+	for _, coin := range s.activeCoins() {
+		var status cmc.AirdropStatus
+		var start, end time.Time
+		switch coin.Rank % 3 {
+		case 1:
+			status, start, end = cmc.AirdropStatusOngoing, today.Add(-7*day), today.Add(7*day)
+		case 2:
+			status, start, end = cmc.AirdropStatusEnded, today.Add(-30*day), today.Add(-16*day)
+		default:
+			status, start, end = cmc.AirdropStatusUpcoming, today.Add(7*day), today.Add(21*day)
+		}
+
+		website := "https://" + coin.Slug + ".example/airdrop"
+		airdrops = append(airdrops, cmc.Airdrop{
+			ID:               stableID("airdrop", coin.Slug),
+			Name:             coin.Name + " Airdrop",
+			Description:      fmt.Sprintf("Complete the tasks to share a pool of %s.", coin.Symbol),
+			Website:          &website,
+			DateAdded:        start.Add(-14 * day),
+			Status:           string(status),
+			DateStart:        &start,
+			DateEnd:          &end,
+			CryptocurrencyID: coin.ID,
+			Symbol:           coin.Symbol,
+			Slug:             coin.Slug,
+		})
+	}
+	return airdrops
+}
+
+// cryptocurrencyAirdrops serves the airdrops with a status, ongoing by default,
+// optionally only those of the coin given by id, slug or symbol.
+func (s *Server) cryptocurrencyAirdrops(query url.Values) (any, int, *apiError) {
+	status := cmc.AirdropStatus(strings.ToUpper(query.Get("status")))
+	switch status {
+	case "":
+		status = cmc.AirdropStatusOngoing
+	case cmc.AirdropStatusOngoing, cmc.AirdropStatusEnded, cmc.AirdropStatusUpcoming:
+	default:
+		return nil, 0, badRequest(`Invalid value for "status": "%s"`, query.Get("status"))
+	}
+	id, slug, symbol := query.Get("id"), query.Get("slug"), query.Get("symbol")
+
+	var data []cmc.Airdrop
+	for _, airdrop := range s.airdrops() {
+		switch {
+		case airdrop.Status != string(status),
+			id != "" && id != strconv.Itoa(airdrop.CryptocurrencyID),
+			slug != "" && slug != airdrop.Slug,
+			symbol != "" && !strings.EqualFold(symbol, airdrop.Symbol):
+			continue
+		}
+		data = append(data, airdrop)
+	}
+
+	data, err := page(query, data, 100)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, len(data), nil
+}
+
+// cryptocurrencyAirdrop serves one airdrop by ID.
+func (s *Server) cryptocurrencyAirdrop(query url.Values) (any, int, *apiError) {
+	id := query.Get("id")
+	if id == "" {
+		return nil, 0, badRequest(`"id" is required`)
+	}
+	for _, airdrop := range s.airdrops() {
+		if airdrop.ID == id {
+			return airdrop, 1, nil
+		}
+	}
+	return nil, 0, badRequest(`Invalid value for "id": "%s"`, id)
+}
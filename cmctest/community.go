@@ -0,0 +1,249 @@
+package cmctest
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// commentsPerPost is the number of comments on every post.
+const commentsPerPost = 3
+
+// contentLatest serves an article about each active coin's 24h move, newest first.
+// Articles alternate between news and Alexandria.
+func (s *Server) contentLatest(query url.Values) (any, int, *apiError) {
+	kind, language := query.Get("category"), query.Get("language")
+	var coinID int
+	if value := query.Get("cryptocurrency_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, 0, badRequest(`Invalid value for "cryptocurrency_id": "%s"`, value)
+		}
+		coinID = id
+	}
+
+	hour := s.now().UTC().Truncate(time.Hour)
+	var data []cmc.NewsArticle
+	for i, coin := range s.activeCoins() {
+		article := contentArticle(coin, i, hour.Add(-time.Duration(i)*time.Hour))
+		switch {
+		case kind != "" && kind != article.Meta.Type,
+			language != "" && language != article.Meta.Language,
+			coinID != 0 && coinID != coin.ID:
+			continue
+		}
+		data = append(data, article)
+	}
+
+	data, err := page(query, data, 100)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, len(data), nil
+}
+
+func contentArticle(coin Coin, i int, released time.Time) cmc.NewsArticle {
+	kind, source := "news", "cmctest News"
+	if i%2 == 1 {
+		kind, source = "alexandria", "CoinMarketCap"
+	}
+	direction := "rises"
+	if coin.PercentChange24h < 0 {
+		direction = "falls"
+	}
+
+	cover := "https://news.example/" + coin.Slug + ".png"
+	return cmc.NewsArticle{
+		Cover:      &cover,
+		Assets:     []cmc.ContentAsset{contentAsset(coin)},
+		CreatedAt:  released,
+		UpdatedAt:  &released,
+		ReleasedAt: &released,
+		Meta: cmc.NewsArticleMeta{
+			ID:         stableID("article", coin.Slug),
+			Title:      fmt.Sprintf("%s %s %.1f%% in 24 hours", coin.Name, direction, math.Abs(coin.PercentChange24h)),
+			Subtitle:   fmt.Sprintf("%s trades at $%.2f.", coin.Symbol, coin.Price),
+			SourceName: source,
+			SourceURL:  "https://news.example/" + coin.Slug,
+			Language:   "en",
+			Type:       kind,
+			Status:     "PUBLISHED",
+			Visibility: true,
+		},
+	}
+}
+
+func contentAsset(coin Coin) cmc.ContentAsset {
+	return cmc.ContentAsset{ID: coin.ID, Name: coin.Name, Symbol: coin.Symbol, Slug: coin.Slug}
+}
+
+// posts returns two posts about each active coin, the higher ranked coins' posted
+// more recently and liked more.
+func (s *Server) posts() []cmc.Post {
+	minute := s.now().UTC().Truncate(time.Minute)
+
+	var posts []cmc.Post
+	for _, coin := range s.activeCoins() {
+		for n, author := range []string{"hodler", "trader"} {
+			nickname := coin.Symbol + "_" + author
+			likes := 500/coin.Rank + 25*(1-n)
+			posts = append(posts, cmc.Post{
+				PostID:       strconv.Itoa(coin.ID*10 + n),
+				Owner:        cmc.PostAuthor{Nickname: nickname, AvatarURL: "https://avatars.example/" + nickname + ".png"},
+				TextContent:  fmt.Sprintf("$%s at $%.2f, %+.1f%% today", coin.Symbol, coin.Price, coin.PercentChange24h),
+				Photos:       []string{},
+				CommentCount: commentsPerPost,
+				LikeCount:    likes,
+				RepostCount:  likes / 10,
+				PostTime:     minute.Add(-time.Duration(coin.Rank*60+n*30) * time.Minute),
+				LanguageCode: "en",
+				Currencies:   []cmc.ContentAsset{contentAsset(coin)},
+			})
+		}
+	}
+	return posts
+}
+
+// contentPosts serves the latest posts or, when top is set, the most liked. A page
+// ends with a last_score cursor that continues after its last post; the last page's
+// is empty.
+func (s *Server) contentPosts(query url.Values, top bool) (any, int, *apiError) {
+	period, err := timePeriod(query, cmc.TimePeriodAllTime)
+	if err != nil {
+		return nil, 0, err
+	}
+	coinID := query.Get("cryptocurrency_id")
+
+	var posts []cmc.Post
+	for _, post := range s.posts() {
+		if coinID != "" && coinID != strconv.Itoa(post.Currencies[0].ID) {
+			continue
+		}
+		if period != cmc.TimePeriodAllTime {
+			if since, until := s.periodWindow(Coin{}, period); post.PostTime.Before(since) || post.PostTime.After(until) {
+				continue
+			}
+		}
+		posts = append(posts, post)
+	}
+	sort.SliceStable(posts, func(i, j int) bool {
+		if top {
+			return posts[i].LikeCount > posts[j].LikeCount
+		}
+		return posts[i].PostTime.After(posts[j].PostTime)
+	})
+
+	offset := 0
+	if value := query.Get("last_score"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 || n > len(posts) {
+			return nil, 0, badRequest(`Invalid value for "last_score": "%s"`, value)
+		}
+		offset = n
+	}
+	list, err := page(query, posts[offset:], 20)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data := cmc.PostList{List: list}
+	if n := len(list); n > 0 {
+		end := 1 + slices.IndexFunc(posts, func(p cmc.Post) bool { return p.PostID == list[n-1].PostID })
+		if end < len(posts) {
+			data.LastScore = strconv.Itoa(end)
+		}
+	}
+	return data, len(list), nil
+}
+
+// contentComments serves the comments on a post.
+func (s *Server) contentComments(query url.Values) (any, int, *apiError) {
+	id := query.Get("post_id")
+	if id == "" {
+		return nil, 0, badRequest(`"post_id" is required`)
+	}
+
+	posts := s.posts()
+	i := slices.IndexFunc(posts, func(p cmc.Post) bool { return p.PostID == id })
+	if i < 0 {
+		return nil, 0, badRequest(`Invalid value for "post_id": "%s"`, id)
+	}
+	post := posts[i]
+
+	comments := make([]cmc.Comment, commentsPerPost)
+	for n := range comments {
+		nickname := fmt.Sprintf("%s_reader%d", post.Currencies[0].Symbol, n+1)
+		comments[n] = cmc.Comment{Post: cmc.Post{
+			PostID:       fmt.Sprintf("%s-%d", post.PostID, n+1),
+			Owner:        cmc.PostAuthor{Nickname: nickname, AvatarURL: "https://avatars.example/" + nickname + ".png"},
+			TextContent:  fmt.Sprintf("Replying to @%s", post.Owner.Nickname),
+			Photos:       []string{},
+			LikeCount:    commentsPerPost - n,
+			PostTime:     post.PostTime.Add(time.Duration(n+1) * 10 * time.Minute),
+			LanguageCode: "en",
+			Currencies:   post.Currencies,
+		}}
+	}
+
+	comments, err := page(query, comments, len(comments))
+	if err != nil {
+		return nil, 0, err
+	}
+	return comments, len(comments), nil
+}
+
+// communityTrendingTopics serves a topic for each category, largest first.
+func (s *Server) communityTrendingTopics(query url.Values) (any, int, *apiError) {
+	if _, err := timePeriod(query, cmc.TimePeriod24h); err != nil {
+		return nil, 0, err
+	}
+
+	var data []cmc.TrendingTopic
+	for i, c := range s.categories() {
+		data = append(data, cmc.TrendingTopic{Rank: i + 1, Topic: "#" + c.name})
+	}
+
+	data, err := page(query, data, 100)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, len(data), nil
+}
+
+// communityTrendingTokens serves the active coins by search score.
+func (s *Server) communityTrendingTokens(query url.Values) (any, int, *apiError) {
+	if _, err := timePeriod(query, cmc.TimePeriod24h); err != nil {
+		return nil, 0, err
+	}
+
+	coins, _ := s.bySearchScore()
+	usd := []convertTarget{{"USD", 1}}
+	data := make([]cmc.TrendingToken, len(coins))
+	for i, coin := range coins {
+		quote := s.cryptocurrencyQuote(coin, usd)
+		fiat := 0
+		data[i] = cmc.TrendingToken{
+			ID:       coin.ID,
+			Name:     coin.Name,
+			Symbol:   coin.Symbol,
+			Slug:     coin.Slug,
+			Rank:     i + 1,
+			CMCRank:  quote.CMCRank,
+			IsActive: quote.IsActive,
+			IsFiat:   &fiat,
+			Quote:    quote.Quote,
+		}
+	}
+
+	data, err := page(query, data, 100)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, len(data), nil
+}
@@ -0,0 +1,425 @@
+package cmctest
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// nativeTokenAddress stands in for the contract address of a network's own coin.
+const nativeTokenAddress = "0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+
+// dexShare is the share of a token's volume that trades on its network's DEX.
+const dexShare = 0.01
+
+// dexPool is the DEX pool of a seeded token against the coin of the network it is
+// issued on. Each network has one DEX, which shares the network coin's ID.
+type dexPool struct {
+	token   Coin
+	network Coin
+	address string
+}
+
+// dexPools returns a pool for every active token whose network coin is seeded, in
+// order of volume.
+func (s *Server) dexPools() []dexPool {
+	var pools []dexPool
+	for _, token := range s.coinsByRank() {
+		if token.Platform == nil || token.Inactive {
+			continue
+		}
+		for _, network := range s.coins {
+			if network.ID == token.Platform.ID {
+				address := fmt.Sprintf("0x%040x", token.ID*1_000_000+network.ID)
+				pools = append(pools, dexPool{token: token, network: network, address: address})
+			}
+		}
+	}
+	sort.SliceStable(pools, func(i, j int) bool {
+		return pools[i].token.Volume24h > pools[j].token.Volume24h
+	})
+	return pools
+}
+
+func (p dexPool) info() cmc.DexPairInfo {
+	token, network := p.token, p.network
+	return cmc.DexPairInfo{
+		ContractAddress:           p.address,
+		Name:                      token.Symbol + "/" + network.Symbol,
+		BaseAssetID:               strconv.Itoa(token.ID),
+		BaseAssetUCID:             strconv.Itoa(token.ID),
+		BaseAssetName:             token.Name,
+		BaseAssetSymbol:           token.Symbol,
+		BaseAssetContractAddress:  token.Platform.TokenAddress,
+		QuoteAssetID:              strconv.Itoa(network.ID),
+		QuoteAssetUCID:            strconv.Itoa(network.ID),
+		QuoteAssetName:            network.Name,
+		QuoteAssetSymbol:          network.Symbol,
+		QuoteAssetContractAddress: nativeTokenAddress,
+		DexID:                     strconv.Itoa(network.ID),
+		DexSlug:                   network.Slug + "-swap",
+		NetworkID:                 strconv.Itoa(network.ID),
+		NetworkSlug:               network.Slug,
+	}
+}
+
+// volume returns the pool's 24h volume in USD.
+func (p dexPool) volume() float64 {
+	return p.token.Volume24h * dexShare
+}
+
+// transactions returns the pool's 24h transactions, at an average of $5,000 each.
+func (p dexPool) transactions() int {
+	return int(p.volume() / 5000)
+}
+
+// matchesNetwork applies the network_id and network_slug parameters.
+func (p dexPool) matchesNetwork(query url.Values) bool {
+	id, slug := query.Get("network_id"), query.Get("network_slug")
+	return (id == "" || id == strconv.Itoa(p.network.ID)) && (slug == "" || strings.EqualFold(slug, p.network.Slug))
+}
+
+// dexNetworks serves the networks of the seeded tokens.
+func (s *Server) dexNetworks(query url.Values) (any, int, *apiError) {
+	var networks []cmc.DexNetwork
+	seen := make(map[int]bool)
+	for _, pool := range s.dexPools() {
+		network := pool.network
+		if seen[network.ID] {
+			continue
+		}
+		seen[network.ID] = true
+
+		id, slug, created := network.ID, network.Slug, network.DateAdded
+		networks = append(networks, cmc.DexNetwork{
+			ID:                 network.ID,
+			Name:               network.Name,
+			NetworkSlug:        network.Slug,
+			CryptocurrencyID:   &id,
+			CryptocurrencySlug: &slug,
+			CreatedAt:          &created,
+		})
+	}
+	sort.SliceStable(networks, func(i, j int) bool {
+		return networks[i].ID < networks[j].ID
+	})
+
+	networks, err := page(query, networks, 100)
+	if err != nil {
+		return nil, 0, err
+	}
+	return networks, len(networks), nil
+}
+
+// dexListings serves the DEX of each network, largest first.
+func (s *Server) dexListings(query url.Values) (any, int, *apiError) {
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total float64
+	pools := s.dexPools()
+	for _, pool := range pools {
+		total += pool.volume()
+	}
+
+	now := s.now().UTC()
+	var listings []cmc.DexListing
+	index := make(map[int]int)
+	for _, pool := range pools {
+		i, ok := index[pool.network.ID]
+		if !ok {
+			status, kind := "active", "swap"
+			i = len(listings)
+			index[pool.network.ID] = i
+			listings = append(listings, cmc.DexListing{
+				ID:             pool.network.ID,
+				Name:           pool.network.Name + " Swap",
+				Slug:           pool.network.Slug + "-swap",
+				Status:         &status,
+				Type:           &kind,
+				NumMarketPairs: new(int),
+				LastUpdated:    &now,
+			})
+			for _, t := range targets {
+				listings[i].Quote = append(listings[i].Quote, cmc.DexListingQuote{
+					ConvertID:          t.key,
+					MarketShare:        new(float64),
+					NumTransactions24h: new(int),
+					Volume24h:          new(float64),
+					LastUpdated:        &now,
+				})
+			}
+		}
+
+		listing := &listings[i]
+		*listing.NumMarketPairs++
+		for j, t := range targets {
+			quote := listing.Quote[j]
+			*quote.Volume24h += pool.volume() * t.rate
+			*quote.NumTransactions24h += pool.transactions()
+			*quote.MarketShare += percentOf(pool.volume(), total)
+		}
+	}
+
+	listings, err = page(query, listings, 100)
+	if err != nil {
+		return nil, 0, err
+	}
+	return listings, len(listings), nil
+}
+
+// dexSpotPairs serves the pools matching the network, DEX and asset filters, largest
+// first.
+func (s *Server) dexSpotPairs(query url.Values) (any, int, *apiError) {
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filters := map[string]func(cmc.DexPairInfo) string{
+		"dex_id":                       func(p cmc.DexPairInfo) string { return p.DexID },
+		"dex_slug":                     func(p cmc.DexPairInfo) string { return p.DexSlug },
+		"base_asset_id":                func(p cmc.DexPairInfo) string { return p.BaseAssetID },
+		"base_asset_symbol":            func(p cmc.DexPairInfo) string { return p.BaseAssetSymbol },
+		"base_asset_contract_address":  func(p cmc.DexPairInfo) string { return p.BaseAssetContractAddress },
+		"quote_asset_id":               func(p cmc.DexPairInfo) string { return p.QuoteAssetID },
+		"quote_asset_symbol":           func(p cmc.DexPairInfo) string { return p.QuoteAssetSymbol },
+		"quote_asset_contract_address": func(p cmc.DexPairInfo) string { return p.QuoteAssetContractAddress },
+	}
+
+	var pairs []cmc.DexPair
+	for _, pool := range s.dexPools() {
+		info, match := pool.info(), pool.matchesNetwork(query)
+		for param, field := range filters {
+			if value := query.Get(param); value != "" && !strings.EqualFold(value, field(info)) {
+				match = false
+			}
+		}
+		if match {
+			pairs = append(pairs, s.dexPair(pool, targets))
+		}
+	}
+
+	pairs, err = page(query, pairs, 100)
+	if err != nil {
+		return nil, 0, err
+	}
+	return pairs, len(pairs), nil
+}
+
+// lookupPools resolves the contract_address parameter. Unknown addresses fail unless
+// skip_invalid is set.
+func (s *Server) lookupPools(query url.Values) ([]dexPool, *apiError) {
+	addresses := splitList(query.Get("contract_address"))
+	if len(addresses) == 0 {
+		return nil, badRequest(`"contract_address" is required`)
+	}
+
+	var pools []dexPool
+	for _, address := range addresses {
+		found := false
+		for _, pool := range s.dexPools() {
+			if strings.EqualFold(pool.address, address) && pool.matchesNetwork(query) {
+				pools = append(pools, pool)
+				found = true
+			}
+		}
+		if !found && query.Get("skip_invalid") != "true" {
+			return nil, badRequest(`Invalid value for "contract_address": "%s"`, address)
+		}
+	}
+	return pools, nil
+}
+
+// dexPairQuotes serves the latest quotes of the requested pools.
+func (s *Server) dexPairQuotes(query url.Values) (any, int, *apiError) {
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	pools, err := s.lookupPools(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pairs := make([]cmc.DexPair, len(pools))
+	for i, pool := range pools {
+		pairs[i] = s.dexPair(pool, targets)
+	}
+	return pairs, len(pairs), nil
+}
+
+func (s *Server) dexPair(pool dexPool, targets []convertTarget) cmc.DexPair {
+	now := s.now().UTC()
+	token := pool.token
+	transactions := pool.transactions()
+	buys := transactions * 52 / 100
+	sells := transactions - buys
+	buyVolume := pool.volume() * 0.52
+	sellVolume := pool.volume() - buyVolume
+	created := token.DateAdded
+
+	pair := cmc.DexPair{
+		DexPairInfo:        pool.info(),
+		LastUpdated:        &now,
+		CreatedAt:          &created,
+		PoolCreated:        &created,
+		NumTransactions24h: &transactions,
+		Buys24h:            &buys,
+		Sells24h:           &sells,
+		BuyVolume24h:       &buyVolume,
+		SellVolume24h:      &sellVolume,
+	}
+	for _, t := range targets {
+		price := token.Price * t.rate
+		byQuote := token.Price / pool.network.Price
+		volume := pool.volume() * t.rate
+		liquidity := volume * 5
+		fdv := token.Price * token.TotalSupply * t.rate
+		change1h, change24h := token.PercentChange1h, token.PercentChange24h
+		pair.Quote = append(pair.Quote, cmc.DexPairQuote{
+			ConvertID:             t.key,
+			Price:                 &price,
+			PriceByQuoteAsset:     &byQuote,
+			Volume24h:             &volume,
+			Liquidity:             &liquidity,
+			FullyDilutedValue:     &fdv,
+			PercentChangePrice1h:  &change1h,
+			PercentChangePrice24h: &change24h,
+			LastUpdated:           &now,
+		})
+	}
+	return pair
+}
+
+// dexPairOHLCV serves the open daily candle of the requested pools.
+func (s *Server) dexPairOHLCV(query url.Values) (any, int, *apiError) {
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	pools, err := s.lookupPools(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := s.now().UTC()
+	data := make([]cmc.DexPairOHLCV, len(pools))
+	for i, pool := range pools {
+		day := now.Truncate(24 * time.Hour)
+		data[i] = cmc.DexPairOHLCV{
+			DexPairInfo: pool.info(),
+			LastUpdated: &now,
+			Quote:       dexOHLCVQuotes(s.candle(pool.token, day, 24*time.Hour), targets),
+		}
+	}
+	return data, len(data), nil
+}
+
+// dexPairOHLCVHistorical serves candles along the price path of each pool's token.
+func (s *Server) dexPairOHLCVHistorical(query url.Values) (any, int, *apiError) {
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	pools, err := s.lookupPools(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	times, step, err := s.historyTimes(query, cmc.IntervalDaily)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data := make([]cmc.DexPairOHLCVHistorical, len(pools))
+	points := 0
+	for i, pool := range pools {
+		data[i].DexPairInfo = pool.info()
+		for _, open := range times {
+			candle := s.candle(pool.token, open, step)
+			data[i].Quotes = append(data[i].Quotes, cmc.DexOHLCVPeriod{
+				TimeOpen:  candle.TimeOpen,
+				TimeClose: candle.TimeClose,
+				Quote:     dexOHLCVQuotes(candle, targets),
+			})
+		}
+		points += len(times)
+	}
+	return data, points, nil
+}
+
+// dexOHLCVQuotes converts a candle of a pool's token into every convert target, with
+// the pool's share of the volume.
+func dexOHLCVQuotes(candle cmc.OHLCV, targets []convertTarget) []cmc.DexOHLCVQuote {
+	quotes := make([]cmc.DexOHLCVQuote, len(targets))
+	for i, t := range targets {
+		o, h, l, c := *candle.Open*t.rate, *candle.High*t.rate, *candle.Low*t.rate, *candle.Close*t.rate
+		volume := *candle.Volume * dexShare * t.rate
+		quotes[i] = cmc.DexOHLCVQuote{
+			ConvertID: t.key,
+			Open:      &o,
+			High:      &h,
+			Low:       &l,
+			Close:     &c,
+			Volume:    &volume,
+			Timestamp: candle.Timestamp,
+		}
+	}
+	return quotes
+}
+
+// dexPairTrades serves the last ten trades of each requested pool, one every 37
+// seconds, alternating between buys and sells.
+func (s *Server) dexPairTrades(query url.Values) (any, int, *apiError) {
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	pools, err := s.lookupPools(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+	data := make([]cmc.DexPairTrades, len(pools))
+	for i, pool := range pools {
+		data[i].DexPairInfo = pool.info()
+		for j := 0; j < 10; j++ {
+			date := now.Add(-time.Duration(j) * 37 * time.Second)
+			kind := "buy"
+			if j%2 == 1 {
+				kind = "sell"
+			}
+
+			price := s.priceAt(pool.token, date)
+			usd := float64(500 * (j%4 + 1))
+			trade := cmc.DexTrade{
+				Date:            &date,
+				Type:            kind,
+				TransactionHash: fmt.Sprintf("0x%032x%032x", pool.token.ID, date.Unix()),
+			}
+			for _, t := range targets {
+				tradePrice, total := price*t.rate, usd*t.rate
+				byQuote := price / s.priceAt(pool.network, date)
+				base, quote := usd/price, usd/s.priceAt(pool.network, date)
+				trade.Quote = append(trade.Quote, cmc.DexTradeQuote{
+					ConvertID:         t.key,
+					Price:             &tradePrice,
+					PriceByQuoteAsset: &byQuote,
+					AmountBaseAsset:   &base,
+					AmountQuoteAsset:  &quote,
+					Total:             &total,
+				})
+			}
+			data[i].Trades = append(data[i].Trades, trade)
+		}
+	}
+	return data, len(data), nil
+}
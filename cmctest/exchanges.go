@@ -0,0 +1,159 @@
+package cmctest
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// volumeAt returns an exchange's 24h USD volume at t. Volumes follow a weekly wave of
+// five percent that ends at the exchange's current volume at the server's clock.
+func (s *Server) volumeAt(exchange Exchange, t time.Time) float64 {
+	return exchange.Volume24h * (1 + 0.05*(math.Sin(volumePhase(exchange, t))-math.Sin(volumePhase(exchange, s.now()))))
+}
+
+func volumePhase(exchange Exchange, t time.Time) float64 {
+	return 2*math.Pi*float64(t.Unix())/(7*86400) + float64(exchange.ID)
+}
+
+// volumeSince sums an exchange's daily volumes over the given number of days to now.
+func (s *Server) volumeSince(exchange Exchange, days int) float64 {
+	now := s.now()
+	var total float64
+	for i := 0; i < days; i++ {
+		total += s.volumeAt(exchange, now.Add(-time.Duration(i)*24*time.Hour))
+	}
+	return total
+}
+
+// exchangeQuotes serves the latest volumes of the exchanges given by id or slug.
+func (s *Server) exchangeQuotes(query url.Values) (any, int, *apiError) {
+	exchanges, err := s.lookupExchanges(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := s.now().UTC()
+	change := func(exchange Exchange, days int) *float64 {
+		previous := s.volumeAt(exchange, now.Add(-time.Duration(days)*24*time.Hour))
+		change := (exchange.Volume24h/previous - 1) * 100
+		return &change
+	}
+
+	data := make(map[string]cmc.ExchangeQuote, len(exchanges))
+	for key, exchange := range exchanges {
+		pairs, volume := exchange.NumMarketPairs, exchange.Volume24h
+		volume7d, volume30d := s.volumeSince(exchange, 7), s.volumeSince(exchange, 30)
+		change24h := change(exchange, 1)
+
+		quotes := make(map[string]*cmc.Quote, len(targets))
+		for _, t := range targets {
+			converted := volume * t.rate
+			quotes[t.key] = &cmc.Quote{Volume24h: &converted, PercentChange24h: change24h, LastUpdated: &now}
+		}
+
+		data[key] = cmc.ExchangeQuote{
+			ID:                     exchange.ID,
+			Name:                   exchange.Name,
+			Slug:                   exchange.Slug,
+			NumMarketPairs:         &pairs,
+			Volume24hReported:      &volume,
+			Volume24hAdjusted:      &volume,
+			Volume7dReported:       &volume7d,
+			Volume30dReported:      &volume30d,
+			PercentChangeVolume24h: change24h,
+			PercentChangeVolume7d:  change(exchange, 7),
+			PercentChangeVolume30d: change(exchange, 30),
+			LastUpdated:            now,
+			Quote:                  quotes,
+		}
+	}
+	return data, len(data), nil
+}
+
+// exchangeQuotesHistorical serves volumes along each exchange's volume path. Like the
+// API, a single exchange is served on its own and several are keyed by ID.
+func (s *Server) exchangeQuotesHistorical(query url.Values) (any, int, *apiError) {
+	exchanges, err := s.lookupExchanges(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	times, _, err := s.historyTimes(query, cmc.Interval5m)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data := make(map[string]cmc.ExchangeQuotesHistorical, len(exchanges))
+	for _, exchange := range exchanges {
+		quotes := make([]cmc.HistoricalQuote, len(times))
+		for i, t := range times {
+			timestamp := t
+			quote := make(map[string]*cmc.Quote, len(targets))
+			for _, target := range targets {
+				volume := s.volumeAt(exchange, t) * target.rate
+				quote[target.key] = &cmc.Quote{Volume24h: &volume, LastUpdated: &timestamp}
+			}
+			quotes[i] = cmc.HistoricalQuote{Timestamp: t, Quote: quote}
+		}
+		data[strconv.Itoa(exchange.ID)] = cmc.ExchangeQuotesHistorical{
+			ID:     exchange.ID,
+			Name:   exchange.Name,
+			Slug:   exchange.Slug,
+			Quotes: quotes,
+		}
+	}
+
+	if len(data) == 1 {
+		for _, history := range data {
+			return history, len(times), nil
+		}
+	}
+	return data, len(data) * len(times), nil
+}
+
+// exchangeAssets serves an exchange's wallets: one per active coin, together holding
+// the exchange's 24h volume split evenly across the coins.
+func (s *Server) exchangeAssets(query url.Values) (any, int, *apiError) {
+	id := query.Get("id")
+	if id == "" {
+		return nil, 0, badRequest(`"id" is required`)
+	}
+
+	var exchange *Exchange
+	for i := range s.exchanges {
+		if strconv.Itoa(s.exchanges[i].ID) == id {
+			exchange = &s.exchanges[i]
+		}
+	}
+	if exchange == nil {
+		return nil, 0, badRequest(`Invalid value for "id": "%s"`, id)
+	}
+
+	coins := s.activeCoins()
+	data := make([]cmc.ExchangeAsset, len(coins))
+	for i, coin := range coins {
+		platform := cmc.ExchangeAssetPlatform{CryptoID: coin.ID, Symbol: coin.Symbol, Name: coin.Name}
+		if coin.Platform != nil {
+			platform = cmc.ExchangeAssetPlatform{CryptoID: coin.Platform.ID, Symbol: coin.Platform.Symbol, Name: coin.Platform.Name}
+		}
+		data[i] = cmc.ExchangeAsset{
+			WalletAddress: fmt.Sprintf("0x%040x", exchange.ID*1_000_000+coin.ID),
+			Balance:       exchange.Volume24h / float64(len(coins)) / coin.Price,
+			Platform:      platform,
+			Currency:      cmc.ExchangeAssetCurrency{CryptoID: coin.ID, PriceUSD: coin.Price, Symbol: coin.Symbol, Name: coin.Name},
+		}
+	}
+	return data, len(data), nil
+}
@@ -0,0 +1,110 @@
+package cmctest
+
+import (
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// Coin is a cryptocurrency served by the fake. Market cap is derived from price and circulating supply.
+type Coin struct {
+	ID                int
+	Name              string
+	Symbol            string
+	Slug              string
+	Rank              int
+	Price             float64
+	Volume24h         float64
+	PercentChange1h   float64
+	PercentChange24h  float64
+	PercentChange7d   float64
+	CirculatingSupply float64
+	TotalSupply       float64
+	MaxSupply         float64 // zero means no max supply
+	DateAdded         time.Time
+	Tags              []string
+	Platform          *cmc.Platform
+	Inactive          bool // only listed by the map endpoint with listing_status=inactive
+}
+
+// MarketCap returns the coin's market capitalization in USD.
+func (c Coin) MarketCap() float64 {
+	return c.Price * c.CirculatingSupply
+}
+
+// Exchange is an exchange served by the fake.
+type Exchange struct {
+	ID             int
+	Name           string
+	Slug           string
+	Volume24h      float64
+	NumMarketPairs int
+	DateLaunched   time.Time
+}
+
+// Fiat is a fiat currency served by the fake. USDRate is the number of units per US dollar.
+type Fiat struct {
+	ID      int
+	Name    string
+	Sign    string
+	Symbol  string
+	USDRate float64
+}
+
+// DefaultCoins returns the cryptocurrencies a new Server is seeded with.
+func DefaultCoins() []Coin {
+	added := time.Date(2013, 4, 28, 0, 0, 0, 0, time.UTC)
+
+	return []Coin{
+		{
+			ID: 1, Name: "Bitcoin", Symbol: "BTC", Slug: "bitcoin", Rank: 1,
+			Price: 65000, Volume24h: 30e9, PercentChange1h: 0.1, PercentChange24h: 1.5, PercentChange7d: 4.2,
+			CirculatingSupply: 19.7e6, TotalSupply: 19.7e6, MaxSupply: 21e6,
+			DateAdded: added, Tags: []string{"mineable", "pow"},
+		},
+		{
+			ID: 1027, Name: "Ethereum", Symbol: "ETH", Slug: "ethereum", Rank: 2,
+			Price: 3500, Volume24h: 15e9, PercentChange1h: -0.2, PercentChange24h: 2.1, PercentChange7d: 6.5,
+			CirculatingSupply: 120e6, TotalSupply: 120e6,
+			DateAdded: added.AddDate(2, 3, 9), Tags: []string{"pos", "smart-contracts"},
+		},
+		{
+			ID: 825, Name: "Tether USDt", Symbol: "USDT", Slug: "tether", Rank: 3,
+			Price: 1, Volume24h: 50e9, PercentChange24h: 0.01,
+			CirculatingSupply: 110e9, TotalSupply: 112e9,
+			DateAdded: added.AddDate(1, 10, 27), Tags: []string{"stablecoin"},
+			Platform: &cmc.Platform{ID: 1027, Name: "Ethereum", Symbol: "ETH", Slug: "ethereum", TokenAddress: "0xdac17f958d2ee523a2206206994597c13d831ec7"},
+		},
+		{
+			ID: 1839, Name: "BNB", Symbol: "BNB", Slug: "bnb", Rank: 4,
+			Price: 600, Volume24h: 2e9, PercentChange1h: 0.05, PercentChange24h: -0.8, PercentChange7d: 1.1,
+			CirculatingSupply: 150e6, TotalSupply: 150e6, MaxSupply: 200e6,
+			DateAdded: added.AddDate(4, 2, 28), Tags: []string{"marketplace"},
+		},
+		{
+			ID: 5426, Name: "Solana", Symbol: "SOL", Slug: "solana", Rank: 5,
+			Price: 150, Volume24h: 3e9, PercentChange1h: 0.3, PercentChange24h: 3.4, PercentChange7d: -2.5,
+			CirculatingSupply: 460e6, TotalSupply: 580e6,
+			DateAdded: added.AddDate(7, 0, 12), Tags: []string{"pos", "smart-contracts"},
+		},
+	}
+}
+
+// DefaultExchanges returns the exchanges a new Server is seeded with.
+func DefaultExchanges() []Exchange {
+	return []Exchange{
+		{ID: 270, Name: "Binance", Slug: "binance", Volume24h: 15e9, NumMarketPairs: 1800, DateLaunched: time.Date(2017, 7, 14, 0, 0, 0, 0, time.UTC)},
+		{ID: 89, Name: "Coinbase Exchange", Slug: "coinbase-exchange", Volume24h: 3e9, NumMarketPairs: 650, DateLaunched: time.Date(2014, 5, 24, 0, 0, 0, 0, time.UTC)},
+		{ID: 24, Name: "Kraken", Slug: "kraken", Volume24h: 1.2e9, NumMarketPairs: 900, DateLaunched: time.Date(2011, 7, 28, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+// DefaultFiats returns the fiat currencies a new Server is seeded with.
+func DefaultFiats() []Fiat {
+	return []Fiat{
+		{ID: 2781, Name: "United States Dollar", Sign: "$", Symbol: "USD", USDRate: 1},
+		{ID: 2790, Name: "Euro", Sign: "€", Symbol: "EUR", USDRate: 0.92},
+		{ID: 2791, Name: "Pound Sterling", Sign: "£", Symbol: "GBP", USDRate: 0.79},
+		{ID: 2797, Name: "Japanese Yen", Sign: "¥", Symbol: "JPY", USDRate: 150},
+	}
+}
@@ -0,0 +1,335 @@
+package cmctest
+
+import (
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// maxHistoryPoints caps the points of a historical series with time_start but no count.
+const maxHistoryPoints = 10000
+
+// priceAt returns a coin's USD price at t. Prices follow a deterministic path that ends
+// at the coin's current price at the server's clock: a trend compounding
+// PercentChange24h per day, with a small wave so that consecutive points differ.
+func (s *Server) priceAt(coin Coin, t time.Time) float64 {
+	now := s.now()
+	days := t.Sub(now).Hours() / 24
+	trend := math.Pow(1+coin.PercentChange24h/100, days)
+	wave := 1 + 0.005*(math.Sin(pricePhase(coin, t))-math.Sin(pricePhase(coin, now)))
+	return coin.Price * trend * wave
+}
+
+// pricePhase gives every coin a six-hour wave with its own offset.
+func pricePhase(coin Coin, t time.Time) float64 {
+	return 2*math.Pi*float64(t.Unix())/(6*3600) + float64(coin.ID)
+}
+
+// percentChangeAt returns the change of a coin's price over period ending at t.
+func (s *Server) percentChangeAt(coin Coin, t time.Time, period time.Duration) float64 {
+	return (s.priceAt(coin, t)/s.priceAt(coin, t.Add(-period)) - 1) * 100
+}
+
+// historyTimes returns the timestamps of a historical series from the time_start,
+// time_end, count and interval parameters, falling back to time_period and then to
+// defaultInterval. Timestamps are aligned to the interval and never in the future.
+// Without time_start, the series is the count points up to time_end.
+func (s *Server) historyTimes(query url.Values, defaultInterval cmc.Interval) ([]time.Time, time.Duration, *apiError) {
+	name := cmc.Interval(query.Get("interval"))
+	if name == "" {
+		name = cmc.Interval(query.Get("time_period"))
+	}
+	if name == "" {
+		name = defaultInterval
+	}
+	step, ok := intervalDuration(name)
+	if !ok {
+		return nil, 0, badRequest(`Invalid value for "interval": "%s"`, name)
+	}
+
+	count := 10
+	if value := query.Get("count"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxHistoryPoints {
+			return nil, 0, badRequest(`"count" must be between 1 and %d`, maxHistoryPoints)
+		}
+		count = n
+	}
+
+	end := s.now().UTC()
+	if value := query.Get("time_end"); value != "" {
+		t, ok := parseTime(value)
+		if !ok {
+			return nil, 0, badRequest(`Invalid value for "time_end": "%s"`, value)
+		}
+		if t.Before(end) {
+			end = t
+		}
+	}
+
+	var times []time.Time
+	if value := query.Get("time_start"); value != "" {
+		start, ok := parseTime(value)
+		if !ok {
+			return nil, 0, badRequest(`Invalid value for "time_start": "%s"`, value)
+		}
+		if start.After(end) {
+			return nil, 0, badRequest(`"time_start" must be before "time_end"`)
+		}
+		if query.Get("count") == "" {
+			count = maxHistoryPoints
+		}
+
+		first := start.Truncate(step)
+		if first.Before(start) {
+			first = first.Add(step)
+		}
+		for t := first; !t.After(end) && len(times) < count; t = t.Add(step) {
+			times = append(times, t)
+		}
+		return times, step, nil
+	}
+
+	last := end.Truncate(step)
+	for i := count - 1; i >= 0; i-- {
+		times = append(times, last.Add(-time.Duration(i)*step))
+	}
+	return times, step, nil
+}
+
+// intervalDuration returns the length of an API interval such as "5m", "daily" or "7d".
+func intervalDuration(interval cmc.Interval) (time.Duration, bool) {
+	const day = 24 * time.Hour
+
+	switch interval {
+	case cmc.IntervalHourly:
+		return time.Hour, true
+	case cmc.IntervalDaily:
+		return day, true
+	case cmc.IntervalWeekly:
+		return 7 * day, true
+	case cmc.IntervalMonthly:
+		return 30 * day, true
+	case cmc.IntervalYearly:
+		return 365 * day, true
+	}
+
+	s := string(interval)
+	if n, err := strconv.Atoi(strings.TrimSuffix(s, "d")); err == nil && strings.HasSuffix(s, "d") && n > 0 {
+		return time.Duration(n) * day, true
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 && (strings.HasSuffix(s, "m") || strings.HasSuffix(s, "h")) {
+		return d, true
+	}
+	return 0, false
+}
+
+// parseTime accepts the time formats of the API: RFC 3339, a date or Unix seconds.
+func parseTime(value string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), true
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, true
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+// historyKey returns the response key of a lookup: the symbol for symbol lookups and the
+// ID otherwise.
+func historyKey(key string, coin Coin) string {
+	if strings.EqualFold(key, coin.Symbol) {
+		return strings.ToUpper(key)
+	}
+	return strconv.Itoa(coin.ID)
+}
+
+// cryptocurrencyQuotesHistorical serves quotes along each coin's price path. Like the
+// latest quotes, symbol lookups list every coin with the symbol.
+func (s *Server) cryptocurrencyQuotesHistorical(query url.Values) (any, int, *apiError) {
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	matches, err := s.lookupCoins(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	times, _, err := s.historyTimes(query, cmc.Interval5m)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	history := func(coin Coin) cmc.CryptocurrencyQuotesHistorical {
+		quotes := make([]cmc.HistoricalQuote, len(times))
+		for i, t := range times {
+			quotes[i] = cmc.HistoricalQuote{Timestamp: t, Quote: s.historicalQuote(coin, t, targets)}
+		}
+		active, fiat := 1, 0
+		if coin.Inactive {
+			active = 0
+		}
+		return cmc.CryptocurrencyQuotesHistorical{ID: coin.ID, Name: coin.Name, Symbol: coin.Symbol, IsActive: &active, IsFiat: &fiat, Quotes: quotes}
+	}
+
+	if query.Get("symbol") != "" && query.Get("id") == "" && query.Get("slug") == "" {
+		data := make(map[string][]cmc.CryptocurrencyQuotesHistorical, len(matches))
+		for symbol, coins := range matches {
+			for _, coin := range coins {
+				data[symbol] = append(data[symbol], history(coin))
+			}
+		}
+		return data, len(data) * len(times), nil
+	}
+
+	data := make(map[string]cmc.CryptocurrencyQuotesHistorical, len(matches))
+	for _, coins := range matches {
+		data[strconv.Itoa(coins[0].ID)] = history(coins[0])
+	}
+	return data, len(data) * len(times), nil
+}
+
+// historicalQuote builds the quote of a coin at t in every convert target.
+func (s *Server) historicalQuote(coin Coin, t time.Time, targets []convertTarget) map[string]*cmc.Quote {
+	usd := s.priceAt(coin, t)
+	change1h := s.percentChangeAt(coin, t, time.Hour)
+	change24h := s.percentChangeAt(coin, t, 24*time.Hour)
+	change7d := s.percentChangeAt(coin, t, 7*24*time.Hour)
+
+	quotes := make(map[string]*cmc.Quote, len(targets))
+	for _, target := range targets {
+		price := usd * target.rate
+		volume := coin.Volume24h * target.rate
+		marketCap := usd * coin.CirculatingSupply * target.rate
+		timestamp := t
+		quotes[target.key] = &cmc.Quote{
+			Price:            &price,
+			Volume24h:        &volume,
+			MarketCap:        &marketCap,
+			PercentChange1h:  &change1h,
+			PercentChange24h: &change24h,
+			PercentChange7d:  &change7d,
+			LastUpdated:      &timestamp,
+		}
+	}
+	return quotes
+}
+
+// cryptocurrencyOHLCVHistorical serves daily candles, or candles of the requested
+// interval, along each coin's price path.
+func (s *Server) cryptocurrencyOHLCVHistorical(query url.Values) (any, int, *apiError) {
+	matches, err := s.lookupCoins(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	times, step, err := s.historyTimes(query, cmc.IntervalDaily)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data := make(map[string][]cmc.OHLCV, len(matches))
+	points := 0
+	for key, coins := range matches {
+		coin := coins[0]
+		candles := make([]cmc.OHLCV, len(times))
+		for i, open := range times {
+			candles[i] = s.candle(coin, open, step)
+		}
+		data[historyKey(key, coin)] = candles
+		points += len(candles)
+	}
+	return data, points, nil
+}
+
+// candle builds the USD candle of a coin for the period starting at open. The high and
+// low are sampled along the price path; a period that has not ended closes at the
+// current price.
+func (s *Server) candle(coin Coin, open time.Time, step time.Duration) cmc.OHLCV {
+	closeTime := open.Add(step)
+	if now := s.now().UTC(); closeTime.After(now) {
+		closeTime = now
+	}
+
+	openPrice, closePrice := s.priceAt(coin, open), s.priceAt(coin, closeTime)
+	high, low := max(openPrice, closePrice), min(openPrice, closePrice)
+	highTime, lowTime := open, open
+	if closePrice > openPrice {
+		highTime = closeTime
+	} else {
+		lowTime = closeTime
+	}
+	const samples = 12
+	for i := 1; i < samples; i++ {
+		t := open.Add(closeTime.Sub(open) * time.Duration(i) / samples)
+		switch price := s.priceAt(coin, t); {
+		case price > high:
+			high, highTime = price, t
+		case price < low:
+			low, lowTime = price, t
+		}
+	}
+
+	volume := coin.Volume24h * float64(closeTime.Sub(open)) / float64(24*time.Hour)
+	marketCap := closePrice * coin.CirculatingSupply
+	return cmc.OHLCV{
+		TimeOpen:  &open,
+		TimeClose: &closeTime,
+		TimeHigh:  &highTime,
+		TimeLow:   &lowTime,
+		Open:      &openPrice,
+		High:      &high,
+		Low:       &low,
+		Close:     &closePrice,
+		Volume:    &volume,
+		MarketCap: &marketCap,
+		Timestamp: &closeTime,
+	}
+}
+
+// pricePerformanceStats serves each coin's performance over the periods in
+// time_period, all_time by default, from candles spanning each period.
+func (s *Server) pricePerformanceStats(query url.Values) (any, int, *apiError) {
+	periods := splitList(query.Get("time_period"))
+	if len(periods) == 0 {
+		periods = []string{string(cmc.TimePeriodAllTime)}
+	}
+	for _, period := range periods {
+		if !validPeriod(cmc.TimePeriod(period)) {
+			return nil, 0, badRequest(`Invalid value for "time_period": "%s"`, period)
+		}
+	}
+	matches, err := s.lookupCoins(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data := make(map[string]cmc.PricePerformanceStats, len(matches))
+	for key, coins := range matches {
+		coin := coins[0]
+		roi := make(map[string]*cmc.PerformancePeriod, len(periods))
+		for _, period := range periods {
+			open, closeTime := s.periodWindow(coin, cmc.TimePeriod(period))
+			candle := s.candle(coin, open, closeTime.Sub(open))
+			roi[period] = &cmc.PerformancePeriod{
+				Period:     period,
+				OpenPrice:  candle.Open,
+				HighPrice:  candle.High,
+				LowPrice:   candle.Low,
+				ClosePrice: candle.Close,
+				OpenTime:   candle.TimeOpen,
+				HighTime:   candle.TimeHigh,
+				LowTime:    candle.TimeLow,
+				CloseTime:  candle.TimeClose,
+			}
+		}
+		data[historyKey(key, coin)] = cmc.PricePerformanceStats{ROI: roi}
+	}
+	return data, len(data), nil
+}
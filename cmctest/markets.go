@@ -0,0 +1,163 @@
+package cmctest
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// cryptocurrencyMarketPairs serves the pairs of one coin on every exchange, largest
+// first. See marketPair.
+func (s *Server) cryptocurrencyMarketPairs(query url.Values) (any, int, *apiError) {
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	matches, err := s.lookupCoins(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data := make(map[string][]cmc.MarketPair, len(matches))
+	pairs := 0
+	for key, coins := range matches {
+		coin := coins[0]
+		var markets []cmc.MarketPair
+		for _, exchange := range s.exchangesByVolume() {
+			markets = append(markets, s.marketPair(exchange, coin, targets))
+		}
+
+		markets, err := page(query, filterPairs(query, markets), 100)
+		if err != nil {
+			return nil, 0, err
+		}
+		data[historyKey(key, coin)] = markets
+		pairs += len(markets)
+	}
+	return data, pairs, nil
+}
+
+// exchangeMarketPairs serves the pairs of every active coin on one exchange, largest
+// first. See marketPair.
+func (s *Server) exchangeMarketPairs(query url.Values) (any, int, *apiError) {
+	id, slug := query.Get("id"), query.Get("slug")
+	if id == "" && slug == "" {
+		return nil, 0, badRequest(`"value" must contain at least one of [id, slug]`)
+	}
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var exchange *Exchange
+	for i := range s.exchanges {
+		if strconv.Itoa(s.exchanges[i].ID) == id || s.exchanges[i].Slug == slug {
+			exchange = &s.exchanges[i]
+		}
+	}
+	if exchange == nil {
+		return nil, 0, badRequest(`Invalid value for "id" or "slug"`)
+	}
+
+	coins := s.coinsByRank()
+	sort.SliceStable(coins, func(i, j int) bool {
+		return coins[i].Volume24h > coins[j].Volume24h
+	})
+
+	var markets []cmc.MarketPair
+	for _, coin := range coins {
+		if !coin.Inactive {
+			markets = append(markets, s.marketPair(*exchange, coin, targets))
+		}
+	}
+
+	markets, err = page(query, filterPairs(query, markets), 100)
+	if err != nil {
+		return nil, 0, err
+	}
+	return markets, len(markets), nil
+}
+
+// marketPair builds the pair of coin on exchange. Coins trade against USDT, or USDT
+// against USD, and an exchange's share of a coin's volume is its share of the
+// exchanges' total volume.
+func (s *Server) marketPair(exchange Exchange, coin Coin, targets []convertTarget) cmc.MarketPair {
+	now := s.now().UTC()
+	base := pairCurrency(coin.ID, coin.Name, coin.Symbol, coin.Slug)
+	quote := pairCurrency(2781, "United States Dollar", "USD", "usd")
+	if usdt, ok := s.coinBySymbol("USDT"); ok && coin.Symbol != "USDT" {
+		quote = pairCurrency(usdt.ID, usdt.Name, usdt.Symbol, usdt.Slug)
+	}
+
+	var total float64
+	for _, e := range s.exchanges {
+		total += e.Volume24h
+	}
+	usdVolume := coin.Volume24h * exchange.Volume24h / max(total, 1)
+
+	quotes := make(map[string]*cmc.Quote, len(targets))
+	for _, t := range targets {
+		price, volume, updated := coin.Price*t.rate, usdVolume*t.rate, now
+		quotes[t.key] = &cmc.Quote{Price: &price, Volume24h: &volume, LastUpdated: &updated}
+	}
+
+	marketURL := fmt.Sprintf("https://%s.com/trade/%s_%s", exchange.Slug, base.CurrencySymbol, quote.CurrencySymbol)
+	return cmc.MarketPair{
+		ExchangeID:      exchange.ID,
+		ExchangeName:    exchange.Name,
+		ExchangeSlug:    exchange.Slug,
+		MarketID:        exchange.ID*100000 + coin.ID,
+		MarketPair:      base.CurrencySymbol + "/" + quote.CurrencySymbol,
+		MarketPairBase:  base,
+		MarketPairQuote: quote,
+		MarketURL:       &marketURL,
+		Category:        "spot",
+		FeeType:         "percentage",
+		Quote:           quotes,
+		LastUpdated:     now,
+	}
+}
+
+func pairCurrency(id int, name, symbol, slug string) cmc.MarketPairCurrency {
+	return cmc.MarketPairCurrency{CurrencyID: id, CurrencyName: name, CurrencySymbol: symbol, CurrencySlug: slug, ExchangeSymbol: symbol}
+}
+
+// filterPairs applies the matched_id and matched_symbol parameters, which select pairs
+// by the currency the base trades against.
+func filterPairs(query url.Values, markets []cmc.MarketPair) []cmc.MarketPair {
+	ids, symbols := splitList(query.Get("matched_id")), splitList(query.Get("matched_symbol"))
+	if len(ids) == 0 && len(symbols) == 0 {
+		return markets
+	}
+
+	var matched []cmc.MarketPair
+	for _, m := range markets {
+		if contains(ids, strconv.Itoa(m.MarketPairQuote.CurrencyID)) || contains(symbols, m.MarketPairQuote.CurrencySymbol) {
+			matched = append(matched, m)
+		}
+	}
+	return matched
+}
+
+// exchangesByVolume returns a copy of the seeded exchanges, largest first.
+func (s *Server) exchangesByVolume() []Exchange {
+	exchanges := append([]Exchange(nil), s.exchanges...)
+	sort.SliceStable(exchanges, func(i, j int) bool {
+		return exchanges[i].Volume24h > exchanges[j].Volume24h
+	})
+	return exchanges
+}
+
+// coinBySymbol returns the best-ranked coin with symbol.
+func (s *Server) coinBySymbol(symbol string) (Coin, bool) {
+	for _, coin := range s.coinsByRank() {
+		if strings.EqualFold(coin.Symbol, symbol) {
+			return coin, true
+		}
+	}
+	return Coin{}, false
+}
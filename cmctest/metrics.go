@@ -0,0 +1,245 @@
+package cmctest
+
+import (
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// fearAndGreedHistory is the number of days of fear and greed readings served.
+const fearAndGreedHistory = 730
+
+// fearAndGreedAt returns the fear and greed reading at t. The index rises 8 points for
+// every percent the market moved over the past day, on top of a 17-day mood cycle.
+func (s *Server) fearAndGreedAt(t time.Time) cmc.FearAndGreed {
+	var marketCap, previousCap float64
+	for _, coin := range s.activeCoins() {
+		marketCap += s.priceAt(coin, t) * coin.CirculatingSupply
+		previousCap += s.priceAt(coin, t.Add(-24*time.Hour)) * coin.CirculatingSupply
+	}
+	change := percentOf(marketCap-previousCap, previousCap)
+	days := float64(t.Unix()) / 86400
+	value := int(math.Round(min(100, max(0, 50+8*change+15*math.Sin(2*math.Pi*days/17)))))
+
+	var classification string
+	switch {
+	case value < 25:
+		classification = "Extreme fear"
+	case value < 45:
+		classification = "Fear"
+	case value <= 55:
+		classification = "Neutral"
+	case value <= 75:
+		classification = "Greed"
+	default:
+		classification = "Extreme greed"
+	}
+	return cmc.FearAndGreed{Value: value, ValueClassification: classification, Timestamp: t}
+}
+
+func (s *Server) fearAndGreedLatest() (any, int, *apiError) {
+	now := s.now().UTC()
+	reading := s.fearAndGreedAt(now)
+	reading.UpdateTime = &now
+	return reading, 1, nil
+}
+
+// fearAndGreedHistorical serves daily readings, newest first.
+func (s *Server) fearAndGreedHistorical(query url.Values) (any, int, *apiError) {
+	today := s.now().UTC().Truncate(24 * time.Hour)
+	days := make([]time.Time, fearAndGreedHistory)
+	for i := range days {
+		days[i] = today.Add(-time.Duration(i) * 24 * time.Hour)
+	}
+
+	days, err := page(query, days, 50)
+	if err != nil {
+		return nil, 0, err
+	}
+	data := make([]cmc.FearAndGreed, len(days))
+	for i, day := range days {
+		data[i] = s.fearAndGreedAt(day)
+	}
+	return data, len(data), nil
+}
+
+func isIndex(name string) bool {
+	switch cmc.Index(name) {
+	case cmc.IndexCMC100, cmc.IndexCMC20, cmc.IndexAltcoinSeason:
+		return true
+	}
+	return false
+}
+
+// indexAt returns the level of an index at t. The CMC100 and CMC20 hold the largest
+// coins other than stablecoins, weighted by market cap, at a level of their total market
+// cap in units of $10 billion. The altcoin season index is the share of those coins,
+// other than Bitcoin, that beat Bitcoin over 90 days.
+func (s *Server) indexAt(index cmc.Index, t time.Time) (float64, []cmc.IndexConstituent) {
+	var coins []Coin
+	for _, coin := range s.activeCoins() {
+		if !slices.Contains(coin.Tags, "stablecoin") {
+			coins = append(coins, coin)
+		}
+	}
+
+	if index == cmc.IndexAltcoinSeason {
+		const season = 90 * 24 * time.Hour
+		var btc float64
+		for _, coin := range coins {
+			if coin.Symbol == "BTC" {
+				btc = s.percentChangeAt(coin, t, season)
+			}
+		}
+		var altcoins, beating int
+		for _, coin := range coins {
+			if coin.Symbol != "BTC" {
+				altcoins++
+				if s.percentChangeAt(coin, t, season) > btc {
+					beating++
+				}
+			}
+		}
+		return math.Round(percentOf(float64(beating), float64(altcoins))), nil
+	}
+
+	size := 100
+	if index == cmc.IndexCMC20 {
+		size = 20
+	}
+	coins = coins[:min(size, len(coins))]
+
+	var total float64
+	caps := make([]float64, len(coins))
+	for i, coin := range coins {
+		caps[i] = s.priceAt(coin, t) * coin.CirculatingSupply
+		total += caps[i]
+	}
+	constituents := make([]cmc.IndexConstituent, len(coins))
+	for i, coin := range coins {
+		constituents[i] = cmc.IndexConstituent{
+			ID:     coin.ID,
+			Name:   coin.Name,
+			Symbol: coin.Symbol,
+			URL:    "https://coinmarketcap.com/currencies/" + coin.Slug + "/",
+			Weight: percentOf(caps[i], total),
+		}
+	}
+	return total / 1e10, constituents
+}
+
+// indexValue returns the reading of an index at t with its change over the past day.
+func (s *Server) indexValue(index cmc.Index, t time.Time) cmc.IndexValue {
+	value, constituents := s.indexAt(index, t)
+	reading := cmc.IndexValue{Value: value, Constituents: constituents}
+	if previous, _ := s.indexAt(index, t.Add(-24*time.Hour)); previous != 0 {
+		change := (value/previous - 1) * 100
+		reading.Value24hPercentageChange = &change
+	}
+	return reading
+}
+
+// indexLatest serves the current reading of an index, which updates every five minutes.
+func (s *Server) indexLatest(index cmc.Index) (any, int, *apiError) {
+	now := s.now().UTC()
+	next := now.Truncate(5 * time.Minute).Add(5 * time.Minute)
+
+	reading := s.indexValue(index, now)
+	reading.LastUpdate, reading.NextUpdate = &now, &next
+	return reading, 1, nil
+}
+
+// indexHistorical serves daily readings of an index, or readings of the requested
+// interval, oldest first.
+func (s *Server) indexHistorical(index cmc.Index, query url.Values) (any, int, *apiError) {
+	times, _, err := s.historyTimes(query, cmc.IntervalDaily)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data := make([]cmc.IndexValue, len(times))
+	for i, t := range times {
+		data[i] = s.indexValue(index, t)
+		data[i].UpdateTime = &times[i]
+	}
+	return data, len(data), nil
+}
+
+// globalMetricsHistorical serves daily global metrics, or metrics of the requested
+// interval, along the coins' price paths.
+func (s *Server) globalMetricsHistorical(query url.Values) (any, int, *apiError) {
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	times, _, err := s.historyTimes(query, cmc.IntervalDaily)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data := make([]cmc.GlobalMetrics, len(times))
+	for i, t := range times {
+		data[i] = s.globalMetricsAt(t, targets)
+	}
+	return data, len(data), nil
+}
+
+// blockchainStatistics serves chain statistics for coins with their own blockchain.
+// Mineable coins produce a block every ten minutes, others every twelve seconds, since
+// the coin was added.
+func (s *Server) blockchainStatistics(query url.Values) (any, int, *apiError) {
+	matches, err := s.lookupCoins(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := s.now().UTC()
+	data := make(map[string]cmc.BlockchainStats, len(matches))
+	for key, coins := range matches {
+		coin := coins[0]
+		if coin.Platform != nil {
+			return nil, 0, badRequest(`No blockchain statistics for "%s"`, key)
+		}
+
+		blockTime, algorithm := 12, (*string)(nil)
+		if slices.Contains(coin.Tags, "mineable") {
+			sha256 := "SHA-256"
+			blockTime, algorithm = 600, &sha256
+		}
+		perDay := 86400 / blockTime
+		height := int(now.Sub(coin.DateAdded).Seconds()) / blockTime
+		lastBlock := coin.DateAdded.Add(time.Duration(height*blockTime) * time.Second)
+		yearStart := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		countYTD := int(now.Sub(yearStart).Seconds()) / blockTime
+		count30d := 30 * perDay
+
+		day := s.candle(coin, now.Add(-24*time.Hour), 24*time.Hour)
+		transactions := int(coin.Volume24h / 5000)
+		meanValue := coin.Volume24h / coin.Price / float64(max(transactions, 1))
+
+		data[historyKey(key, coin)] = cmc.BlockchainStats{
+			ID:                     coin.ID,
+			Symbol:                 coin.Symbol,
+			Name:                   coin.Name,
+			TotalSupply:            strconv.FormatFloat(coin.TotalSupply, 'f', -1, 64),
+			Count24hInterval:       &perDay,
+			Count30dInterval:       &count30d,
+			CountYtdInterval:       &countYTD,
+			FirstBlockTimestamp:    coin.DateAdded,
+			FirstPricedTimestamp:   coin.DateAdded,
+			HashAlgorithm:          algorithm,
+			High24h:                day.High,
+			Low24h:                 day.Low,
+			LastBlockHeight:        &height,
+			LastBlockTimestamp:     lastBlock,
+			MeanBlockTime:          &blockTime,
+			MeanTxValue:            &meanValue,
+			Sum24hTransactionCount: &transactions,
+		}
+	}
+	return data, len(data), nil
+}
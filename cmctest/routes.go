@@ -0,0 +1,807 @@
+package cmctest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+type apiError struct {
+	statusCode int
+	errorCode  cmc.ErrorCode
+	message    string
+}
+
+func badRequest(format string, args ...any) *apiError {
+	return &apiError{http.StatusBadRequest, cmc.ErrorCodeBadRequest, fmt.Sprintf(format, args...)}
+}
+
+// route returns the data for an endpoint and the number of items it holds. Callers must hold s.mu.
+func (s *Server) route(endpoint string, query url.Values, usage *keyUsage) (any, int, *apiError) {
+	if data, ok := s.fixtures[endpoint]; ok {
+		return data, 1, nil
+	}
+
+	switch endpoint {
+	case "/v1/cryptocurrency/map":
+		return s.cryptocurrencyMap(query)
+	case "/v2/cryptocurrency/info":
+		return s.cryptocurrencyInfo(query)
+	case "/v1/cryptocurrency/listings/latest", "/v1/cryptocurrency/listings/historical", "/v1/cryptocurrency/listings/new":
+		return s.cryptocurrencyListings(query)
+	case "/v2/cryptocurrency/quotes/latest":
+		return s.cryptocurrencyQuotes(query)
+	case "/v2/cryptocurrency/quotes/historical", "/v3/cryptocurrency/quotes/historical":
+		return s.cryptocurrencyQuotesHistorical(query)
+	case "/v2/cryptocurrency/ohlcv/latest":
+		return s.cryptocurrencyOHLCV(query)
+	case "/v2/cryptocurrency/ohlcv/historical":
+		return s.cryptocurrencyOHLCVHistorical(query)
+	case "/v2/cryptocurrency/market-pairs/latest":
+		return s.cryptocurrencyMarketPairs(query)
+	case "/v1/global-metrics/quotes/latest":
+		return s.globalMetrics(query)
+	case "/v2/tools/price-conversion":
+		return s.priceConversion(query)
+	case "/v1/fiat/map":
+		return s.fiatMap(query)
+	case "/v1/exchange/map":
+		return s.exchangeMap(query)
+	case "/v1/exchange/info":
+		return s.exchangeInfo(query)
+	case "/v1/exchange/listings/latest":
+		return s.exchangeListings(query)
+	case "/v1/exchange/market-pairs/latest":
+		return s.exchangeMarketPairs(query)
+	case "/v4/dex/networks/list":
+		return s.dexNetworks(query)
+	case "/v4/dex/listings/quotes":
+		return s.dexListings(query)
+	case "/v4/dex/spot-pairs/latest":
+		return s.dexSpotPairs(query)
+	case "/v4/dex/pairs/quotes/latest":
+		return s.dexPairQuotes(query)
+	case "/v4/dex/pairs/ohlcv/latest":
+		return s.dexPairOHLCV(query)
+	case "/v4/dex/pairs/ohlcv/historical":
+		return s.dexPairOHLCVHistorical(query)
+	case "/v4/dex/pairs/trade/latest":
+		return s.dexPairTrades(query)
+	case "/v2/cryptocurrency/price-performance-stats/latest":
+		return s.pricePerformanceStats(query)
+	case "/v1/cryptocurrency/categories":
+		return s.cryptocurrencyCategories(query)
+	case "/v1/cryptocurrency/category":
+		return s.cryptocurrencyCategory(query)
+	case "/v1/cryptocurrency/airdrops":
+		return s.cryptocurrencyAirdrops(query)
+	case "/v1/cryptocurrency/airdrop":
+		return s.cryptocurrencyAirdrop(query)
+	case "/v1/cryptocurrency/trending/latest":
+		return s.cryptocurrencyTrending(query, trendingSearches)
+	case "/v1/cryptocurrency/trending/most-visited":
+		return s.cryptocurrencyTrending(query, trendingVisits)
+	case "/v1/cryptocurrency/trending/gainers-losers":
+		return s.cryptocurrencyGainersLosers(query)
+	case "/v1/global-metrics/quotes/historical":
+		return s.globalMetricsHistorical(query)
+	case "/v1/blockchain/statistics/latest":
+		return s.blockchainStatistics(query)
+	case "/v1/exchange/quotes/latest":
+		return s.exchangeQuotes(query)
+	case "/v1/exchange/quotes/historical":
+		return s.exchangeQuotesHistorical(query)
+	case "/v1/exchange/assets":
+		return s.exchangeAssets(query)
+	case "/v1/content/latest":
+		return s.contentLatest(query)
+	case "/v1/content/posts/top":
+		return s.contentPosts(query, true)
+	case "/v1/content/posts/latest":
+		return s.contentPosts(query, false)
+	case "/v1/content/posts/comments":
+		return s.contentComments(query)
+	case "/v1/community/trending/topic":
+		return s.communityTrendingTopics(query)
+	case "/v1/community/trending/token":
+		return s.communityTrendingTokens(query)
+	case "/v3/fear-and-greed/latest":
+		return s.fearAndGreedLatest()
+	case "/v3/fear-and-greed/historical":
+		return s.fearAndGreedHistorical(query)
+	case "/v1/tools/postman":
+		return s.postmanCollection(), 1, nil
+	case "/v1/key/info":
+		return s.keyInfo(usage), 1, nil
+	}
+
+	if name, ok := strings.CutPrefix(endpoint, "/v3/index/"); ok {
+		if index, ok := strings.CutSuffix(name, "-latest"); ok && isIndex(index) {
+			return s.indexLatest(cmc.Index(index))
+		}
+		if index, ok := strings.CutSuffix(name, "-historical"); ok && isIndex(index) {
+			return s.indexHistorical(cmc.Index(index), query)
+		}
+	}
+
+	return nil, 0, &apiError{http.StatusNotFound, cmc.ErrorCodeNotFound, "Not Found"}
+}
+
+func (s *Server) cryptocurrencyMap(query url.Values) (any, int, *apiError) {
+	symbols := splitList(query.Get("symbol"))
+	statuses := splitList(query.Get("listing_status"))
+	if len(statuses) == 0 {
+		statuses = []string{string(cmc.StatusActive)}
+	}
+
+	var coins []Coin
+	for _, coin := range s.coinsByRank() {
+		status := cmc.StatusActive
+		if coin.Inactive {
+			status = cmc.StatusInactive
+		}
+		if contains(statuses, string(status)) && (len(symbols) == 0 || contains(symbols, coin.Symbol)) {
+			coins = append(coins, coin)
+		}
+	}
+
+	coins, err := page(query, coins, len(coins))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data := make([]cmc.CryptocurrencyMap, len(coins))
+	for i, coin := range coins {
+		rank, active := coin.Rank, 1
+		if coin.Inactive {
+			active = 0
+		}
+		data[i] = cmc.CryptocurrencyMap{
+			ID:       coin.ID,
+			Name:     coin.Name,
+			Symbol:   coin.Symbol,
+			Slug:     coin.Slug,
+			Rank:     &rank,
+			IsActive: &active,
+			Platform: coin.Platform,
+		}
+	}
+	return data, len(data), nil
+}
+
+func (s *Server) cryptocurrencyInfo(query url.Values) (any, int, *apiError) {
+	matches, err := s.lookupCoins(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data := make(map[string]cmc.CryptocurrencyInfo, len(matches))
+	for key, coins := range matches {
+		coin := coins[0]
+		category := "coin"
+		var contracts []cmc.ContractAddress
+		if coin.Platform != nil {
+			category = "token"
+			contracts = []cmc.ContractAddress{{ContractAddress: coin.Platform.TokenAddress, Platform: *coin.Platform}}
+		}
+		data[key] = cmc.CryptocurrencyInfo{
+			ID:        coin.ID,
+			Name:      coin.Name,
+			Symbol:    coin.Symbol,
+			Slug:      coin.Slug,
+			Category:  category,
+			Logo:      fmt.Sprintf("https://s2.coinmarketcap.com/static/img/coins/64x64/%d.png", coin.ID),
+			Tags:      coin.Tags,
+			Platform:  coin.Platform,
+			DateAdded: coin.DateAdded,
+			URLs:      map[string][]string{"website": {"https://" + coin.Slug + ".org"}},
+
+			ContractAddress: contracts,
+		}
+	}
+	return data, len(data), nil
+}
+
+func (s *Server) cryptocurrencyListings(query url.Values) (any, int, *apiError) {
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	coins := s.coinsByRank()
+	if err := sortCoins(coins, query.Get("sort"), query.Get("sort_dir")); err != nil {
+		return nil, 0, err
+	}
+
+	coins, err = page(query, coins, 100)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data := make([]cmc.CryptocurrencyListing, len(coins))
+	for i, coin := range coins {
+		data[i] = s.cryptocurrencyListing(coin, targets)
+	}
+	return data, len(data), nil
+}
+
+// cryptocurrencyListing builds the listing of a coin in every convert target.
+func (s *Server) cryptocurrencyListing(coin Coin, targets []convertTarget) cmc.CryptocurrencyListing {
+	quote := s.cryptocurrencyQuote(coin, targets)
+	return cmc.CryptocurrencyListing{
+		ID:                quote.ID,
+		Name:              quote.Name,
+		Symbol:            quote.Symbol,
+		Slug:              quote.Slug,
+		DateAdded:         quote.DateAdded,
+		Tags:              quote.Tags,
+		MaxSupply:         quote.MaxSupply,
+		CirculatingSupply: quote.CirculatingSupply,
+		TotalSupply:       quote.TotalSupply,
+		Platform:          quote.Platform,
+		CMCRank:           quote.CMCRank,
+		LastUpdated:       quote.LastUpdated,
+		Quote:             quote.Quote,
+	}
+}
+
+// cryptocurrencyQuotes mirrors the v2 response shape: objects keyed by ID for id and
+// slug lookups, and arrays keyed by symbol for symbol lookups.
+func (s *Server) cryptocurrencyQuotes(query url.Values) (any, int, *apiError) {
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matches, err := s.lookupCoins(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if query.Get("symbol") != "" && query.Get("id") == "" && query.Get("slug") == "" {
+		data := make(map[string][]cmc.CryptocurrencyQuote, len(matches))
+		for symbol, coins := range matches {
+			for _, coin := range coins {
+				data[symbol] = append(data[symbol], s.cryptocurrencyQuote(coin, targets))
+			}
+		}
+		return data, len(data), nil
+	}
+
+	data := make(map[string]cmc.CryptocurrencyQuote, len(matches))
+	for _, coins := range matches {
+		data[strconv.Itoa(coins[0].ID)] = s.cryptocurrencyQuote(coins[0], targets)
+	}
+	return data, len(data), nil
+}
+
+func (s *Server) cryptocurrencyOHLCV(query url.Values) (any, int, *apiError) {
+	matches, err := s.lookupCoins(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := s.now().UTC()
+	open := now.Truncate(24 * time.Hour)
+
+	data := make(map[string]cmc.OHLCV, len(matches))
+	for _, coins := range matches {
+		coin := coins[0]
+		openPrice := coin.Price / (1 + coin.PercentChange24h/100)
+		high := max(openPrice, coin.Price) * 1.01
+		low := min(openPrice, coin.Price) * 0.99
+		closePrice := coin.Price
+		volume := coin.Volume24h
+
+		data[strconv.Itoa(coin.ID)] = cmc.OHLCV{
+			TimeOpen:  &open,
+			Open:      &openPrice,
+			High:      &high,
+			Low:       &low,
+			Close:     &closePrice,
+			Volume:    &volume,
+			Timestamp: &now,
+		}
+	}
+	return data, len(data), nil
+}
+
+func (s *Server) globalMetrics(query url.Values) (any, int, *apiError) {
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.globalMetricsAt(s.now().UTC(), targets), 1, nil
+}
+
+// globalMetricsAt totals the market at t in every convert target.
+func (s *Server) globalMetricsAt(now time.Time, targets []convertTarget) cmc.GlobalMetrics {
+	var marketCap, volume, btc, eth float64
+	for _, coin := range s.coins {
+		coinCap := s.priceAt(coin, now) * coin.CirculatingSupply
+		marketCap += coinCap
+		volume += coin.Volume24h
+		switch coin.Symbol {
+		case "BTC":
+			btc = coinCap
+		case "ETH":
+			eth = coinCap
+		}
+	}
+
+	btcDominance := percentOf(btc, marketCap)
+	ethDominance := percentOf(eth, marketCap)
+	activeCryptocurrencies := len(s.coins)
+	activeExchanges := len(s.exchanges)
+
+	quotes := make(map[string]*cmc.Quote, len(targets))
+	for _, t := range targets {
+		totalCap, totalVolume := marketCap*t.rate, volume*t.rate
+		quotes[t.key] = &cmc.Quote{MarketCap: &totalCap, Volume24h: &totalVolume, LastUpdated: &now}
+	}
+
+	return cmc.GlobalMetrics{
+		BtcDominance:           &btcDominance,
+		EthDominance:           &ethDominance,
+		ActiveCryptocurrencies: &activeCryptocurrencies,
+		TotalCryptocurrencies:  &activeCryptocurrencies,
+		ActiveExchanges:        &activeExchanges,
+		TotalExchanges:         &activeExchanges,
+		LastUpdated:            now,
+		Quote:                  quotes,
+	}
+}
+
+func (s *Server) priceConversion(query url.Values) (any, int, *apiError) {
+	amount, parseErr := strconv.ParseFloat(query.Get("amount"), 64)
+	if parseErr != nil {
+		return nil, 0, badRequest(`"amount" is required and must be a number`)
+	}
+	if query.Get("id") == "" && query.Get("symbol") == "" {
+		return nil, 0, badRequest(`"value" must contain at least one of [id, symbol]`)
+	}
+
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matches, err := s.lookupCoins(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var coin Coin
+	for _, coins := range matches {
+		coin = coins[0]
+	}
+
+	now := s.now().UTC()
+	quotes := make(map[string]*cmc.ConversionQuote, len(targets))
+	for _, t := range targets {
+		quotes[t.key] = &cmc.ConversionQuote{Price: amount * coin.Price * t.rate, LastUpdated: now}
+	}
+
+	return cmc.PriceConversion{
+		ID:          strconv.Itoa(coin.ID),
+		Symbol:      coin.Symbol,
+		Name:        coin.Name,
+		Amount:      amount,
+		LastUpdated: now,
+		Quote:       quotes,
+	}, 1, nil
+}
+
+func (s *Server) fiatMap(query url.Values) (any, int, *apiError) {
+	fiats, err := page(query, s.fiats, len(s.fiats))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data := make([]cmc.FiatMap, len(fiats))
+	for i, fiat := range fiats {
+		data[i] = cmc.FiatMap{ID: fiat.ID, Name: fiat.Name, Sign: fiat.Sign, Symbol: fiat.Symbol}
+	}
+	return data, len(data), nil
+}
+
+func (s *Server) exchangeMap(query url.Values) (any, int, *apiError) {
+	slugs := splitList(query.Get("slug"))
+
+	var exchanges []Exchange
+	for _, exchange := range s.exchanges {
+		if len(slugs) == 0 || contains(slugs, exchange.Slug) {
+			exchanges = append(exchanges, exchange)
+		}
+	}
+
+	exchanges, err := page(query, exchanges, len(exchanges))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	active := 1
+	data := make([]cmc.ExchangeMap, len(exchanges))
+	for i, exchange := range exchanges {
+		data[i] = cmc.ExchangeMap{ID: exchange.ID, Name: exchange.Name, Slug: exchange.Slug, IsActive: &active}
+	}
+	return data, len(data), nil
+}
+
+func (s *Server) exchangeInfo(query url.Values) (any, int, *apiError) {
+	exchanges, err := s.lookupExchanges(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data := make(map[string]cmc.ExchangeInfo, len(exchanges))
+	for key, exchange := range exchanges {
+		launched, volume := exchange.DateLaunched, exchange.Volume24h
+		data[key] = cmc.ExchangeInfo{
+			ID:            exchange.ID,
+			Name:          exchange.Name,
+			Slug:          exchange.Slug,
+			DateLaunched:  &launched,
+			SpotVolumeUsd: &volume,
+		}
+	}
+	return data, len(data), nil
+}
+
+func (s *Server) exchangeListings(query url.Values) (any, int, *apiError) {
+	exchanges, err := page(query, s.exchangesByVolume(), 100)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := s.now().UTC()
+	data := make([]cmc.ExchangeListing, len(exchanges))
+	for i, exchange := range exchanges {
+		pairs, volume := exchange.NumMarketPairs, exchange.Volume24h
+		data[i] = cmc.ExchangeListing{
+			ID:             exchange.ID,
+			Name:           exchange.Name,
+			Slug:           exchange.Slug,
+			NumMarketPairs: &pairs,
+			LastUpdated:    now,
+			Quote:          map[string]*cmc.Quote{"USD": {Volume24h: &volume}},
+		}
+	}
+	return data, len(data), nil
+}
+
+func (s *Server) keyInfo(usage *keyUsage) cmc.KeyInfo {
+	var info cmc.KeyInfo
+
+	info.Plan.Name = "cmctest"
+	info.Plan.CreditLimitDaily = s.dailyLimit
+	info.Plan.CreditLimitMonthly = s.monthlyLimit
+	info.Plan.RateLimitMinute = s.rateLimit
+	info.Plan.CreditLimitDailyResetTimestamp = usage.day.AddDate(0, 0, 1)
+	info.Plan.CreditLimitMonthlyResetTimestamp = usage.month.AddDate(0, 1, 0)
+
+	info.Usage.CurrentMinute.RequestsMade = usage.minuteRequests
+	info.Usage.CurrentMinute.RequestsLeft = remaining(s.rateLimit, usage.minuteRequests)
+	info.Usage.CurrentDay.CreditsUsed = usage.dailyCredits
+	info.Usage.CurrentDay.CreditsLeft = remaining(s.dailyLimit, usage.dailyCredits)
+	info.Usage.CurrentMonth.CreditsUsed = usage.monthlyCredits
+	info.Usage.CurrentMonth.CreditsLeft = remaining(s.monthlyLimit, usage.monthlyCredits)
+
+	return info
+}
+
+// cryptocurrencyQuote builds the quote of a coin in every convert target.
+func (s *Server) cryptocurrencyQuote(coin Coin, targets []convertTarget) cmc.CryptocurrencyQuote {
+	now := s.now().UTC()
+	active, rank := 1, coin.Rank
+	circulating, total := coin.CirculatingSupply, coin.TotalSupply
+
+	quote := cmc.CryptocurrencyQuote{
+		ID:                coin.ID,
+		Name:              coin.Name,
+		Symbol:            coin.Symbol,
+		Slug:              coin.Slug,
+		IsActive:          &active,
+		DateAdded:         coin.DateAdded,
+		Tags:              coin.Tags,
+		CirculatingSupply: &circulating,
+		TotalSupply:       &total,
+		Platform:          coin.Platform,
+		CMCRank:           &rank,
+		LastUpdated:       now,
+		Quote:             make(map[string]*cmc.Quote, len(targets)),
+	}
+	if coin.MaxSupply > 0 {
+		maxSupply := coin.MaxSupply
+		quote.MaxSupply = &maxSupply
+	}
+
+	for _, t := range targets {
+		price := coin.Price * t.rate
+		volume := coin.Volume24h * t.rate
+		marketCap := coin.MarketCap() * t.rate
+		change1h, change24h, change7d := coin.PercentChange1h, coin.PercentChange24h, coin.PercentChange7d
+
+		quote.Quote[t.key] = &cmc.Quote{
+			Price:            &price,
+			Volume24h:        &volume,
+			PercentChange1h:  &change1h,
+			PercentChange24h: &change24h,
+			PercentChange7d:  &change7d,
+			MarketCap:        &marketCap,
+			LastUpdated:      &now,
+		}
+	}
+	return quote
+}
+
+// convertTarget is a quote currency and its units per US dollar.
+type convertTarget struct {
+	key  string
+	rate float64
+}
+
+// convertTargets resolves the convert and convert_id parameters, defaulting to USD.
+func (s *Server) convertTargets(query url.Values) ([]convertTarget, *apiError) {
+	symbols, ids := splitList(query.Get("convert")), splitList(query.Get("convert_id"))
+	if len(symbols) == 0 && len(ids) == 0 {
+		symbols = []string{"USD"}
+	}
+
+	var targets []convertTarget
+	for _, symbol := range symbols {
+		rate, ok := s.usdRate(func(id int, sym string) bool { return sym == strings.ToUpper(symbol) })
+		if !ok {
+			return nil, badRequest(`Invalid value for "convert": "%s"`, symbol)
+		}
+		targets = append(targets, convertTarget{strings.ToUpper(symbol), rate})
+	}
+	for _, id := range ids {
+		rate, ok := s.usdRate(func(fid int, _ string) bool { return strconv.Itoa(fid) == id })
+		if !ok {
+			return nil, badRequest(`Invalid value for "convert_id": "%s"`, id)
+		}
+		targets = append(targets, convertTarget{id, rate})
+	}
+	return targets, nil
+}
+
+// usdRate returns the units per US dollar of the first fiat or coin matching match.
+func (s *Server) usdRate(match func(id int, symbol string) bool) (float64, bool) {
+	for _, fiat := range s.fiats {
+		if match(fiat.ID, fiat.Symbol) {
+			return fiat.USDRate, true
+		}
+	}
+	for _, coin := range s.coins {
+		if match(coin.ID, coin.Symbol) && coin.Price > 0 {
+			return 1 / coin.Price, true
+		}
+	}
+	return 0, false
+}
+
+// lookupCoins resolves the id, slug and symbol parameters to coins keyed by the requested value.
+// Unknown values fail unless skip_invalid is set.
+func (s *Server) lookupCoins(query url.Values) (map[string][]Coin, *apiError) {
+	lookups := []struct {
+		param string
+		match func(Coin, string) bool
+	}{
+		{"id", func(c Coin, v string) bool { return strconv.Itoa(c.ID) == v }},
+		{"slug", func(c Coin, v string) bool { return c.Slug == v }},
+		{"symbol", func(c Coin, v string) bool { return strings.EqualFold(c.Symbol, v) }},
+		{"address", func(c Coin, v string) bool { return c.Platform != nil && strings.EqualFold(c.Platform.TokenAddress, v) }},
+	}
+
+	skipInvalid := query.Get("skip_invalid") == "true"
+	matches := make(map[string][]Coin)
+	requested := false
+
+	for _, lookup := range lookups {
+		for _, value := range splitList(query.Get(lookup.param)) {
+			requested = true
+			key := value
+			if lookup.param == "symbol" {
+				key = strings.ToUpper(value)
+			}
+
+			for _, coin := range s.coinsByRank() {
+				if lookup.match(coin, value) {
+					matches[key] = append(matches[key], coin)
+				}
+			}
+			if len(matches[key]) == 0 && !skipInvalid {
+				return nil, badRequest(`Invalid value for "%s": "%s"`, lookup.param, value)
+			}
+		}
+	}
+
+	if !requested {
+		return nil, badRequest(`"value" must contain at least one of [id, symbol, slug, address]`)
+	}
+	return matches, nil
+}
+
+// lookupExchanges resolves the id and slug parameters to exchanges keyed by the requested value.
+func (s *Server) lookupExchanges(query url.Values) (map[string]Exchange, *apiError) {
+	ids, slugs := splitList(query.Get("id")), splitList(query.Get("slug"))
+	if len(ids) == 0 && len(slugs) == 0 {
+		return nil, badRequest(`"value" must contain at least one of [id, slug]`)
+	}
+
+	matches := make(map[string]Exchange)
+	for _, exchange := range s.exchanges {
+		switch {
+		case contains(ids, strconv.Itoa(exchange.ID)):
+			matches[strconv.Itoa(exchange.ID)] = exchange
+		case contains(slugs, exchange.Slug):
+			matches[exchange.Slug] = exchange
+		}
+	}
+	if len(matches) < len(ids)+len(slugs) {
+		return nil, badRequest(`Invalid value for "id" or "slug"`)
+	}
+	return matches, nil
+}
+
+// activeCoins returns the coins that are not inactive, ordered by rank.
+func (s *Server) activeCoins() []Coin {
+	var coins []Coin
+	for _, coin := range s.coinsByRank() {
+		if !coin.Inactive {
+			coins = append(coins, coin)
+		}
+	}
+	return coins
+}
+
+// coinsByRank returns a copy of the seeded coins ordered by rank.
+func (s *Server) coinsByRank() []Coin {
+	coins := append([]Coin(nil), s.coins...)
+	sort.SliceStable(coins, func(i, j int) bool {
+		return coins[i].Rank < coins[j].Rank
+	})
+	return coins
+}
+
+// sortCoins applies the listings sort and sort_dir parameters.
+func sortCoins(coins []Coin, by, dir string) *apiError {
+	keys := map[string]func(Coin) float64{
+		"":                   func(c Coin) float64 { return c.MarketCap() },
+		"market_cap":         func(c Coin) float64 { return c.MarketCap() },
+		"price":              func(c Coin) float64 { return c.Price },
+		"volume_24h":         func(c Coin) float64 { return c.Volume24h },
+		"percent_change_1h":  func(c Coin) float64 { return c.PercentChange1h },
+		"percent_change_24h": func(c Coin) float64 { return c.PercentChange24h },
+		"percent_change_7d":  func(c Coin) float64 { return c.PercentChange7d },
+		"circulating_supply": func(c Coin) float64 { return c.CirculatingSupply },
+	}
+
+	key, ok := keys[by]
+	if !ok {
+		return badRequest(`Invalid value for "sort": "%s"`, by)
+	}
+	if dir != "" && dir != "asc" && dir != "desc" {
+		return badRequest(`Invalid value for "sort_dir": "%s"`, dir)
+	}
+
+	sort.SliceStable(coins, func(i, j int) bool {
+		if dir == "asc" {
+			return key(coins[i]) < key(coins[j])
+		}
+		return key(coins[i]) > key(coins[j])
+	})
+	return nil
+}
+
+// page applies the 1-based start and limit parameters.
+func page[T any](query url.Values, items []T, defaultLimit int) ([]T, *apiError) {
+	start, limit := 1, defaultLimit
+
+	if value := query.Get("start"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return nil, badRequest(`"start" must be larger than or equal to 1`)
+		}
+		start = n
+	}
+	if value := query.Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 5000 {
+			return nil, badRequest(`"limit" must be between 1 and 5000`)
+		}
+		limit = n
+	}
+
+	if start > len(items) {
+		return nil, nil
+	}
+	end := start - 1 + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start-1 : end], nil
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func contains(items []string, value string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func percentOf(part, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return part / total * 100
+}
+
+// remaining returns the units left under a limit, or zero when the limit is unset.
+func remaining(limit, used int) int {
+	if limit <= 0 || used >= limit {
+		return 0
+	}
+	return limit - used
+}
+
+// postmanRequests are the endpoints of the Postman collection: those that need no
+// parameters.
+var postmanRequests = []string{
+	"/v1/cryptocurrency/map",
+	"/v1/cryptocurrency/listings/latest",
+	"/v1/cryptocurrency/categories",
+	"/v1/cryptocurrency/trending/latest",
+	"/v1/exchange/map",
+	"/v1/fiat/map",
+	"/v1/global-metrics/quotes/latest",
+	"/v1/content/latest",
+	"/v3/fear-and-greed/latest",
+	"/v3/index/cmc100-latest",
+	"/v1/key/info",
+}
+
+// postmanCollection returns a Postman collection of postmanRequests against the server.
+func (s *Server) postmanCollection() map[string]any {
+	items := make([]map[string]any, len(postmanRequests))
+	for i, endpoint := range postmanRequests {
+		items[i] = map[string]any{
+			"name": endpoint,
+			"request": map[string]any{
+				"method": http.MethodGet,
+				"header": []map[string]string{{"key": "X-CMC_PRO_API_KEY", "value": "{{api_key}}"}},
+				"url":    s.URL + endpoint,
+			},
+		}
+	}
+	return map[string]any{
+		"info": map[string]string{
+			"name":   "CoinMarketCap API (cmctest)",
+			"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
+		},
+		"item":     items,
+		"variable": []map[string]string{{"key": "api_key", "value": APIKey}},
+	}
+}
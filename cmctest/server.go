@@ -0,0 +1,360 @@
+// Package cmctest provides an in-process fake CoinMarketCap API for tests.
+//
+// The fake serves every endpoint the coinmarketcap.Client calls, wraps responses in
+// the usual status envelope with credit counts, and enforces API keys, per-minute
+// rate limits and daily and monthly credit limits with the real error codes:
+//
+//	fake := cmctest.NewServer(cmctest.WithRateLimit(30))
+//	defer fake.Close()
+//
+//	client := coinmarketcap.NewClient(
+//		coinmarketcap.WithAPIKey(cmctest.APIKey),
+//		coinmarketcap.WithBaseURL(fake.URL),
+//	)
+//
+// Server.Client returns such a client with rate limiting and retries turned off.
+package cmctest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+	"golang.org/x/time/rate"
+)
+
+// APIKey is accepted by a Server created without WithAPIKeys.
+const APIKey = "cmctest-api-key"
+
+// Request is a request received by the fake server.
+type Request struct {
+	Endpoint string
+	Query    url.Values
+	APIKey   string
+	Time     time.Time
+}
+
+// Usage is the request and credit usage of one API key.
+type Usage struct {
+	MinuteRequests int
+	DailyCredits   int
+	MonthlyCredits int
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithAPIKeys sets the API keys the server accepts. Other keys fail with error code 1001.
+func WithAPIKeys(keys ...string) Option {
+	return func(s *Server) {
+		s.keys = make(map[string]bool, len(keys))
+		for _, key := range keys {
+			s.keys[key] = true
+		}
+	}
+}
+
+// WithRateLimit limits each API key to n requests per minute. Zero means unlimited.
+func WithRateLimit(n int) Option {
+	return func(s *Server) {
+		s.rateLimit = n
+	}
+}
+
+// WithCreditLimits sets the daily and monthly credit limits per API key. Zero means unlimited.
+func WithCreditLimits(daily, monthly int) Option {
+	return func(s *Server) {
+		s.dailyLimit = daily
+		s.monthlyLimit = monthly
+	}
+}
+
+// WithCreditCost overrides the credits charged for an endpoint.
+func WithCreditCost(endpoint string, credits int) Option {
+	return func(s *Server) {
+		s.costs[endpoint] = credits
+	}
+}
+
+// WithRestrictedEndpoints makes endpoints fail with error code 1006, as they do
+// when the key's plan does not include them.
+func WithRestrictedEndpoints(endpoints ...string) Option {
+	return func(s *Server) {
+		for _, endpoint := range endpoints {
+			s.restricted[endpoint] = true
+		}
+	}
+}
+
+// WithCoins replaces the default cryptocurrencies.
+func WithCoins(coins ...Coin) Option {
+	return func(s *Server) {
+		s.coins = append([]Coin(nil), coins...)
+	}
+}
+
+// WithExchanges replaces the default exchanges.
+func WithExchanges(exchanges ...Exchange) Option {
+	return func(s *Server) {
+		s.exchanges = append([]Exchange(nil), exchanges...)
+	}
+}
+
+// WithClock sets the clock used for timestamps and limit windows.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// Server is a stateful fake CoinMarketCap API.
+type Server struct {
+	// URL is the base URL of the server, suitable for coinmarketcap.WithBaseURL.
+	URL string
+
+	server *httptest.Server
+
+	mu           sync.Mutex
+	coins        []Coin
+	exchanges    []Exchange
+	fiats        []Fiat
+	fixtures     map[string]any
+	failures     map[string][]failure
+	keys         map[string]bool
+	restricted   map[string]bool
+	costs        map[string]int
+	rateLimit    int
+	dailyLimit   int
+	monthlyLimit int
+	usage        map[string]*keyUsage
+	requests     []Request
+	now          func() time.Time
+}
+
+type keyUsage struct {
+	minute         time.Time
+	minuteRequests int
+	day            time.Time
+	dailyCredits   int
+	month          time.Time
+	monthlyCredits int
+}
+
+type failure struct {
+	statusCode int
+	errorCode  cmc.ErrorCode
+	message    string
+}
+
+// NewServer starts a fake server seeded with DefaultCoins, DefaultExchanges and DefaultFiats.
+// Call Close when done.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		coins:      DefaultCoins(),
+		exchanges:  DefaultExchanges(),
+		fiats:      DefaultFiats(),
+		fixtures:   make(map[string]any),
+		failures:   make(map[string][]failure),
+		keys:       map[string]bool{APIKey: true},
+		restricted: make(map[string]bool),
+		costs:      make(map[string]int),
+		usage:      make(map[string]*keyUsage),
+		now:        time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.URL = s.server.URL
+
+	return s
+}
+
+// Client returns a client for the server that authenticates with APIKey and does not
+// throttle or retry, so tests run fast and see every error. opts are applied last.
+func (s *Server) Client(opts ...cmc.Option) *cmc.Client {
+	return cmc.NewClient(append([]cmc.Option{
+		cmc.WithAPIKey(APIKey),
+		cmc.WithBaseURL(s.URL),
+		cmc.WithRateLimit(rate.Limit(1000)),
+		cmc.WithRetryPolicy(cmc.RetryPolicy{}),
+	}, opts...)...)
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// SetFixture serves data for endpoint instead of the built-in response.
+// data is encoded as JSON inside the status envelope.
+func (s *Server) SetFixture(endpoint string, data any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fixtures[endpoint] = data
+}
+
+// FailNext makes the next request to endpoint fail with the given HTTP status and error code.
+// Calls queue up, so FailNext twice fails the next two requests.
+func (s *Server) FailNext(endpoint string, statusCode int, errorCode cmc.ErrorCode, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[endpoint] = append(s.failures[endpoint], failure{statusCode, errorCode, message})
+}
+
+// UpdateCoin applies update to the coin with the given symbol and reports whether it exists.
+func (s *Server) UpdateCoin(symbol string, update func(*Coin)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.coins {
+		if s.coins[i].Symbol == symbol {
+			update(&s.coins[i])
+			return true
+		}
+	}
+	return false
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Usage returns the current usage of an API key.
+func (s *Server) Usage(apiKey string) Usage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.keyUsage(apiKey)
+	return Usage{
+		MinuteRequests: u.minuteRequests,
+		DailyCredits:   u.dailyCredits,
+		MonthlyCredits: u.monthlyCredits,
+	}
+}
+
+// keyUsage returns the usage of a key with expired windows reset. Callers must hold s.mu.
+func (s *Server) keyUsage(apiKey string) *keyUsage {
+	now := s.now().UTC()
+	minute := now.Truncate(time.Minute)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	u, ok := s.usage[apiKey]
+	if !ok {
+		u = &keyUsage{}
+		s.usage[apiKey] = u
+	}
+	if !u.minute.Equal(minute) {
+		u.minute, u.minuteRequests = minute, 0
+	}
+	if !u.day.Equal(day) {
+		u.day, u.dailyCredits = day, 0
+	}
+	if !u.month.Equal(month) {
+		u.month, u.monthlyCredits = month, 0
+	}
+	return u
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	endpoint := r.URL.Path
+	query := r.URL.Query()
+	apiKey := r.Header.Get("X-CMC_PRO_API_KEY")
+	if apiKey == "" {
+		apiKey = query.Get("CMC_PRO_API_KEY")
+	}
+	s.requests = append(s.requests, Request{Endpoint: endpoint, Query: query, APIKey: apiKey, Time: s.now()})
+
+	switch {
+	case apiKey == "":
+		s.writeError(w, http.StatusUnauthorized, cmc.ErrorCodeAPIKeyMissing, "API key missing.")
+		return
+	case !s.keys[apiKey]:
+		s.writeError(w, http.StatusUnauthorized, cmc.ErrorCodeAPIKeyInvalid, "This API Key is invalid.")
+		return
+	}
+
+	usage := s.keyUsage(apiKey)
+	usage.minuteRequests++
+
+	if queued := s.failures[endpoint]; len(queued) > 0 {
+		s.failures[endpoint] = queued[1:]
+		s.writeError(w, queued[0].statusCode, queued[0].errorCode, queued[0].message)
+		return
+	}
+
+	switch {
+	case s.rateLimit > 0 && usage.minuteRequests > s.rateLimit:
+		s.writeError(w, http.StatusTooManyRequests, cmc.ErrorCodeMinuteRateLimit,
+			"You've exceeded your API Key's HTTP request rate limit. Rate limits reset every minute.")
+		return
+	case s.restricted[endpoint]:
+		s.writeError(w, http.StatusForbidden, cmc.ErrorCodePlanUnauthorized,
+			"Your API Key subscription plan doesn't support this endpoint.")
+		return
+	}
+
+	free := endpoint == "/v1/key/info"
+	if !free {
+		switch {
+		case s.dailyLimit > 0 && usage.dailyCredits >= s.dailyLimit:
+			s.writeError(w, http.StatusTooManyRequests, cmc.ErrorCodeDailyRateLimit, "You've exceeded your API Key's daily rate limit.")
+			return
+		case s.monthlyLimit > 0 && usage.monthlyCredits >= s.monthlyLimit:
+			s.writeError(w, http.StatusTooManyRequests, cmc.ErrorCodeMonthlyRateLimit, "You've exceeded your API Key's monthly rate limit.")
+			return
+		}
+	}
+
+	data, count, apiErr := s.route(endpoint, query, usage)
+	if apiErr != nil {
+		s.writeError(w, apiErr.statusCode, apiErr.errorCode, apiErr.message)
+		return
+	}
+
+	credits := 0
+	if !free {
+		credits = creditCost(count)
+		if cost, ok := s.costs[endpoint]; ok {
+			credits = cost
+		}
+	}
+	usage.dailyCredits += credits
+	usage.monthlyCredits += credits
+
+	s.writeJSON(w, http.StatusOK, data, cmc.Status{CreditCount: credits})
+}
+
+// creditCost charges one credit per 100 returned items, with a minimum of one.
+func creditCost(items int) int {
+	if items <= 100 {
+		return 1
+	}
+	return (items + 99) / 100
+}
+
+func (s *Server) writeError(w http.ResponseWriter, statusCode int, errorCode cmc.ErrorCode, message string) {
+	s.writeJSON(w, statusCode, nil, cmc.Status{ErrorCode: errorCode, ErrorMessage: &message})
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, statusCode int, data any, status cmc.Status) {
+	status.Timestamp = s.now().UTC()
+	status.Elapsed = 1
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(cmc.APIResponse[any]{Data: data, Status: status})
+}
@@ -0,0 +1,736 @@
+package cmctest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+func expectAPIError(t *testing.T, err error, statusCode int, errorCode cmc.ErrorCode) {
+	t.Helper()
+
+	var apiErr *cmc.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.StatusCode != statusCode || apiErr.ErrorCode != errorCode {
+		t.Errorf("expected status %d and error code %d, got %d and %d", statusCode, errorCode, apiErr.StatusCode, apiErr.ErrorCode)
+	}
+}
+
+func TestServerQuotes(t *testing.T) {
+	fake := NewServer()
+	defer fake.Close()
+
+	client := fake.Client()
+	ctx := context.Background()
+
+	bySymbol, err := client.GetCryptocurrencyQuotesLatest(ctx, &cmc.CryptocurrencyQuotesOptions{
+		Symbol:  []string{"BTC", "ETH"},
+		Convert: []string{"USD", "EUR"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	btc := cmc.GetPrimaryQuote(bySymbol.Data["BTC"])
+	if btc == nil || btc.ID != 1 || *btc.Quote["USD"].Price != 65000 {
+		t.Fatalf("unexpected BTC quote: %+v", btc)
+	}
+	if eur := *btc.Quote["EUR"].Price; eur != 65000*0.92 {
+		t.Errorf("expected EUR price %v, got %v", 65000*0.92, eur)
+	}
+	if bySymbol.Status.CreditCount != 1 {
+		t.Errorf("expected 1 credit, got %d", bySymbol.Status.CreditCount)
+	}
+
+	byID, err := client.GetCryptocurrencyQuotesLatest(ctx, &cmc.CryptocurrencyQuotesOptions{ID: []int{1027}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if eth := cmc.GetPrimaryQuote(byID.Data["1027"]); eth == nil || eth.Symbol != "ETH" {
+		t.Errorf("unexpected quote by ID: %+v", byID.Data)
+	}
+
+	_, err = client.GetCryptocurrencyQuotesLatest(ctx, &cmc.CryptocurrencyQuotesOptions{Symbol: []string{"NOPE"}})
+	expectAPIError(t, err, http.StatusBadRequest, cmc.ErrorCodeBadRequest)
+
+	fake.UpdateCoin("BTC", func(c *Coin) { c.Price = 70000 })
+	updated, err := client.GetCryptocurrencyQuotesLatest(ctx, &cmc.CryptocurrencyQuotesOptions{Symbol: []string{"BTC"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if price := *cmc.GetPrimaryQuote(updated.Data["BTC"]).Quote["USD"].Price; price != 70000 {
+		t.Errorf("expected updated price 70000, got %v", price)
+	}
+}
+
+func TestServerHistory(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 34, 0, 0, time.UTC)
+	fake := NewServer(WithClock(func() time.Time { return now }))
+	defer fake.Close()
+
+	client := fake.Client()
+	ctx := context.Background()
+
+	interval := cmc.Interval1h
+	quotes, err := client.GetCryptocurrencyQuotesHistorical(ctx, &cmc.CryptocurrencyQuotesHistoricalOptions{
+		ID:       []int{1},
+		Count:    cmc.Int(3),
+		Interval: &interval,
+		Convert:  []string{"EUR"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := quotes.Data["1"]; len(got) != 1 || got[0].ID != 1 || got[0].Symbol != "BTC" {
+		t.Fatalf("expected the history of bitcoin, got %+v", got)
+	}
+	points := quotes.Data["1"][0].Quotes
+	if len(points) != 3 || !points[2].Timestamp.Equal(now.Truncate(time.Hour)) || !points[0].Timestamp.Equal(now.Add(-2*time.Hour).Truncate(time.Hour)) {
+		t.Fatalf("expected 3 hourly points up to now, got %+v", points)
+	}
+	if price := *points[2].Quote["EUR"].Price; price < 65000*0.92*0.98 || price > 65000*0.92*1.02 {
+		t.Errorf("expected an EUR price near the current price, got %v", price)
+	}
+
+	again, err := client.GetCryptocurrencyQuotesHistoricalV3(ctx, &cmc.CryptocurrencyQuotesHistoricalOptions{
+		Symbol:    []string{"btc"},
+		TimeStart: cmc.String("2024-06-01T10:00:00Z"),
+		Interval:  &interval,
+		Convert:   []string{"EUR"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := again.Data["BTC"]; len(got) != 1 || len(got[0].Quotes) != 3 || *got[0].Quotes[0].Quote["EUR"].Price != *points[0].Quote["EUR"].Price {
+		t.Errorf("expected the same deterministic points by symbol from time_start, got %+v", got)
+	}
+
+	ohlcv, err := client.GetCryptocurrencyOHLCVHistorical(ctx, &cmc.CryptocurrencyOHLCVHistoricalOptions{Slug: []string{"ethereum"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	candles := ohlcv.Data["1027"]
+	if len(candles) != 10 || !candles[9].TimeOpen.Equal(now.Truncate(24*time.Hour)) || *candles[9].Close != 3500 {
+		t.Fatalf("expected 10 daily candles ending at the current price, got %d", len(candles))
+	}
+	for i, c := range candles {
+		if *c.High < max(*c.Open, *c.Close) || *c.Low > min(*c.Open, *c.Close) || i > 0 && *c.Open != *candles[i-1].Close {
+			t.Errorf("inconsistent candle %d: %+v", i, c)
+		}
+	}
+	if ohlcv.Status.CreditCount != 1 {
+		t.Errorf("expected 1 credit for 10 points, got %d", ohlcv.Status.CreditCount)
+	}
+}
+
+func TestServerMarkets(t *testing.T) {
+	fake := NewServer()
+	defer fake.Close()
+
+	client := fake.Client()
+	ctx := context.Background()
+
+	pairs, err := client.GetCryptocurrencyMarketPairsLatest(ctx, &cmc.CryptocurrencyMarketPairsOptions{Symbol: cmc.String("BTC")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	btc := pairs.Data["BTC"]
+	if len(btc) != 3 || btc[0].ExchangeSlug != "binance" || btc[0].MarketPair != "BTC/USDT" {
+		t.Fatalf("expected BTC/USDT on every exchange, largest first, got %+v", btc)
+	}
+	var volume float64
+	for _, pair := range btc {
+		volume += *pair.Quote["USD"].Volume24h
+	}
+	if volume < 30e9*0.999 || volume > 30e9*1.001 {
+		t.Errorf("expected the pairs to add up to the coin's volume, got %v", volume)
+	}
+
+	exchangePairs, err := client.GetExchangeMarketPairsLatest(ctx, &cmc.ExchangeMarketPairsOptions{Slug: cmc.String("kraken"), MatchedSymbol: []string{"USD"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(exchangePairs.Data) != 1 || exchangePairs.Data[0].MarketPair != "USDT/USD" {
+		t.Errorf("expected USDT/USD on Kraken, got %+v", exchangePairs.Data)
+	}
+}
+
+func TestServerDex(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 34, 0, 0, time.UTC)
+	fake := NewServer(WithClock(func() time.Time { return now }))
+	defer fake.Close()
+
+	client := fake.Client()
+	ctx := context.Background()
+
+	networks, err := client.GetDexNetworks(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(networks.Data) != 1 || networks.Data[0].NetworkSlug != "ethereum" {
+		t.Fatalf("expected the network of the seeded token, got %+v", networks.Data)
+	}
+
+	spot, err := client.GetDexSpotPairsLatest(ctx, &cmc.DexSpotPairsOptions{NetworkSlug: cmc.String("ethereum"), BaseAssetSymbol: cmc.String("USDT")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(spot.Data) != 1 || spot.Data[0].Name != "USDT/ETH" || spot.Data[0].BaseToken().ContractAddress != "0xdac17f958d2ee523a2206206994597c13d831ec7" {
+		t.Fatalf("expected the USDT/ETH pool, got %+v", spot.Data)
+	}
+	address := spot.Data[0].ContractAddress
+
+	quotes, err := client.GetDexPairsQuotesLatest(ctx, &cmc.DexPairOptions{ContractAddress: []string{address}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if quote := quotes.Data[0].Quote[0]; *quote.Price != 1 || *quote.PriceByQuoteAsset != 1.0/3500 {
+		t.Errorf("unexpected pool quote %+v", quote)
+	}
+
+	history, err := client.GetDexPairsOHLCVHistorical(ctx, &cmc.DexPairOHLCVHistoricalOptions{ContractAddress: []string{address}, Count: cmc.Int(2)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if periods := history.Data[0].Quotes; len(periods) != 2 || *periods[1].Quote[0].Close != 1 {
+		t.Errorf("expected 2 candles ending at the current price, got %+v", periods)
+	}
+
+	trades, err := client.GetDexPairsTradeLatest(ctx, &cmc.DexPairOptions{ContractAddress: []string{address}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := trades.Data[0].Trades; len(got) != 10 || got[0].Type != "buy" || got[1].Type != "sell" || !got[0].Date.After(*got[1].Date) {
+		t.Errorf("expected 10 trades, newest first, got %+v", got)
+	}
+
+	_, err = client.GetDexPairsOHLCVLatest(ctx, &cmc.DexPairOptions{ContractAddress: []string{"0x0"}})
+	expectAPIError(t, err, http.StatusBadRequest, cmc.ErrorCodeBadRequest)
+}
+
+func TestServerCategories(t *testing.T) {
+	fake := NewServer()
+	defer fake.Close()
+
+	client := fake.Client()
+	ctx := context.Background()
+
+	categories, err := client.GetCryptocurrencyCategories(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(categories.Data) != 6 || categories.Data[0].Name != "Mineable" || categories.Data[0].NumTokens != 1 {
+		t.Fatalf("expected a category per tag, largest first, got %+v", categories.Data)
+	}
+
+	bySymbol, err := client.GetCryptocurrencyCategories(ctx, &cmc.CryptocurrencyCategoriesOptions{Symbol: []string{"SOL"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bySymbol.Data) != 2 || bySymbol.Data[1].Name != "Smart Contracts" || bySymbol.Data[1].NumTokens != 2 {
+		t.Fatalf("expected the categories of solana, got %+v", bySymbol.Data)
+	}
+	if marketCap := *bySymbol.Data[1].MarketCap; marketCap != 3500*120e6+150*460e6 {
+		t.Errorf("expected the market cap of ethereum and solana, got %v", marketCap)
+	}
+
+	detail, err := client.GetCryptocurrencyCategory(ctx, &cmc.CryptocurrencyCategoryOptions{ID: bySymbol.Data[1].ID, Convert: []string{"EUR"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if coins := detail.Data.Coins; len(coins) != 2 || coins[0].Symbol != "ETH" || *coins[0].Quote["EUR"].Price != 3500*0.92 {
+		t.Errorf("expected the listings of ethereum and solana in EUR, got %+v", coins)
+	}
+
+	_, err = client.GetCryptocurrencyCategory(ctx, &cmc.CryptocurrencyCategoryOptions{ID: "unknown"})
+	expectAPIError(t, err, http.StatusBadRequest, cmc.ErrorCodeBadRequest)
+
+	airdrops, err := client.GetCryptocurrencyAirdrops(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(airdrops.Data) != 2 || airdrops.Data[0].Symbol != "BTC" || airdrops.Data[1].Symbol != "BNB" {
+		t.Fatalf("expected the ongoing airdrops, got %+v", airdrops.Data)
+	}
+
+	upcoming, err := client.GetCryptocurrencyAirdrops(ctx, &cmc.CryptocurrencyAirdropsOptions{Status: cmc.AirdropStatusPtr(cmc.AirdropStatusUpcoming)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(upcoming.Data) != 1 || upcoming.Data[0].Symbol != "USDT" || !upcoming.Data[0].DateStart.After(time.Now()) {
+		t.Fatalf("expected the upcoming tether airdrop, got %+v", upcoming.Data)
+	}
+
+	airdrop, err := client.GetCryptocurrencyAirdrop(ctx, upcoming.Data[0].ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if airdrop.Data.ID != upcoming.Data[0].ID || airdrop.Data.CryptocurrencyID != 825 {
+		t.Errorf("expected the tether airdrop by ID, got %+v", airdrop.Data)
+	}
+}
+
+func TestServerTrending(t *testing.T) {
+	fake := NewServer()
+	defer fake.Close()
+
+	client := fake.Client()
+	ctx := context.Background()
+
+	trending, err := client.GetCryptocurrencyTrendingLatest(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := trending.Data; len(got) != 5 || got[0].Symbol != "SOL" || *got[0].SearchScore != 100 || *got[4].SearchScore >= *got[3].SearchScore {
+		t.Fatalf("expected coins by search score, biggest mover first, got %+v", got)
+	}
+
+	visited, err := client.GetCryptocurrencyTrendingMostVisited(ctx, &cmc.CryptocurrencyTrendingOptions{Limit: cmc.Int(2)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := visited.Data; len(got) != 2 || got[0].Symbol != "BTC" || got[0].SearchScore != nil {
+		t.Errorf("expected the top coins by rank, got %+v", got)
+	}
+
+	tests := []struct {
+		name  string
+		opts  *cmc.CryptocurrencyGainersLosersOptions
+		first string
+		last  string
+	}{
+		{"gainers", nil, "SOL", "BNB"},
+		{"losers", &cmc.CryptocurrencyGainersLosersOptions{SortDir: cmc.SortDirectionPtr(cmc.SortAsc)}, "BNB", "SOL"},
+		{"over 30 days", &cmc.CryptocurrencyGainersLosersOptions{TimePeriod: cmc.TimePeriodPtr(cmc.TimePeriod30d)}, "SOL", "BNB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.GetCryptocurrencyTrendingGainersLosers(ctx, tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := resp.Data; len(got) != 5 || got[0].Symbol != tt.first || got[4].Symbol != tt.last {
+				t.Errorf("expected %s to %s, got %+v", tt.first, tt.last, got)
+			}
+		})
+	}
+
+	_, err = client.GetCryptocurrencyTrendingGainersLosers(ctx, &cmc.CryptocurrencyGainersLosersOptions{TimePeriod: cmc.TimePeriodPtr("2w")})
+	expectAPIError(t, err, http.StatusBadRequest, cmc.ErrorCodeBadRequest)
+
+	topics, err := client.GetCommunityTrendingTopic(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(topics.Data) != 6 || topics.Data[0].Rank != 1 || topics.Data[0].Topic != "#Mineable" {
+		t.Errorf("expected a topic per category, got %+v", topics.Data)
+	}
+
+	tokens, err := client.GetCommunityTrendingToken(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tokens.Data) != 5 || tokens.Data[0].Symbol != "SOL" || tokens.Data[0].Rank != 1 || *tokens.Data[0].Quote["USD"].Price != 150 {
+		t.Errorf("expected tokens by search score, got %+v", tokens.Data)
+	}
+}
+
+func TestServerContent(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 34, 0, 0, time.UTC)
+	fake := NewServer(WithClock(func() time.Time { return now }))
+	defer fake.Close()
+
+	client := fake.Client()
+	ctx := context.Background()
+
+	articles, err := client.GetContentLatest(ctx, &cmc.ContentLatestOptions{CryptocurrencyID: cmc.Int(1027)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(articles.Data) != 1 || articles.Data[0].Assets[0].Symbol != "ETH" || articles.Data[0].Meta.Type != "alexandria" {
+		t.Fatalf("expected the ethereum article, got %+v", articles.Data)
+	}
+
+	posts, err := client.IterateContentPostsTop(ctx, &cmc.ContentPostsOptions{Limit: cmc.Int(3)}).All()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(posts) != 10 || posts[0].Owner.Nickname != "BTC_hodler" {
+		t.Fatalf("expected all 10 posts, most liked first, got %+v", posts)
+	}
+	for i := 1; i < len(posts); i++ {
+		if posts[i].LikeCount > posts[i-1].LikeCount {
+			t.Errorf("post %d has more likes than the one before it", i)
+		}
+	}
+	if requests := len(fake.Requests()); requests != 5 {
+		t.Errorf("expected 4 page requests after the article request, got %d", requests-1)
+	}
+
+	latest, err := client.GetContentPostsLatest(ctx, &cmc.ContentPostsOptions{TimePeriod: cmc.TimePeriodPtr(cmc.TimePeriod1h)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list := latest.Data.List; len(list) != 1 || !list[0].PostTime.Equal(now.Truncate(time.Minute).Add(-time.Hour)) || latest.Data.LastScore != "" {
+		t.Errorf("expected the one post of the last hour, got %+v", latest.Data)
+	}
+
+	comments, err := client.GetContentPostsComments(ctx, &cmc.ContentCommentsOptions{PostID: posts[0].PostID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments.Data) != posts[0].CommentCount || comments.Data[0].TextContent != "Replying to @BTC_hodler" {
+		t.Errorf("expected the comments of the post, got %+v", comments.Data)
+	}
+
+	_, err = client.GetContentPostsComments(ctx, &cmc.ContentCommentsOptions{PostID: "0"})
+	expectAPIError(t, err, http.StatusBadRequest, cmc.ErrorCodeBadRequest)
+}
+
+func TestServerMetrics(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 34, 0, 0, time.UTC)
+	fake := NewServer(WithClock(func() time.Time { return now }))
+	defer fake.Close()
+
+	client := fake.Client()
+	ctx := context.Background()
+
+	fearAndGreed, err := client.GetFearAndGreedLatest(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reading := fearAndGreed.Data; reading.Value < 0 || reading.Value > 100 || reading.ValueClassification == "" || !reading.UpdateTime.Equal(now) {
+		t.Errorf("unexpected latest reading %+v", reading)
+	}
+
+	readings, err := client.GetFearAndGreedRange(ctx, now.AddDate(0, 0, -10), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(readings) != 10 || !readings[9].Timestamp.Equal(now.Truncate(24*time.Hour)) {
+		t.Errorf("expected 10 daily readings up to today, got %+v", readings)
+	}
+
+	cmc100, err := client.GetIndexCMC100Latest(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var weights float64
+	for _, constituent := range cmc100.Data.Constituents {
+		weights += constituent.Weight
+	}
+	if len(cmc100.Data.Constituents) != 4 || cmc100.Data.Constituents[0].Symbol != "BTC" || weights < 99.99 || weights > 100.01 {
+		t.Errorf("expected the coins other than tether, weighted by market cap, got %+v", cmc100.Data.Constituents)
+	}
+	if cmc100.Data.Value24hPercentageChange == nil || !cmc100.Data.Time().Equal(now) {
+		t.Errorf("expected a current reading with its daily change, got %+v", cmc100.Data)
+	}
+
+	history, err := client.GetAltcoinSeasonIndexHistorical(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history.Data) != 10 || !history.Data[9].Time().Equal(now.Truncate(24*time.Hour)) || history.Data[9].Value > 100 {
+		t.Errorf("expected 10 daily readings up to today, got %+v", history.Data)
+	}
+
+	_, err = client.GetIndexLatest(ctx, "cmc5")
+	expectAPIError(t, err, http.StatusNotFound, cmc.ErrorCodeNotFound)
+
+	global, err := client.GetGlobalMetricsHistorical(ctx, &cmc.GlobalMetricsHistoricalOptions{Count: cmc.Int(3)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(global.Data) != 3 || !global.Data[2].LastUpdated.Equal(now.Truncate(24*time.Hour)) || *global.Data[2].Quote["USD"].MarketCap <= 0 {
+		t.Errorf("expected 3 daily readings up to today, got %+v", global.Data)
+	}
+
+	stats, err := client.GetBlockchainStatsLatest(ctx, &cmc.BlockchainStatsOptions{Symbol: []string{"BTC"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if btc := stats.Data["BTC"]; *btc.MeanBlockTime != 600 || *btc.HashAlgorithm != "SHA-256" || *btc.High24h < *btc.Low24h || btc.LastBlockTimestamp.After(now) {
+		t.Errorf("unexpected bitcoin statistics %+v", btc)
+	}
+
+	_, err = client.GetBlockchainStatsLatest(ctx, &cmc.BlockchainStatsOptions{Symbol: []string{"USDT"}})
+	expectAPIError(t, err, http.StatusBadRequest, cmc.ErrorCodeBadRequest)
+
+	performance, err := client.GetCryptocurrencyPricePerformanceStats(ctx, &cmc.CryptocurrencyPricePerformanceStatsOptions{ID: []int{1027}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	allTime := performance.Data["1027"].ROI[string(cmc.TimePeriodAllTime)]
+	if allTime == nil || *allTime.ClosePrice != 3500 || !allTime.OpenTime.Equal(DefaultCoins()[1].DateAdded) || *allTime.HighPrice < *allTime.ClosePrice {
+		t.Errorf("expected the all-time performance of ethereum, got %+v", allTime)
+	}
+}
+
+func TestServerExchangeQuotes(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 34, 0, 0, time.UTC)
+	fake := NewServer(WithClock(func() time.Time { return now }))
+	defer fake.Close()
+
+	client := fake.Client()
+	ctx := context.Background()
+
+	quotes, err := client.GetExchangeQuotesLatest(ctx, &cmc.ExchangeQuotesOptions{Slug: []string{"binance"}, Convert: []string{"EUR"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if binance := quotes.Data["binance"]; *binance.Volume24hReported != 15e9 || *binance.Quote["EUR"].Volume24h != 15e9*0.92 || *binance.Volume7dReported <= *binance.Volume24hReported {
+		t.Errorf("unexpected binance quote %+v", binance)
+	}
+
+	tests := []struct {
+		name string
+		ids  []int
+	}{
+		{"one exchange", []int{270}},
+		{"several exchanges", []int{270, 89}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.GetExchangeQuotesHistorical(ctx, &cmc.ExchangeQuotesHistoricalOptions{ID: tt.ids, Count: cmc.Int(3)})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(resp.Data) != len(tt.ids) {
+				t.Fatalf("expected %d exchanges, got %+v", len(tt.ids), resp.Data)
+			}
+			quotes := resp.Data["270"].Quotes
+			if len(quotes) != 3 || !quotes[2].Timestamp.Equal(now.Truncate(5*time.Minute)) || *quotes[2].Quote["USD"].Volume24h <= 0 {
+				t.Errorf("expected 3 points up to now, got %+v", quotes)
+			}
+		})
+	}
+
+	assets, err := client.GetExchangeAssets(ctx, 89)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(assets.Data) != 5 || assets.Data[2].Currency.Symbol != "USDT" || assets.Data[2].Platform.Symbol != "ETH" || assets.Data[0].WalletAddress == assets.Data[1].WalletAddress {
+		t.Errorf("expected a wallet per coin, got %+v", assets.Data)
+	}
+
+	_, err = client.GetExchangeAssets(ctx, 1)
+	expectAPIError(t, err, http.StatusBadRequest, cmc.ErrorCodeBadRequest)
+}
+
+func TestServerPostman(t *testing.T) {
+	fake := NewServer()
+	defer fake.Close()
+
+	resp, err := fake.Client().GetPostmanCollection(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	collection, _ := resp.Data.(map[string]any)
+	items, _ := collection["item"].([]any)
+	if len(items) != len(postmanRequests) {
+		t.Fatalf("expected %d requests, got %+v", len(postmanRequests), resp.Data)
+	}
+
+	for _, item := range items {
+		request := item.(map[string]any)["request"].(map[string]any)
+		req, err := http.NewRequest(request["method"].(string), request["url"].(string), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		req.Header.Set("X-CMC_PRO_API_KEY", APIKey)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			t.Errorf("%s: expected status 200, got %d", req.URL.Path, res.StatusCode)
+		}
+	}
+}
+
+func TestServerPaging(t *testing.T) {
+	fake := NewServer()
+	defer fake.Close()
+
+	client := fake.Client()
+
+	coins, err := client.IterateCryptocurrencyMap(context.Background(), &cmc.CryptocurrencyMapOptions{Limit: cmc.Int(2)}).All()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(coins) != len(DefaultCoins()) || coins[0].Symbol != "BTC" {
+		t.Errorf("expected all %d coins starting with BTC, got %+v", len(DefaultCoins()), coins)
+	}
+
+	if requests := len(fake.Requests()); requests != 3 {
+		t.Errorf("expected 3 page requests, got %d", requests)
+	}
+}
+
+func TestServerAPIKeys(t *testing.T) {
+	fake := NewServer(WithAPIKeys("good"))
+	defer fake.Close()
+
+	_, err := fake.Client(cmc.WithAPIKey("bad")).GetFiatMap(context.Background(), nil)
+	expectAPIError(t, err, http.StatusUnauthorized, cmc.ErrorCodeAPIKeyInvalid)
+
+	_, err = fake.Client(cmc.WithAPIKey("")).GetFiatMap(context.Background(), nil)
+	expectAPIError(t, err, http.StatusUnauthorized, cmc.ErrorCodeAPIKeyMissing)
+
+	if _, err := fake.Client(cmc.WithAPIKey("good")).GetFiatMap(context.Background(), nil); err != nil {
+		t.Errorf("unexpected error with valid key: %v", err)
+	}
+}
+
+func TestServerLimits(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	fake := NewServer(
+		WithRateLimit(2),
+		WithCreditLimits(3, 0),
+		WithClock(func() time.Time { return now }),
+	)
+	defer fake.Close()
+
+	client := fake.Client()
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetFiatMap(ctx, nil); err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i+1, err)
+		}
+	}
+	_, err := client.GetFiatMap(ctx, nil)
+	expectAPIError(t, err, http.StatusTooManyRequests, cmc.ErrorCodeMinuteRateLimit)
+
+	now = now.Add(time.Minute)
+	if _, err := client.GetFiatMap(ctx, nil); err != nil {
+		t.Fatalf("expected rate limit to reset, got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	_, err = client.GetFiatMap(ctx, nil)
+	expectAPIError(t, err, http.StatusTooManyRequests, cmc.ErrorCodeDailyRateLimit)
+
+	info, err := client.GetKeyInfo(ctx)
+	if err != nil {
+		t.Fatalf("key info should bypass the credit limit: %v", err)
+	}
+	if info.Data.Usage.CurrentDay.CreditsUsed != 3 || info.Data.Plan.CreditLimitDaily != 3 {
+		t.Errorf("unexpected key usage: %+v", info.Data.Usage.CurrentDay)
+	}
+
+	if usage := fake.Usage(APIKey); usage.DailyCredits != 3 || usage.MonthlyCredits != 3 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+}
+
+func TestServerFixturesAndFailures(t *testing.T) {
+	fake := NewServer(WithRestrictedEndpoints("/v1/exchange/assets"))
+	defer fake.Close()
+
+	fake.SetFixture("/v3/fear-and-greed/latest", map[string]any{
+		"value":                71,
+		"value_classification": "Greed",
+		"update_time":          "2024-06-01T12:00:00Z",
+	})
+	fake.FailNext("/v1/global-metrics/quotes/latest", http.StatusInternalServerError, cmc.ErrorCodeInternalServerError, "boom")
+
+	client := fake.Client()
+	ctx := context.Background()
+
+	fng, err := client.GetFearAndGreedLatest(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fng.Data.Value != 71 || fng.Data.ValueClassification != "Greed" {
+		t.Errorf("unexpected fixture data: %+v", fng.Data)
+	}
+
+	_, err = client.GetGlobalMetricsLatest(ctx, nil)
+	expectAPIError(t, err, http.StatusInternalServerError, cmc.ErrorCodeInternalServerError)
+
+	metrics, err := client.GetGlobalMetricsLatest(ctx, nil)
+	if err != nil {
+		t.Fatalf("expected queued failure to be consumed, got %v", err)
+	}
+	if metrics.Data.BtcDominance == nil || *metrics.Data.BtcDominance <= 0 {
+		t.Errorf("expected BTC dominance, got %+v", metrics.Data)
+	}
+
+	_, err = client.GetExchangeAssets(ctx, 270)
+	expectAPIError(t, err, http.StatusForbidden, cmc.ErrorCodePlanUnauthorized)
+}
+
+func TestServerServesEveryEndpoint(t *testing.T) {
+	fake := NewServer()
+	defer fake.Close()
+
+	client := fake.Client()
+	ctx := context.Background()
+
+	calls := []struct {
+		name string
+		call func() error
+	}{
+		{"categories", func() error {
+			_, err := client.GetCryptocurrencyCategories(ctx, nil)
+			return err
+		}},
+		{"listings", func() error {
+			_, err := client.GetCryptocurrencyListingsLatest(ctx, nil)
+			return err
+		}},
+		{"info", func() error {
+			_, err := client.GetCryptocurrencyInfo(ctx, &cmc.CryptocurrencyInfoOptions{Slug: []string{"bitcoin"}})
+			return err
+		}},
+		{"ohlcv", func() error {
+			_, err := client.GetCryptocurrencyOHLCVLatest(ctx, &cmc.CryptocurrencyOHLCVOptions{ID: []int{1}})
+			return err
+		}},
+		{"exchange info", func() error {
+			_, err := client.GetExchangeInfo(ctx, &cmc.ExchangeInfoOptions{ID: []int{270}})
+			return err
+		}},
+		{"exchange listings", func() error {
+			_, err := client.GetExchangeListingsLatest(ctx, nil)
+			return err
+		}},
+		{"price conversion", func() error {
+			_, err := client.GetPriceConversion(ctx, &cmc.PriceConversionOptions{Amount: 2, Symbol: cmc.String("ETH")})
+			return err
+		}},
+		{"posts", func() error {
+			_, err := client.GetContentPostsTop(ctx, nil)
+			return err
+		}},
+		{"index", func() error {
+			_, err := client.GetIndexCMC100Latest(ctx)
+			return err
+		}},
+		{"index history", func() error {
+			_, err := client.GetIndexCMC20Historical(ctx, nil)
+			return err
+		}},
+		{"dex spot pairs", func() error {
+			_, err := client.GetDexSpotPairsLatest(ctx, nil)
+			return err
+		}},
+		{"trending tokens", func() error {
+			_, err := client.GetCommunityTrendingToken(ctx, nil)
+			return err
+		}},
+	}
+
+	for _, tt := range calls {
+		if err := tt.call(); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package cmctest
+
+import (
+	"math"
+	"net/url"
+	"sort"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// trendingList selects the ordering of a trending endpoint.
+type trendingList int
+
+const (
+	trendingSearches trendingList = iota // by search score
+	trendingVisits                       // by rank
+)
+
+// bySearchScore returns the active coins ordered by search score, with their scores.
+// A coin's score is its 24h move relative to the largest move, so the coins that move
+// most are searched most.
+func (s *Server) bySearchScore() ([]Coin, []float64) {
+	coins := s.activeCoins()
+	sort.SliceStable(coins, func(i, j int) bool {
+		return math.Abs(coins[i].PercentChange24h) > math.Abs(coins[j].PercentChange24h)
+	})
+
+	scores := make([]float64, len(coins))
+	for i, coin := range coins {
+		if top := math.Abs(coins[0].PercentChange24h); top > 0 {
+			scores[i] = math.Round(100 * math.Abs(coin.PercentChange24h) / top)
+		}
+	}
+	return coins, scores
+}
+
+// cryptocurrencyTrending serves the most searched or the most visited coins.
+func (s *Server) cryptocurrencyTrending(query url.Values, list trendingList) (any, int, *apiError) {
+	if _, err := timePeriod(query, cmc.TimePeriod24h); err != nil {
+		return nil, 0, err
+	}
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	coins, scores := s.activeCoins(), []float64(nil)
+	if list == trendingSearches {
+		coins, scores = s.bySearchScore()
+	}
+
+	data := make([]cmc.Trending, len(coins))
+	for i, coin := range coins {
+		data[i] = s.trending(coin, targets)
+		if scores != nil {
+			data[i].SearchScore = &scores[i]
+		}
+	}
+
+	data, err = page(query, data, 100)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, len(data), nil
+}
+
+// cryptocurrencyGainersLosers serves the active coins ordered by their price change
+// over time_period, largest gain first unless sort_dir is asc.
+func (s *Server) cryptocurrencyGainersLosers(query url.Values) (any, int, *apiError) {
+	period, err := timePeriod(query, cmc.TimePeriod24h)
+	if err != nil {
+		return nil, 0, err
+	}
+	if by := query.Get("sort"); by != "" && by != "percent_change_24h" {
+		return nil, 0, badRequest(`Invalid value for "sort": "%s"`, by)
+	}
+	dir := query.Get("sort_dir")
+	if dir != "" && dir != "asc" && dir != "desc" {
+		return nil, 0, badRequest(`Invalid value for "sort_dir": "%s"`, dir)
+	}
+	targets, err := s.convertTargets(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	coins := s.activeCoins()
+	changes := make(map[int]float64, len(coins))
+	for _, coin := range coins {
+		changes[coin.ID] = s.periodChange(coin, period)
+	}
+	sort.SliceStable(coins, func(i, j int) bool {
+		if dir == "asc" {
+			return changes[coins[i].ID] < changes[coins[j].ID]
+		}
+		return changes[coins[i].ID] > changes[coins[j].ID]
+	})
+
+	data := make([]cmc.Trending, len(coins))
+	for i, coin := range coins {
+		data[i] = s.trending(coin, targets)
+	}
+
+	data, err = page(query, data, 100)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, len(data), nil
+}
+
+// periodChange returns a coin's price change over a period ending now. The 1h, 24h and
+// 7d changes are the coin's own, as in its latest quote.
+func (s *Server) periodChange(coin Coin, period cmc.TimePeriod) float64 {
+	switch period {
+	case cmc.TimePeriod1h:
+		return coin.PercentChange1h
+	case cmc.TimePeriod24h:
+		return coin.PercentChange24h
+	case cmc.TimePeriod7d:
+		return coin.PercentChange7d
+	}
+	open, closeTime := s.periodWindow(coin, period)
+	return (s.priceAt(coin, closeTime)/s.priceAt(coin, open) - 1) * 100
+}
+
+func (s *Server) trending(coin Coin, targets []convertTarget) cmc.Trending {
+	quote := s.cryptocurrencyQuote(coin, targets)
+	return cmc.Trending{
+		ID:          quote.ID,
+		Name:        quote.Name,
+		Symbol:      quote.Symbol,
+		Slug:        quote.Slug,
+		CMCRank:     quote.CMCRank,
+		LastUpdated: quote.LastUpdated,
+		Quote:       quote.Quote,
+	}
+}
+
+// timePeriod reads the time_period parameter, falling back to defaultPeriod.
+func timePeriod(query url.Values, defaultPeriod cmc.TimePeriod) (cmc.TimePeriod, *apiError) {
+	period := cmc.TimePeriod(query.Get("time_period"))
+	if period == "" {
+		return defaultPeriod, nil
+	}
+	if !validPeriod(period) {
+		return "", badRequest(`Invalid value for "time_period": "%s"`, period)
+	}
+	return period, nil
+}
+
+func validPeriod(period cmc.TimePeriod) bool {
+	if period == cmc.TimePeriodAllTime || period == cmc.TimePeriodYesterday {
+		return true
+	}
+	_, ok := intervalDuration(cmc.Interval(period))
+	return ok
+}
+
+// periodWindow returns the start and end of a period ending now: since the coin was
+// added for all_time, and the previous UTC day for yesterday.
+func (s *Server) periodWindow(coin Coin, period cmc.TimePeriod) (time.Time, time.Time) {
+	now := s.now().UTC()
+	switch period {
+	case cmc.TimePeriodAllTime:
+		return coin.DateAdded, now
+	case cmc.TimePeriodYesterday:
+		today := now.Truncate(24 * time.Hour)
+		return today.Add(-24 * time.Hour), today
+	}
+	step, _ := intervalDuration(cmc.Interval(period))
+	return now.Add(-step), now
+}
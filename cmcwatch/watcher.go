@@ -0,0 +1,365 @@
+// Package cmcwatch streams quote updates by polling /v2/cryptocurrency/quotes/latest.
+// Polls are aligned to CoinMarketCap's update cadence using Quote.LastUpdated, and only
+// quotes that changed are emitted:
+//
+//	watcher := cmcwatch.New(client, []int{1, 1027}, cmcwatch.WithConvert("USD", "EUR"))
+//	go watcher.Run(ctx)
+//
+//	for update := range watcher.Updates() {
+//		fmt.Println(update.ID, update.Convert, *update.Quote.Price)
+//	}
+//
+// The watcher calls the client like any other caller, so it shares the client's rate
+// limiter, credit budget, cache and key pool.
+package cmcwatch
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// Defaults used by New.
+const (
+	// DefaultInterval matches how often CoinMarketCap refreshes latest quotes.
+	DefaultInterval = time.Minute
+	// DefaultDelay is how long after a quote's expected refresh the watcher polls.
+	DefaultDelay = 5 * time.Second
+	// DefaultMinBackoff and DefaultMaxBackoff bound the wait after failed polls.
+	DefaultMinBackoff = 5 * time.Second
+	DefaultMaxBackoff = 5 * time.Minute
+	// DefaultBuffer is the capacity of the Updates channel.
+	DefaultBuffer = 64
+)
+
+// Update is a changed quote of one asset in one convert currency.
+type Update struct {
+	ID       int
+	Convert  string
+	Asset    cmc.CryptocurrencyQuote // the entry the quote belongs to
+	Quote    cmc.Quote
+	Previous *cmc.Quote // nil for the first update of the asset and currency
+}
+
+// Time returns when CoinMarketCap last updated the quote, or the zero time if unknown.
+func (u Update) Time() time.Time {
+	if u.Quote.LastUpdated == nil {
+		return time.Time{}
+	}
+	return *u.Quote.LastUpdated
+}
+
+// Option configures a Watcher.
+type Option func(*Watcher)
+
+// WithInterval replaces DefaultInterval. Non-positive intervals are ignored.
+func WithInterval(interval time.Duration) Option {
+	return func(w *Watcher) {
+		w.interval = interval
+	}
+}
+
+// WithDelay replaces DefaultDelay. A negative delay is treated as zero.
+func WithDelay(delay time.Duration) Option {
+	return func(w *Watcher) {
+		w.delay = delay
+	}
+}
+
+// WithConvert sets the currencies to quote in. The API defaults to USD.
+func WithConvert(currencies ...string) Option {
+	return func(w *Watcher) {
+		w.convert = currencies
+	}
+}
+
+// WithBackoff bounds the wait after failed polls. It starts at min and doubles with
+// every consecutive failure up to max. A non-positive min or max keeps its default,
+// and max is raised to min if it is lower.
+func WithBackoff(min, max time.Duration) Option {
+	return func(w *Watcher) {
+		w.minBackoff, w.maxBackoff = min, max
+	}
+}
+
+// WithBuffer replaces DefaultBuffer. A negative size is treated as zero, an
+// unbuffered channel.
+func WithBuffer(size int) Option {
+	return func(w *Watcher) {
+		w.buffer = size
+	}
+}
+
+// WithErrorHandler receives the errors of failed polls. By default they are dropped
+// and the watcher retries after a backoff.
+func WithErrorHandler(fn func(error)) Option {
+	return func(w *Watcher) {
+		w.onError = fn
+	}
+}
+
+// Watcher polls latest quotes for a set of cryptocurrency IDs.
+type Watcher struct {
+	client     *cmc.Client
+	interval   time.Duration
+	delay      time.Duration
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	convert    []string
+	buffer     int
+	onError    func(error)
+	now        func() time.Time
+
+	updates chan Update
+
+	mu   sync.Mutex
+	ids  map[int]bool
+	last map[quoteKey]cmc.Quote
+}
+
+type quoteKey struct {
+	id      int
+	convert string
+}
+
+// New creates a watcher for ids. Call Run to start polling.
+func New(client *cmc.Client, ids []int, opts ...Option) *Watcher {
+	w := &Watcher{
+		client:     client,
+		interval:   DefaultInterval,
+		delay:      DefaultDelay,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+		buffer:     DefaultBuffer,
+		onError:    func(error) {},
+		now:        time.Now,
+		ids:        make(map[int]bool),
+		last:       make(map[quoteKey]cmc.Quote),
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	// The schedule and the backoff only advance with positive durations.
+	if w.interval <= 0 {
+		w.interval = DefaultInterval
+	}
+	if w.minBackoff <= 0 {
+		w.minBackoff = DefaultMinBackoff
+	}
+	if w.maxBackoff <= 0 {
+		w.maxBackoff = DefaultMaxBackoff
+	}
+	w.maxBackoff = max(w.maxBackoff, w.minBackoff)
+	w.delay = max(w.delay, 0)
+	w.buffer = max(w.buffer, 0)
+
+	w.updates = make(chan Update, w.buffer)
+	w.Add(ids...)
+	return w
+}
+
+// Updates returns the channel updates are sent on. Updates of an asset arrive in the
+// order CoinMarketCap published them. The channel is closed when Run returns.
+func (w *Watcher) Updates() <-chan Update {
+	return w.updates
+}
+
+// Add starts watching ids from the next poll.
+func (w *Watcher) Add(ids ...int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, id := range ids {
+		w.ids[id] = true
+	}
+}
+
+// Remove stops watching ids and forgets their last quotes.
+func (w *Watcher) Remove(ids ...int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, id := range ids {
+		delete(w.ids, id)
+		for key := range w.last {
+			if key.id == id {
+				delete(w.last, key)
+			}
+		}
+	}
+}
+
+// IDs returns the watched IDs in ascending order.
+func (w *Watcher) IDs() []int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ids := make([]int, 0, len(w.ids))
+	for id := range w.ids {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// Run polls until ctx is done and then closes the Updates channel. It returns ctx's
+// error. Run must be called only once.
+func (w *Watcher) Run(ctx context.Context) error {
+	defer close(w.updates)
+
+	failures := 0
+	for {
+		next, err := w.poll(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		wait := next.Sub(w.now())
+		if err != nil {
+			w.onError(err)
+			failures++
+			wait = w.backoff(failures, err)
+		} else {
+			failures = 0
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// poll fetches the watched quotes, emits the changed ones and returns when to poll next.
+func (w *Watcher) poll(ctx context.Context) (time.Time, error) {
+	ids := w.IDs()
+	if len(ids) == 0 {
+		return w.now().Add(w.interval), nil
+	}
+
+	resp, err := w.client.GetCryptocurrencyQuotesLatest(ctx, &cmc.CryptocurrencyQuotesOptions{
+		ID:      ids,
+		Convert: w.convert,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var latest time.Time
+	for _, id := range ids {
+		for _, asset := range resp.Data[strconv.Itoa(id)] {
+			for _, convert := range sortedKeys(asset.Quote) {
+				quote := asset.Quote[convert]
+				if quote == nil {
+					continue
+				}
+				if quote.LastUpdated != nil && quote.LastUpdated.After(latest) {
+					latest = *quote.LastUpdated
+				}
+
+				update, changed := w.diff(asset, convert, *quote)
+				if !changed {
+					continue
+				}
+				select {
+				case w.updates <- update:
+				case <-ctx.Done():
+					return time.Time{}, ctx.Err()
+				}
+			}
+		}
+	}
+
+	return w.nextPoll(latest), nil
+}
+
+// diff records quote and reports whether it changed since the last one. Quotes older
+// than the last one are dropped so updates never go back in time.
+func (w *Watcher) diff(asset cmc.CryptocurrencyQuote, convert string, quote cmc.Quote) (Update, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key := quoteKey{asset.ID, convert}
+	if !w.ids[asset.ID] {
+		return Update{}, false
+	}
+
+	previous, seen := w.last[key]
+	if seen && previous.LastUpdated != nil && quote.LastUpdated != nil && !quote.LastUpdated.After(*previous.LastUpdated) {
+		return Update{}, false
+	}
+	w.last[key] = quote
+	if seen && sameValues(previous, quote) {
+		return Update{}, false
+	}
+
+	update := Update{ID: asset.ID, Convert: convert, Asset: asset, Quote: quote}
+	if seen {
+		update.Previous = &previous
+	}
+	return update, true
+}
+
+// sameValues reports whether two quotes differ only in LastUpdated.
+func sameValues(a, b cmc.Quote) bool {
+	a.LastUpdated, b.LastUpdated = nil, nil
+	return reflect.DeepEqual(a, b)
+}
+
+// nextPoll returns the first time after now that is delay past an expected refresh,
+// assuming CoinMarketCap refreshes every interval from latest.
+func (w *Watcher) nextPoll(latest time.Time) time.Time {
+	now := w.now()
+	if latest.IsZero() || latest.After(now) {
+		return now.Add(w.interval)
+	}
+
+	next := latest.Add(w.interval + w.delay)
+	if !next.After(now) {
+		missed := now.Sub(next)/w.interval + 1
+		next = next.Add(missed * w.interval)
+	}
+	return next
+}
+
+// backoff returns how long to wait after the given number of consecutive failures.
+// Budget and key pool errors wait until the budget resets or a key returns.
+func (w *Watcher) backoff(failures int, err error) time.Duration {
+	var budgetErr *cmc.BudgetError
+	if errors.As(err, &budgetErr) {
+		return budgetErr.Reset.Sub(w.now())
+	}
+	var noKey *cmc.NoAvailableKeyError
+	if errors.As(err, &noKey) && !noKey.Next.IsZero() {
+		return noKey.Next.Sub(w.now())
+	}
+
+	wait := w.minBackoff
+	for i := 1; i < failures && wait < w.maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > w.maxBackoff {
+		wait = w.maxBackoff
+	}
+	return wait
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,191 @@
+package cmcwatch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+	"github.com/Davincible/go-coinmarketcap/cmctest"
+)
+
+// clock is a settable time source for the fake server.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func receive(t *testing.T, updates <-chan Update) Update {
+	t.Helper()
+	select {
+	case update := <-updates:
+		return update
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected an update")
+		return Update{}
+	}
+}
+
+func TestWatcherEmitsChangedQuotes(t *testing.T) {
+	serverClock := &clock{now: time.Now()}
+	fake := cmctest.NewServer(cmctest.WithClock(serverClock.Now))
+	defer fake.Close()
+
+	watcher := New(fake.Client(), []int{1027, 1}, WithInterval(10*time.Millisecond), WithDelay(0))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- watcher.Run(ctx) }()
+
+	first, second := receive(t, watcher.Updates()), receive(t, watcher.Updates())
+	if first.ID != 1 || second.ID != 1027 || first.Convert != "USD" || first.Previous != nil {
+		t.Fatalf("expected first updates for BTC and ETH in ID order, got %+v and %+v", first, second)
+	}
+
+	// Polls that see the same LastUpdated emit nothing.
+	requests := len(fake.Requests())
+	for len(fake.Requests()) < requests+3 {
+		time.Sleep(5 * time.Millisecond)
+	}
+	select {
+	case update := <-watcher.Updates():
+		t.Fatalf("unexpected update without new data: %+v", update)
+	default:
+	}
+
+	fake.UpdateCoin("BTC", func(c *cmctest.Coin) { c.Price = 70000 })
+	serverClock.Advance(time.Minute)
+
+	update := receive(t, watcher.Updates())
+	if update.ID != 1 || *update.Quote.Price != 70000 || update.Previous == nil || *update.Previous.Price == 70000 {
+		t.Fatalf("expected a BTC price change, got %+v", update)
+	}
+	if !update.Time().After(*update.Previous.LastUpdated) {
+		t.Error("expected the update to be newer than the previous quote")
+	}
+
+	// ETH got a new timestamp but the same values.
+	requests = len(fake.Requests())
+	for len(fake.Requests()) < requests+3 {
+		time.Sleep(5 * time.Millisecond)
+	}
+	select {
+	case update := <-watcher.Updates():
+		t.Fatalf("unexpected update for an unchanged quote: %+v", update)
+	default:
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if _, ok := <-watcher.Updates(); ok {
+		t.Error("expected the updates channel to be closed")
+	}
+}
+
+func TestWatcherRecoversFromErrors(t *testing.T) {
+	fake := cmctest.NewServer()
+	defer fake.Close()
+	fake.FailNext("/v2/cryptocurrency/quotes/latest", http.StatusInternalServerError, 0, "internal error")
+	fake.FailNext("/v2/cryptocurrency/quotes/latest", http.StatusInternalServerError, 0, "internal error")
+
+	var mu sync.Mutex
+	var errs []error
+	watcher := New(fake.Client(), []int{1},
+		WithBackoff(time.Millisecond, 5*time.Millisecond),
+		WithErrorHandler(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		}),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Run(ctx)
+
+	if update := receive(t, watcher.Updates()); update.ID != 1 {
+		t.Fatalf("expected a BTC update after recovering, got %+v", update)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(errs) != 2 {
+		t.Errorf("expected 2 reported errors, got %v", errs)
+	}
+}
+
+func TestWatcherTiming(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC)
+	watcher := New(nil, nil, WithDelay(5*time.Second), WithBackoff(time.Second, 10*time.Second))
+	watcher.now = func() time.Time { return now }
+
+	polls := []struct {
+		name   string
+		latest time.Time
+		want   time.Time
+	}{
+		{"next refresh", now.Add(-20 * time.Second), now.Add(45 * time.Second)},
+		{"missed refreshes", now.Add(-150 * time.Second), now.Add(35 * time.Second)},
+		{"unknown", time.Time{}, now.Add(time.Minute)},
+		{"server clock ahead", now.Add(time.Hour), now.Add(time.Minute)},
+	}
+	for _, tt := range polls {
+		if got := watcher.nextPoll(tt.latest); !got.Equal(tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	backoffs := []struct {
+		failures int
+		err      error
+		want     time.Duration
+	}{
+		{1, errors.New("failed"), time.Second},
+		{3, errors.New("failed"), 4 * time.Second},
+		{10, errors.New("failed"), 10 * time.Second},
+		{1, &cmc.BudgetError{Reset: now.Add(time.Hour)}, time.Hour},
+		{1, &cmc.NoAvailableKeyError{Next: now.Add(time.Minute)}, time.Minute},
+	}
+	for _, tt := range backoffs {
+		if got := watcher.backoff(tt.failures, tt.err); got != tt.want {
+			t.Errorf("backoff(%d, %v): expected %v, got %v", tt.failures, tt.err, tt.want, got)
+		}
+	}
+}
+
+func TestWatcherInvalidOptions(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC)
+	watcher := New(nil, nil, WithInterval(0), WithDelay(-time.Second), WithBackoff(-time.Second, 0), WithBuffer(-1))
+	watcher.now = func() time.Time { return now }
+
+	if got := watcher.nextPoll(now.Add(-150 * time.Second)); !got.Equal(now.Add(30 * time.Second)) {
+		t.Errorf("expected the default interval without a delay, got %v", got)
+	}
+	if got := watcher.backoff(3, errors.New("failed")); got != 4*DefaultMinBackoff {
+		t.Errorf("expected the default backoff to grow, got %v", got)
+	}
+	if cap(watcher.updates) != 0 {
+		t.Errorf("expected an unbuffered channel, got capacity %d", cap(watcher.updates))
+	}
+
+	watcher = New(nil, nil, WithBackoff(time.Minute, time.Second))
+	if got := watcher.backoff(5, errors.New("failed")); got != time.Minute {
+		t.Errorf("expected max to be raised to min, got %v", got)
+	}
+}
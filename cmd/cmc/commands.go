@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+type runFunc = func(ctx context.Context, client *cmc.Client, args []string) (result, error)
+
+func quoteCommand(fs *flag.FlagSet) runFunc {
+	opts := &cmc.CryptocurrencyQuotesOptions{}
+	bindOptions(fs, opts)
+
+	return func(ctx context.Context, client *cmc.Client, args []string) (result, error) {
+		opts.Symbol = append(opts.Symbol, upper(args)...)
+		if len(opts.Symbol) == 0 && len(opts.ID) == 0 && len(opts.Slug) == 0 {
+			return result{}, errors.New("give at least one symbol, -id or -slug")
+		}
+
+		resp, err := client.GetCryptocurrencyQuotesLatest(ctx, opts)
+		if err != nil {
+			return result{}, err
+		}
+
+		res := result{
+			data:    resp.Data,
+			headers: []string{"SYMBOL", "NAME", "CURRENCY", "PRICE", "1H", "24H", "7D", "MARKET CAP", "VOLUME 24H"},
+		}
+		for _, key := range sortedKeys(resp.Data) {
+			for _, coin := range resp.Data[key] {
+				for _, currency := range sortedKeys(coin.Quote) {
+					q := coin.Quote[currency]
+					res.add(coin.Symbol, coin.Name, currency, money(q.Price), pct(q.PercentChange1h),
+						pct(q.PercentChange24h), pct(q.PercentChange7d), money(q.MarketCap), money(q.Volume24h))
+				}
+			}
+		}
+		return res, nil
+	}
+}
+
+func listingsCommand(fs *flag.FlagSet) runFunc {
+	opts := &cmc.CryptocurrencyListingsOptions{}
+	bindOptions(fs, opts)
+
+	return func(ctx context.Context, client *cmc.Client, args []string) (result, error) {
+		resp, err := client.GetCryptocurrencyListingsLatest(ctx, opts)
+		if err != nil {
+			return result{}, err
+		}
+
+		res := result{
+			data:    resp.Data,
+			headers: []string{"RANK", "SYMBOL", "NAME", "CURRENCY", "PRICE", "24H", "MARKET CAP", "VOLUME 24H"},
+		}
+		for _, coin := range resp.Data {
+			for _, currency := range sortedKeys(coin.Quote) {
+				q := coin.Quote[currency]
+				res.add(integer(coin.CMCRank), coin.Symbol, coin.Name, currency, money(q.Price),
+					pct(q.PercentChange24h), money(q.MarketCap), money(q.Volume24h))
+			}
+		}
+		return res, nil
+	}
+}
+
+func infoCommand(fs *flag.FlagSet) runFunc {
+	opts := &cmc.CryptocurrencyInfoOptions{}
+	bindOptions(fs, opts)
+
+	return func(ctx context.Context, client *cmc.Client, args []string) (result, error) {
+		opts.Symbol = append(opts.Symbol, upper(args)...)
+		if len(opts.Symbol) == 0 && len(opts.ID) == 0 && len(opts.Slug) == 0 && len(opts.Address) == 0 {
+			return result{}, errors.New("give at least one symbol, -id, -slug or -address")
+		}
+
+		resp, err := client.GetCryptocurrencyInfo(ctx, opts)
+		if err != nil {
+			return result{}, err
+		}
+
+		res := result{
+			data:    resp.Data,
+			headers: []string{"ID", "SYMBOL", "NAME", "SLUG", "CATEGORY", "DATE ADDED", "WEBSITE"},
+		}
+		for _, key := range sortedKeys(resp.Data) {
+			info := resp.Data[key]
+			website := ""
+			if urls := info.URLs["website"]; len(urls) > 0 {
+				website = urls[0]
+			}
+			res.add(strconv.Itoa(info.ID), info.Symbol, info.Name, info.Slug, info.Category,
+				timestamp(&info.DateAdded), website)
+		}
+		return res, nil
+	}
+}
+
+func ohlcvCommand(fs *flag.FlagSet) runFunc {
+	opts := &cmc.CryptocurrencyOHLCVHistoricalOptions{}
+	bindOptions(fs, opts)
+
+	return func(ctx context.Context, client *cmc.Client, args []string) (result, error) {
+		opts.Symbol = append(opts.Symbol, upper(args)...)
+		if len(opts.Symbol) == 0 && len(opts.ID) == 0 && len(opts.Slug) == 0 {
+			return result{}, errors.New("give at least one symbol, -id or -slug")
+		}
+
+		resp, err := client.GetCryptocurrencyOHLCVHistorical(ctx, opts)
+		if err != nil {
+			return result{}, err
+		}
+
+		res := result{
+			data:    resp.Data,
+			headers: []string{"KEY", "TIME OPEN", "OPEN", "HIGH", "LOW", "CLOSE", "VOLUME"},
+		}
+		for _, key := range sortedKeys(resp.Data) {
+			for _, candle := range resp.Data[key] {
+				res.add(key, timestamp(candle.TimeOpen), num(candle.Open), num(candle.High),
+					num(candle.Low), num(candle.Close), num(candle.Volume))
+			}
+		}
+		return res, nil
+	}
+}
+
+func pairsCommand(fs *flag.FlagSet) runFunc {
+	opts := &cmc.CryptocurrencyMarketPairsOptions{}
+	bindOptions(fs, opts)
+
+	return func(ctx context.Context, client *cmc.Client, args []string) (result, error) {
+		switch {
+		case len(args) > 1:
+			return result{}, errors.New("give a single symbol")
+		case len(args) == 1:
+			symbol := strings.ToUpper(args[0])
+			opts.Symbol = &symbol
+		case opts.Symbol == nil && opts.ID == nil && opts.Slug == nil:
+			return result{}, errors.New("give a symbol, -id or -slug")
+		}
+
+		resp, err := client.GetCryptocurrencyMarketPairsLatest(ctx, opts)
+		if err != nil {
+			return result{}, err
+		}
+
+		res := result{
+			data:    resp.Data,
+			headers: []string{"EXCHANGE", "PAIR", "CATEGORY", "CURRENCY", "PRICE", "VOLUME 24H"},
+		}
+		for _, key := range sortedKeys(resp.Data) {
+			for _, pair := range resp.Data[key] {
+				for _, currency := range sortedKeys(pair.Quote) {
+					q := pair.Quote[currency]
+					res.add(pair.ExchangeName, pair.MarketPair, pair.Category, currency, money(q.Price), money(q.Volume24h))
+				}
+			}
+		}
+		return res, nil
+	}
+}
+
+func exchangesCommand(fs *flag.FlagSet) runFunc {
+	opts := &cmc.ExchangeListingsOptions{}
+	bindOptions(fs, opts)
+
+	return func(ctx context.Context, client *cmc.Client, args []string) (result, error) {
+		resp, err := client.GetExchangeListingsLatest(ctx, opts)
+		if err != nil {
+			return result{}, err
+		}
+
+		res := result{
+			data:    resp.Data,
+			headers: []string{"ID", "NAME", "SLUG", "MARKET PAIRS", "SCORE", "CURRENCY", "VOLUME 24H"},
+		}
+		for _, exchange := range resp.Data {
+			if len(exchange.Quote) == 0 {
+				res.add(strconv.Itoa(exchange.ID), exchange.Name, exchange.Slug, integer(exchange.NumMarketPairs),
+					num(exchange.ExchangeScore), "", money(exchange.Volume24hAdjusted))
+				continue
+			}
+			for _, currency := range sortedKeys(exchange.Quote) {
+				res.add(strconv.Itoa(exchange.ID), exchange.Name, exchange.Slug, integer(exchange.NumMarketPairs),
+					num(exchange.ExchangeScore), currency, money(exchange.Quote[currency].Volume24h))
+			}
+		}
+		return res, nil
+	}
+}
+
+func globalCommand(fs *flag.FlagSet) runFunc {
+	opts := &cmc.GlobalMetricsOptions{}
+	bindOptions(fs, opts)
+
+	return func(ctx context.Context, client *cmc.Client, args []string) (result, error) {
+		resp, err := client.GetGlobalMetricsLatest(ctx, opts)
+		if err != nil {
+			return result{}, err
+		}
+
+		m := resp.Data
+		res := result{
+			data:    m,
+			headers: []string{"METRIC", "VALUE"},
+		}
+		res.add("active cryptocurrencies", integer(m.ActiveCryptocurrencies))
+		res.add("active exchanges", integer(m.ActiveExchanges))
+		res.add("active market pairs", integer(m.ActiveMarketPairs))
+		res.add("btc dominance", pct(m.BtcDominance))
+		res.add("eth dominance", pct(m.EthDominance))
+		for _, currency := range sortedKeys(m.Quote) {
+			q := m.Quote[currency]
+			res.add("total market cap "+currency, money(q.MarketCap))
+			res.add("volume 24h "+currency, money(q.Volume24h))
+		}
+		res.add("last updated", timestamp(&m.LastUpdated))
+		return res, nil
+	}
+}
+
+func convertCommand(fs *flag.FlagSet) runFunc {
+	opts := &cmc.PriceConversionOptions{}
+	bindOptions(fs, opts)
+
+	return func(ctx context.Context, client *cmc.Client, args []string) (result, error) {
+		if len(args) > 0 {
+			amount, err := strconv.ParseFloat(args[0], 64)
+			if err != nil {
+				return result{}, fmt.Errorf("invalid amount %q", args[0])
+			}
+			opts.Amount = amount
+		}
+		if len(args) > 1 {
+			symbol := strings.ToUpper(args[1])
+			opts.Symbol = &symbol
+		}
+		if len(args) > 2 {
+			opts.Convert = append(opts.Convert, upper(args[2:])...)
+		}
+		if opts.Amount == 0 || (opts.Symbol == nil && opts.ID == nil) {
+			return result{}, errors.New("usage: cmc convert [flags] <amount> <symbol> [currency...]")
+		}
+
+		resp, err := client.GetPriceConversion(ctx, opts)
+		if err != nil {
+			return result{}, err
+		}
+
+		conversion := resp.Data
+		res := result{
+			data:    conversion,
+			headers: []string{"AMOUNT", "SYMBOL", "CURRENCY", "PRICE", "LAST UPDATED"},
+		}
+		amount := strconv.FormatFloat(conversion.Amount, 'f', -1, 64)
+		for _, currency := range sortedKeys(conversion.Quote) {
+			q := conversion.Quote[currency]
+			res.add(amount, conversion.Symbol, currency, money(&q.Price), timestamp(&q.LastUpdated))
+		}
+		return res, nil
+	}
+}
+
+func fngCommand(fs *flag.FlagSet) runFunc {
+	opts := &cmc.FearAndGreedHistoricalOptions{}
+	bindOptions(fs, opts)
+
+	return func(ctx context.Context, client *cmc.Client, args []string) (result, error) {
+		var readings []cmc.FearAndGreed
+		if opts.Start == nil && opts.Limit == nil {
+			resp, err := client.GetFearAndGreedLatest(ctx)
+			if err != nil {
+				return result{}, err
+			}
+			readings = []cmc.FearAndGreed{resp.Data}
+		} else {
+			resp, err := client.GetFearAndGreedHistorical(ctx, opts)
+			if err != nil {
+				return result{}, err
+			}
+			readings = resp.Data
+		}
+
+		res := result{
+			data:    readings,
+			headers: []string{"TIMESTAMP", "VALUE", "CLASSIFICATION"},
+		}
+		for _, reading := range readings {
+			res.add(timestamp(&reading.Timestamp), strconv.Itoa(reading.Value), reading.ValueClassification)
+		}
+		return res, nil
+	}
+}
+
+func keyInfoCommand(fs *flag.FlagSet) runFunc {
+	return func(ctx context.Context, client *cmc.Client, args []string) (result, error) {
+		resp, err := client.GetKeyInfo(ctx)
+		if err != nil {
+			return result{}, err
+		}
+
+		info := resp.Data
+		res := result{
+			data:    info,
+			headers: []string{"METRIC", "VALUE"},
+		}
+		res.add("plan", info.Plan.Name)
+		res.add("rate limit per minute", strconv.Itoa(info.Plan.RateLimitMinute))
+		res.add("requests made this minute", strconv.Itoa(info.Usage.CurrentMinute.RequestsMade))
+		res.add("requests left this minute", strconv.Itoa(info.Usage.CurrentMinute.RequestsLeft))
+		res.add("daily credit limit", strconv.Itoa(info.Plan.CreditLimitDaily))
+		res.add("credits used today", strconv.Itoa(info.Usage.CurrentDay.CreditsUsed))
+		res.add("credits left today", strconv.Itoa(info.Usage.CurrentDay.CreditsLeft))
+		res.add("monthly credit limit", strconv.Itoa(info.Plan.CreditLimitMonthly))
+		res.add("credits used this month", strconv.Itoa(info.Usage.CurrentMonth.CreditsUsed))
+		res.add("credits left this month", strconv.Itoa(info.Usage.CurrentMonth.CreditsLeft))
+		return res, nil
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// bindOptions registers one flag per `query`-tagged field of the struct pointed to by opts.
+// Flag names are the query names with underscores replaced by dashes, so the ConvertID
+// field tagged `query:"convert_id"` becomes -convert-id. Unset flags leave fields nil.
+func bindOptions(fs *flag.FlagSet, opts any) {
+	bindFields(fs, reflect.ValueOf(opts).Elem())
+}
+
+func bindFields(fs *flag.FlagSet, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("query")
+
+		if field.Anonymous && key == "" && v.Field(i).Kind() == reflect.Struct {
+			bindFields(fs, v.Field(i))
+			continue
+		}
+		if key == "" || key == "-" || !field.IsExported() {
+			continue
+		}
+
+		name := strings.ReplaceAll(key, "_", "-")
+		if fs.Lookup(name) != nil {
+			continue
+		}
+		fs.Var(&fieldValue{v: v.Field(i)}, name, usageFor(field))
+	}
+}
+
+// usageFor describes the expected value of a field.
+func usageFor(field reflect.StructField) string {
+	typ := field.Type
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() == reflect.Slice {
+		return fmt.Sprintf("comma-separated %s values (%s)", typ.Elem().Kind(), field.Name)
+	}
+	if typ.Name() != "" && typ.PkgPath() != "" {
+		return fmt.Sprintf("%s value (%s)", typ.Name(), field.Name)
+	}
+	return fmt.Sprintf("%s value (%s)", typ.Kind(), field.Name)
+}
+
+// fieldValue is a flag.Value that writes into a struct field.
+type fieldValue struct {
+	v reflect.Value
+}
+
+func (f *fieldValue) String() string {
+	if f == nil || !f.v.IsValid() {
+		return ""
+	}
+
+	v := f.v
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice {
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(parts, ",")
+	}
+	if v.IsZero() {
+		return ""
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func (f *fieldValue) Set(s string) error {
+	switch f.v.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(f.v.Type().Elem())
+		if err := setScalar(elem.Elem(), s); err != nil {
+			return err
+		}
+		f.v.Set(elem)
+		return nil
+	case reflect.Slice:
+		for _, part := range strings.Split(s, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			elem := reflect.New(f.v.Type().Elem()).Elem()
+			if err := setScalar(elem, part); err != nil {
+				return err
+			}
+			f.v.Set(reflect.Append(f.v, elem))
+		}
+		return nil
+	default:
+		return setScalar(f.v, s)
+	}
+}
+
+// IsBoolFlag lets boolean fields be set with a bare -flag.
+func (f *fieldValue) IsBoolFlag() bool {
+	typ := f.v.Type()
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Bool
+}
+
+func setScalar(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		v.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+// Command cmc queries the CoinMarketCap API from the command line.
+//
+// Usage:
+//
+//	cmc <command> [flags] [args]
+//
+// Commands are quote, listings, info, ohlcv, pairs, exchanges, global, convert, fng
+// and keyinfo. Every command accepts the flags of its options struct, named after the
+// API query parameters (for example -convert, -convert-id and -sort-dir), plus:
+//
+//	-o, -output   table (default), json, ndjson or csv
+//	-api-key      API key, overriding CMC_API_KEY and the config file
+//	-config       config file, defaulting to $CMC_CONFIG or <user config dir>/cmc/config.json
+//	-sandbox      use the sandbox API
+//	-base-url     use a different API base URL
+//	-timeout      overall request timeout
+//
+// The config file is JSON:
+//
+//	{"api_key": "...", "sandbox": false, "base_url": "", "output": "table"}
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// command is a cmc subcommand. run registers its flags on fs, which has already been
+// parsed by the time the returned function is called.
+type command struct {
+	name    string
+	summary string
+	setup   func(fs *flag.FlagSet) runFunc
+}
+
+var commands = []command{
+	{"quote", "latest quotes for symbols, IDs or slugs", quoteCommand},
+	{"listings", "latest cryptocurrency listings", listingsCommand},
+	{"info", "cryptocurrency metadata", infoCommand},
+	{"ohlcv", "historical OHLCV candles", ohlcvCommand},
+	{"pairs", "market pairs of a cryptocurrency", pairsCommand},
+	{"exchanges", "latest exchange listings", exchangesCommand},
+	{"global", "global market metrics", globalCommand},
+	{"convert", "convert an amount between currencies", convertCommand},
+	{"fng", "fear and greed index", fngCommand},
+	{"keyinfo", "API key plan and usage", keyInfoCommand},
+}
+
+// config is the on-disk configuration file.
+type config struct {
+	APIKey  string `json:"api_key"`
+	Sandbox bool   `json:"sandbox"`
+	BaseURL string `json:"base_url"`
+	Output  string `json:"output"`
+}
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr, os.Getenv))
+}
+
+// run executes a command line and returns the process exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == args[0] {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(stderr, "cmc: unknown command %q\n\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("cmc "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var (
+		output     string
+		apiKey     string
+		configPath string
+		sandbox    bool
+		baseURL    string
+		timeout    time.Duration
+	)
+	fs.StringVar(&output, "output", "", "output format: table, json, ndjson or csv")
+	fs.StringVar(&output, "o", "", "shorthand for -output")
+	fs.StringVar(&apiKey, "api-key", "", "API key (defaults to $CMC_API_KEY or the config file)")
+	fs.StringVar(&configPath, "config", defaultConfigPath(getenv), "config file")
+	fs.BoolVar(&sandbox, "sandbox", false, "use the sandbox API")
+	fs.StringVar(&baseURL, "base-url", "", "API base URL")
+	fs.DurationVar(&timeout, "timeout", 30*time.Second, "overall request timeout")
+
+	exec := cmd.setup(fs)
+
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	cfg, err := loadConfig(configPath, isFlagSet(fs, "config"))
+	if err != nil {
+		fmt.Fprintf(stderr, "cmc: %v\n", err)
+		return 1
+	}
+
+	if apiKey == "" {
+		apiKey = getenv("CMC_API_KEY")
+	}
+	if apiKey == "" {
+		apiKey = cfg.APIKey
+	}
+	if output == "" {
+		output = cfg.Output
+	}
+	if baseURL == "" {
+		baseURL = cfg.BaseURL
+	}
+	if err := checkFormat(output); err != nil {
+		fmt.Fprintf(stderr, "cmc: %v\n", err)
+		return 1
+	}
+
+	opts := []cmc.Option{
+		cmc.WithAPIKey(apiKey),
+		cmc.WithSandbox(sandbox || cfg.Sandbox),
+		cmc.WithUserAgent("cmc-cli"),
+	}
+	if baseURL != "" {
+		opts = append(opts, cmc.WithBaseURL(baseURL))
+	}
+	client := cmc.NewClient(opts...)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	res, err := exec(ctx, client, fs.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "cmc %s: %v\n", cmd.name, err)
+		return 1
+	}
+
+	if err := write(stdout, output, res); err != nil {
+		fmt.Fprintf(stderr, "cmc: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: cmc <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "cmc <command> -h" for the flags of a command.`)
+}
+
+// defaultConfigPath returns $CMC_CONFIG or cmc/config.json in the user config directory.
+func defaultConfigPath(getenv func(string) string) string {
+	if path := getenv("CMC_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cmc", "config.json")
+}
+
+// loadConfig reads the config file. A missing file is only an error when it was requested explicitly.
+func loadConfig(path string, explicit bool) (config, error) {
+	var cfg config
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !explicit {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// upper returns the arguments in upper case, as symbols are matched case-sensitively.
+func upper(args []string) []string {
+	out := make([]string, len(args))
+	for i, arg := range args {
+		out[i] = strings.ToUpper(arg)
+	}
+	return out
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+	"github.com/Davincible/go-coinmarketcap/cmctest"
+)
+
+// runCLI runs a command line against the fake server with the given environment.
+func runCLI(t *testing.T, env map[string]string, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+
+	var out, errOut bytes.Buffer
+	getenv := func(key string) string { return env[key] }
+	if _, ok := env["CMC_CONFIG"]; !ok {
+		env["CMC_CONFIG"] = filepath.Join(t.TempDir(), "missing.json")
+	}
+
+	code = run(context.Background(), args, &out, &errOut, getenv)
+	return out.String(), errOut.String(), code
+}
+
+func TestCommands(t *testing.T) {
+	server := cmctest.NewServer()
+	defer server.Close()
+
+	tests := []struct {
+		args     []string
+		contains []string
+	}{
+		{[]string{"quote", "-convert", "USD,EUR", "btc", "eth"}, []string{"BTC", "Bitcoin", "EUR", "65000.00", "ETH"}},
+		{[]string{"listings", "-limit", "2"}, []string{"Bitcoin", "Ethereum"}},
+		{[]string{"info", "sol"}, []string{"Solana"}},
+		{[]string{"ohlcv", "BTC"}, []string{"KEY"}},
+		{[]string{"pairs", "BTC"}, []string{"EXCHANGE"}},
+		{[]string{"exchanges"}, []string{"Binance"}},
+		{[]string{"global"}, []string{"total market cap USD", "btc dominance"}},
+		{[]string{"convert", "-convert", "EUR", "2", "btc"}, []string{"BTC", "EUR", "119600.00"}},
+		{[]string{"keyinfo"}, []string{"credits used today"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.args[0], func(t *testing.T) {
+			args := append([]string{tt.args[0], "-base-url", server.URL}, tt.args[1:]...)
+			stdout, stderr, code := runCLI(t, map[string]string{"CMC_API_KEY": cmctest.APIKey}, args...)
+			if code != 0 {
+				t.Fatalf("expected exit code 0, got %d: %s", code, stderr)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, stdout)
+				}
+			}
+		})
+	}
+}
+
+func TestOutputFormats(t *testing.T) {
+	server := cmctest.NewServer()
+	defer server.Close()
+
+	env := map[string]string{"CMC_API_KEY": cmctest.APIKey}
+
+	stdout, stderr, code := runCLI(t, env, "listings", "-base-url", server.URL, "-limit", "3", "-o", "csv")
+	if code != 0 {
+		t.Fatalf("csv: exit code %d: %s", code, stderr)
+	}
+	rows, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	if err != nil {
+		t.Fatalf("csv: invalid output: %v", err)
+	}
+	if len(rows) != 4 || rows[0][0] != "RANK" || rows[1][1] != "BTC" {
+		t.Errorf("csv: unexpected rows %v", rows)
+	}
+
+	stdout, stderr, code = runCLI(t, env, "listings", "-base-url", server.URL, "-limit", "3", "-output", "json")
+	if code != 0 {
+		t.Fatalf("json: exit code %d: %s", code, stderr)
+	}
+	var listings []cmc.CryptocurrencyListing
+	if err := json.Unmarshal([]byte(stdout), &listings); err != nil || len(listings) != 3 {
+		t.Errorf("json: expected 3 listings, got %d (%v)", len(listings), err)
+	}
+
+	stdout, stderr, code = runCLI(t, env, "quote", "-base-url", server.URL, "-o", "ndjson", "BTC", "ETH")
+	if code != 0 {
+		t.Fatalf("ndjson: exit code %d: %s", code, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("ndjson: expected 2 records, got %d:\n%s", len(lines), stdout)
+	}
+	var quote cmc.CryptocurrencyQuote
+	if err := json.Unmarshal([]byte(lines[0]), &quote); err != nil || quote.Symbol != "BTC" {
+		t.Errorf("ndjson: unexpected first record %s (%v)", lines[0], err)
+	}
+
+	sent := len(server.Requests())
+	_, stderr, code = runCLI(t, env, "global", "-base-url", server.URL, "-o", "yaml")
+	if code != 1 || !strings.Contains(stderr, "unknown output format") {
+		t.Errorf("expected unknown format error, got %d: %s", code, stderr)
+	}
+	if n := len(server.Requests()) - sent; n != 0 {
+		t.Errorf("expected no requests with an unknown format, got %d", n)
+	}
+}
+
+func TestAPIKeyPrecedence(t *testing.T) {
+	server := cmctest.NewServer(cmctest.WithAPIKeys("flag-key", "env-key", "config-key"))
+	defer server.Close()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	config := `{"api_key": "config-key", "base_url": "` + server.URL + `", "output": "csv"}`
+	if err := os.WriteFile(configPath, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{"config", map[string]string{"CMC_CONFIG": configPath}, nil, "config-key"},
+		{"env", map[string]string{"CMC_CONFIG": configPath, "CMC_API_KEY": "env-key"}, nil, "env-key"},
+		{"flag", map[string]string{"CMC_CONFIG": configPath, "CMC_API_KEY": "env-key"}, []string{"-api-key", "flag-key"}, "flag-key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := server.Usage(tt.want).MinuteRequests
+			args := append([]string{"keyinfo"}, tt.args...)
+			stdout, stderr, code := runCLI(t, tt.env, args...)
+			if code != 0 {
+				t.Fatalf("exit code %d: %s", code, stderr)
+			}
+			if !strings.HasPrefix(stdout, "METRIC,VALUE") {
+				t.Errorf("expected CSV output from the config file, got:\n%s", stdout)
+			}
+			if server.Usage(tt.want).MinuteRequests != before+1 {
+				t.Errorf("expected the request to use %s", tt.want)
+			}
+		})
+	}
+
+	_, stderr, code := runCLI(t, map[string]string{}, "keyinfo", "-config", filepath.Join(t.TempDir(), "nope.json"))
+	if code != 1 || !strings.Contains(stderr, "failed to read config") {
+		t.Errorf("expected a missing explicit config to fail, got %d: %s", code, stderr)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	_, stderr, code := runCLI(t, map[string]string{}, "frobnicate")
+	if code != 2 || !strings.Contains(stderr, "unknown command") {
+		t.Errorf("expected unknown command, got %d: %s", code, stderr)
+	}
+
+	_, stderr, code = runCLI(t, map[string]string{}, "quote", "-base-url", "http://127.0.0.1:0")
+	if code != 1 || !strings.Contains(stderr, "give at least one symbol") {
+		t.Errorf("expected missing symbol error, got %d: %s", code, stderr)
+	}
+
+	_, _, code = runCLI(t, map[string]string{}, "listings", "-limit", "many")
+	if code != 2 {
+		t.Errorf("expected exit code 2 for an invalid flag, got %d", code)
+	}
+}
+
+func TestBindOptions(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	opts := &cmc.CryptocurrencyListingsOptions{}
+	bindOptions(fs, opts)
+
+	err := fs.Parse([]string{"-limit", "10", "-price-min", "0.5", "-convert", "USD,EUR", "-convert", "GBP", "-sort", "volume_24h", "-convert-id", "2781"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if opts.Limit == nil || *opts.Limit != 10 {
+		t.Errorf("expected limit 10, got %v", opts.Limit)
+	}
+	if opts.PriceMin == nil || *opts.PriceMin != 0.5 {
+		t.Errorf("expected price_min 0.5, got %v", opts.PriceMin)
+	}
+	if strings.Join(opts.Convert, ",") != "USD,EUR,GBP" {
+		t.Errorf("expected convert USD,EUR,GBP, got %v", opts.Convert)
+	}
+	if opts.Sort == nil || *opts.Sort != cmc.SortVolume24h {
+		t.Errorf("expected sort volume_24h, got %v", opts.Sort)
+	}
+	if len(opts.ConvertID) != 1 || opts.ConvertID[0] != 2781 {
+		t.Errorf("expected convert_id 2781, got %v", opts.ConvertID)
+	}
+	if opts.PriceMax != nil || opts.Tag != nil {
+		t.Error("expected unset flags to leave fields nil")
+	}
+
+	quotes := &cmc.CryptocurrencyQuotesOptions{}
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	bindOptions(fs, quotes)
+	if err := fs.Parse([]string{"-skip-invalid"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if quotes.SkipInvalid == nil || !*quotes.SkipInvalid {
+		t.Error("expected a bare boolean flag to set the field")
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Output formats accepted by -output.
+const (
+	formatTable  = "table"
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+	formatCSV    = "csv"
+)
+
+// result is the output of a command: the raw API data for JSON formats and a flat
+// table for the table and CSV formats.
+type result struct {
+	data    any
+	headers []string
+	rows    [][]string
+}
+
+func (r *result) add(row ...string) {
+	r.rows = append(r.rows, row)
+}
+
+// checkFormat returns an error for a format write does not accept, so that a bad -output
+// fails before any request is made.
+func checkFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatNDJSON, formatCSV, "":
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q (want table, json, ndjson or csv)", format)
+	}
+}
+
+// write renders a result in the given format.
+func write(w io.Writer, format string, r result) error {
+	switch format {
+	case formatTable, "":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(r.headers, "\t"))
+		for _, row := range r.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(r.headers); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(r.rows); err != nil {
+			return err
+		}
+		return cw.Error()
+
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r.data)
+
+	case formatNDJSON:
+		enc := json.NewEncoder(w)
+		for _, record := range records(r.data) {
+			if err := enc.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return checkFormat(format)
+	}
+}
+
+// records splits data into one record per slice element or map value, in key order.
+// Map values that are slices are flattened.
+func records(data any) []any {
+	v := reflect.ValueOf(data)
+	switch v.Kind() {
+	case reflect.Slice:
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = v.Index(i).Interface()
+		}
+		return out
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+
+		var out []any
+		for _, key := range keys {
+			value := v.MapIndex(key)
+			if value.Kind() == reflect.Slice {
+				out = append(out, records(value.Interface())...)
+				continue
+			}
+			out = append(out, value.Interface())
+		}
+		return out
+	default:
+		return []any{data}
+	}
+}
+
+// num formats an optional number, leaving missing values blank.
+func num(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+// money formats an optional amount with two decimals, or more for small values.
+func money(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	if *v != 0 && *v < 1 && *v > -1 {
+		return strconv.FormatFloat(*v, 'g', 6, 64)
+	}
+	return strconv.FormatFloat(*v, 'f', 2, 64)
+}
+
+// pct formats an optional percentage with two decimals.
+func pct(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 2, 64) + "%"
+}
+
+func integer(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func timestamp(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
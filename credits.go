@@ -0,0 +1,233 @@
+package coinmarketcap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is matched by errors.Is when a request is refused because
+// the configured daily or monthly credit budget has been used up.
+var ErrBudgetExceeded = errors.New("credit budget exceeded")
+
+// BudgetError describes which credit budget blocked a request.
+type BudgetError struct {
+	Period string // "daily" or "monthly"
+	Used   int
+	Limit  int
+	Reset  time.Time
+}
+
+// Error implements the error interface.
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("%s credit budget exceeded: %d of %d credits used (resets %s)",
+		e.Period, e.Used, e.Limit, e.Reset.Format(time.RFC3339))
+}
+
+// Is reports whether target is ErrBudgetExceeded.
+func (e *BudgetError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+// WithCreditBudget caps the credits the client may spend per day and per month.
+// Once a budget is used up, requests fail with ErrBudgetExceeded before being sent.
+// A limit of zero means unlimited.
+//
+// The budget is a soft limit. A request's cost is only known from its response, so
+// each request in flight holds one credit, the least a request costs, and requests
+// costing more can together overshoot the budget by their extra credits.
+func WithCreditBudget(daily, monthly int) Option {
+	return func(c *ClientConfig) {
+		c.DailyCreditBudget = daily
+		c.MonthlyCreditBudget = monthly
+	}
+}
+
+// CreditUsage is a snapshot of the credits recorded by a CreditMeter.
+type CreditUsage struct {
+	Daily         int
+	Monthly       int
+	DailyBudget   int
+	MonthlyBudget int
+	DailyReset    time.Time
+	MonthlyReset  time.Time
+	ByEndpoint    map[string]int
+}
+
+// CreditMeter sums the Status.CreditCount of every response, per endpoint and
+// per billing period, and enforces optional daily and monthly budgets. See
+// WithCreditBudget for how far requests in flight can overshoot a budget.
+// Periods roll over at the reset times reported by GetKeyInfo, or at UTC
+// midnight and the first of the month until the meter has been seeded.
+type CreditMeter struct {
+	mu            sync.Mutex
+	byEndpoint    map[string]int
+	daily         int
+	monthly       int
+	dailyBudget   int
+	monthlyBudget int
+	dailyReset    time.Time
+	monthlyReset  time.Time
+	reserved      int // credits held by requests in flight
+	now           func() time.Time
+}
+
+// NewCreditMeter creates a meter with the given daily and monthly budgets (zero means unlimited).
+func NewCreditMeter(dailyBudget, monthlyBudget int) *CreditMeter {
+	m := &CreditMeter{
+		byEndpoint:    make(map[string]int),
+		dailyBudget:   dailyBudget,
+		monthlyBudget: monthlyBudget,
+		now:           time.Now,
+	}
+
+	now := m.now().UTC()
+	m.dailyReset = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	m.monthlyReset = time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+
+	return m
+}
+
+// roll resets the period counters whose reset time has passed. Callers must hold m.mu.
+func (m *CreditMeter) roll() {
+	now := m.now()
+
+	if !now.Before(m.dailyReset) {
+		m.daily = 0
+		for !now.Before(m.dailyReset) {
+			m.dailyReset = m.dailyReset.AddDate(0, 0, 1)
+		}
+	}
+
+	if !now.Before(m.monthlyReset) {
+		m.monthly = 0
+		for !now.Before(m.monthlyReset) {
+			m.monthlyReset = m.monthlyReset.AddDate(0, 1, 0)
+		}
+	}
+}
+
+// Seed aligns the meter with the usage and reset times reported by GetKeyInfo.
+// Credits recorded per endpoint are kept.
+func (m *CreditMeter) Seed(info *KeyInfo) {
+	if info == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.daily = info.Usage.CurrentDay.CreditsUsed
+	m.monthly = info.Usage.CurrentMonth.CreditsUsed
+	if !info.Plan.CreditLimitDailyResetTimestamp.IsZero() {
+		m.dailyReset = info.Plan.CreditLimitDailyResetTimestamp
+	}
+	if !info.Plan.CreditLimitMonthlyResetTimestamp.IsZero() {
+		m.monthlyReset = info.Plan.CreditLimitMonthlyResetTimestamp
+	}
+}
+
+// Record adds credits spent on a request to endpoint.
+func (m *CreditMeter) Record(endpoint string, credits int) {
+	if credits <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.roll()
+	m.byEndpoint[endpoint] += credits
+	m.daily += credits
+	m.monthly += credits
+}
+
+// Check returns a *BudgetError if the daily or monthly budget has been used up.
+// Credits held by requests in flight count as used.
+func (m *CreditMeter) Check() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.roll()
+	return m.check()
+}
+
+// check compares the recorded and held credits to the budgets. Callers must hold m.mu.
+func (m *CreditMeter) check() error {
+	daily, monthly := m.daily+m.reserved, m.monthly+m.reserved
+
+	if m.dailyBudget > 0 && daily >= m.dailyBudget {
+		return &BudgetError{Period: "daily", Used: daily, Limit: m.dailyBudget, Reset: m.dailyReset}
+	}
+	if m.monthlyBudget > 0 && monthly >= m.monthlyBudget {
+		return &BudgetError{Period: "monthly", Used: monthly, Limit: m.monthlyBudget, Reset: m.monthlyReset}
+	}
+	return nil
+}
+
+// reserve checks the budgets and, if they allow a request, holds one credit for it until
+// release. Record the request's cost before releasing its credit.
+func (m *CreditMeter) reserve() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.roll()
+	if err := m.check(); err != nil {
+		return err
+	}
+	m.reserved++
+	return nil
+}
+
+// release frees a credit held by reserve.
+func (m *CreditMeter) release() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.reserved--
+}
+
+// Usage returns a snapshot of the recorded credits.
+func (m *CreditMeter) Usage() CreditUsage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.roll()
+
+	byEndpoint := make(map[string]int, len(m.byEndpoint))
+	for endpoint, credits := range m.byEndpoint {
+		byEndpoint[endpoint] = credits
+	}
+
+	return CreditUsage{
+		Daily:         m.daily,
+		Monthly:       m.monthly,
+		DailyBudget:   m.dailyBudget,
+		MonthlyBudget: m.monthlyBudget,
+		DailyReset:    m.dailyReset,
+		MonthlyReset:  m.monthlyReset,
+		ByEndpoint:    byEndpoint,
+	}
+}
+
+// Credits returns the client's credit meter.
+func (c *Client) Credits() *CreditMeter {
+	return c.credits
+}
+
+// SyncCredits fetches key usage with GetKeyInfo, which costs no credits, and seeds the meter with it.
+// With a key pool, the meter of every pooled key is seeded instead.
+func (c *Client) SyncCredits(ctx context.Context) error {
+	if c.keys != nil {
+		return c.keys.sync(ctx, c)
+	}
+	_, err := c.GetKeyInfo(ctx)
+	return err
+}
+
+// isCreditFree reports whether an endpoint is exempt from budget checks.
+func isCreditFree(endpoint string) bool {
+	return endpoint == "/v1/key/info"
+}
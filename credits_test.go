@@ -0,0 +1,189 @@
+package coinmarketcap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestCreditMeterBudget(t *testing.T) {
+	meter := NewCreditMeter(10, 0)
+
+	meter.Record("/v1/cryptocurrency/listings/latest", 4)
+	meter.Record("/v1/cryptocurrency/listings/latest", 4)
+	meter.Record("/v1/cryptocurrency/map", 1)
+
+	if err := meter.Check(); err != nil {
+		t.Fatalf("unexpected error under budget: %v", err)
+	}
+
+	meter.Record("/v1/cryptocurrency/map", 1)
+
+	err := meter.Check()
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected ErrBudgetExceeded, got %v", err)
+	}
+
+	var budgetErr *BudgetError
+	if !errors.As(err, &budgetErr) || budgetErr.Period != "daily" || budgetErr.Used != 10 {
+		t.Errorf("expected daily budget error with 10 used, got %+v", budgetErr)
+	}
+
+	usage := meter.Usage()
+	if usage.ByEndpoint["/v1/cryptocurrency/listings/latest"] != 8 {
+		t.Errorf("expected 8 credits for listings, got %d", usage.ByEndpoint["/v1/cryptocurrency/listings/latest"])
+	}
+	if usage.Monthly != 10 {
+		t.Errorf("expected 10 monthly credits, got %d", usage.Monthly)
+	}
+}
+
+func TestCreditMeterRollover(t *testing.T) {
+	now := time.Date(2024, 3, 15, 23, 0, 0, 0, time.UTC)
+	meter := NewCreditMeter(5, 100)
+	meter.now = func() time.Time { return now }
+
+	var info KeyInfo
+	info.Usage.CurrentDay.CreditsUsed = 5
+	info.Usage.CurrentMonth.CreditsUsed = 50
+	info.Plan.CreditLimitDailyResetTimestamp = time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)
+	info.Plan.CreditLimitMonthlyResetTimestamp = time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)
+	meter.Seed(&info)
+
+	if !errors.Is(meter.Check(), ErrBudgetExceeded) {
+		t.Fatal("expected daily budget to be exhausted after seeding")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if err := meter.Check(); err != nil {
+		t.Fatalf("expected daily budget to reset, got %v", err)
+	}
+
+	usage := meter.Usage()
+	if usage.Daily != 0 || usage.Monthly != 50 {
+		t.Errorf("expected daily=0 monthly=50, got daily=%d monthly=%d", usage.Daily, usage.Monthly)
+	}
+	if !usage.DailyReset.Equal(time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected next daily reset on 2024-03-17, got %v", usage.DailyReset)
+	}
+}
+
+func TestClientCreditBudget(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{"data": [], "status": {"error_code": 0, "credit_count": 2}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1000)),
+		WithCreditBudget(4, 0),
+	)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetCryptocurrencyMap(ctx, nil); err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i+1, err)
+		}
+	}
+
+	_, err := client.GetCryptocurrencyMap(ctx, nil)
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected ErrBudgetExceeded, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected the refused request not to reach the server, got %d calls", calls)
+	}
+
+	if used := client.Credits().Usage().ByEndpoint["/v1/cryptocurrency/map"]; used != 4 {
+		t.Errorf("expected 4 credits recorded for map, got %d", used)
+	}
+}
+
+func TestGetKeyInfoSeedsCredits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"data": {
+				"plan": {"credit_limit_daily": 333, "credit_limit_monthly": 10000},
+				"usage": {
+					"current_day": {"credits_used": 120, "credits_left": 213},
+					"current_month": {"credits_used": 4200, "credits_left": 5800}
+				}
+			},
+			"status": {"error_code": 0, "credit_count": 0}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1000)),
+		WithCreditBudget(100, 0),
+	)
+
+	// Key info is exempt from the budget even when it is exhausted.
+	for i := 0; i < 2; i++ {
+		if err := client.SyncCredits(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	usage := client.Credits().Usage()
+	if usage.Daily != 120 || usage.Monthly != 4200 {
+		t.Errorf("expected daily=120 monthly=4200, got daily=%d monthly=%d", usage.Daily, usage.Monthly)
+	}
+}
+
+func TestClientCreditBudgetInFlight(t *testing.T) {
+	started, finish := make(chan struct{}), make(chan struct{})
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+			<-finish
+		}
+		w.Write([]byte(`{"data": [], "status": {"error_code": 0, "credit_count": 1}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1000)),
+		WithCreditBudget(1, 0),
+	)
+
+	ctx := context.Background()
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GetCryptocurrencyMap(ctx, nil)
+		done <- err
+	}()
+	<-started
+
+	_, err := client.GetCryptocurrencyMap(ctx, nil)
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("expected the in-flight request to hold the last credit, got %v", err)
+	}
+
+	close(finish)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+	if usage := client.Credits().Usage(); usage.Daily != 1 {
+		t.Errorf("expected the held credit to be replaced by the recorded one, got %d", usage.Daily)
+	}
+	if err := client.Credits().Check(); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("expected the budget to be used up, got %v", err)
+	}
+}
@@ -1,502 +1,248 @@
 package coinmarketcap
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
+	"net/url"
 )
 
 type CryptocurrencyMapOptions struct {
-	ListingStatus *ListingStatus
-	Start         *int
-	Limit         *int
-	Sort          *string
-	Symbol        []string
-	Aux           []string
+	ListingStatus *ListingStatus `query:"listing_status"`
+	Start         *int           `query:"start"`
+	Limit         *int           `query:"limit"`
+	Sort          *string        `query:"sort"`
+	Symbol        []string       `query:"symbol"`
+	Aux           []string       `query:"aux"`
 }
 
 func (c *Client) GetCryptocurrencyMap(ctx context.Context, opts *CryptocurrencyMapOptions) (*APIResponse[[]CryptocurrencyMap], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		if opts.ListingStatus != nil {
-			params.Add("listing_status", string(*opts.ListingStatus))
-		}
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		if opts.Sort != nil {
-			params.Add("sort", *opts.Sort)
-		}
-		params.AddStringSlice("symbol", opts.Symbol)
-		params.AddStringSlice("aux", opts.Aux)
-	}
-
 	return get[[]CryptocurrencyMap](c, ctx, "/v1/cryptocurrency/map", &RequestOptions[[]CryptocurrencyMap]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type CryptocurrencyInfoOptions struct {
-	ID      []int
-	Slug    []string
-	Symbol  []string
-	Address []string
-	Aux     []string
+	ID      []int    `query:"id"`
+	Slug    []string `query:"slug"`
+	Symbol  []string `query:"symbol"`
+	Address []string `query:"address"`
+	Aux     []string `query:"aux"`
 }
 
 func (c *Client) GetCryptocurrencyInfo(ctx context.Context, opts *CryptocurrencyInfoOptions) (*APIResponse[map[string]CryptocurrencyInfo], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddIntSlice("id", opts.ID)
-		params.AddStringSlice("slug", opts.Slug)
-		params.AddStringSlice("symbol", opts.Symbol)
-		params.AddStringSlice("address", opts.Address)
-		params.AddStringSlice("aux", opts.Aux)
-	}
-
-	return get[map[string]CryptocurrencyInfo](c, ctx, "/v2/cryptocurrency/info", &RequestOptions[map[string]CryptocurrencyInfo]{
-		QueryParams: params.Build(),
+	return getBatched(c, ctx, encodeQuery(opts), func(ctx context.Context, query url.Values) (*APIResponse[map[string]CryptocurrencyInfo], error) {
+		return get[map[string]CryptocurrencyInfo](c, ctx, "/v2/cryptocurrency/info", &RequestOptions[map[string]CryptocurrencyInfo]{
+			QueryParams: query,
+		})
 	})
 }
 
 type CryptocurrencyListingsOptions struct {
-	Start                *int
-	Limit                *int
-	PriceMin             *float64
-	PriceMax             *float64
-	MarketCapMin         *float64
-	MarketCapMax         *float64
-	Volume24hMin         *float64
-	Volume24hMax         *float64
-	CirculatingSupplyMin *float64
-	CirculatingSupplyMax *float64
-	PercentChange24hMin  *float64
-	PercentChange24hMax  *float64
-	Convert              []string
-	ConvertID            []int
-	Sort                 *ListingSort
-	SortDir              *SortDirection
-	CryptocurrencyType   *CryptocurrencyType
-	Tag                  *string
-	Aux                  []string
+	Start                *int                `query:"start"`
+	Limit                *int                `query:"limit"`
+	PriceMin             *float64            `query:"price_min"`
+	PriceMax             *float64            `query:"price_max"`
+	MarketCapMin         *float64            `query:"market_cap_min"`
+	MarketCapMax         *float64            `query:"market_cap_max"`
+	Volume24hMin         *float64            `query:"volume_24h_min"`
+	Volume24hMax         *float64            `query:"volume_24h_max"`
+	CirculatingSupplyMin *float64            `query:"circulating_supply_min"`
+	CirculatingSupplyMax *float64            `query:"circulating_supply_max"`
+	PercentChange24hMin  *float64            `query:"percent_change_24h_min"`
+	PercentChange24hMax  *float64            `query:"percent_change_24h_max"`
+	Convert              []string            `query:"convert"`
+	ConvertID            []int               `query:"convert_id"`
+	Sort                 *ListingSort        `query:"sort"`
+	SortDir              *SortDirection      `query:"sort_dir"`
+	CryptocurrencyType   *CryptocurrencyType `query:"cryptocurrency_type"`
+	Tag                  *string             `query:"tag"`
+	Aux                  []string            `query:"aux"`
 }
 
 func (c *Client) GetCryptocurrencyListingsLatest(ctx context.Context, opts *CryptocurrencyListingsOptions) (*APIResponse[[]CryptocurrencyListing], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		params.AddFloat("price_min", opts.PriceMin)
-		params.AddFloat("price_max", opts.PriceMax)
-		params.AddFloat("market_cap_min", opts.MarketCapMin)
-		params.AddFloat("market_cap_max", opts.MarketCapMax)
-		params.AddFloat("volume_24h_min", opts.Volume24hMin)
-		params.AddFloat("volume_24h_max", opts.Volume24hMax)
-		params.AddFloat("circulating_supply_min", opts.CirculatingSupplyMin)
-		params.AddFloat("circulating_supply_max", opts.CirculatingSupplyMax)
-		params.AddFloat("percent_change_24h_min", opts.PercentChange24hMin)
-		params.AddFloat("percent_change_24h_max", opts.PercentChange24hMax)
-		params.AddStringSlice("convert", opts.Convert)
-		params.AddIntSlice("convert_id", opts.ConvertID)
-		if opts.Sort != nil {
-			params.Add("sort", string(*opts.Sort))
-		}
-		if opts.SortDir != nil {
-			params.Add("sort_dir", string(*opts.SortDir))
-		}
-		if opts.CryptocurrencyType != nil {
-			params.Add("cryptocurrency_type", string(*opts.CryptocurrencyType))
-		}
-		if opts.Tag != nil {
-			params.Add("tag", *opts.Tag)
-		}
-		params.AddStringSlice("aux", opts.Aux)
-	}
-
 	return get[[]CryptocurrencyListing](c, ctx, "/v1/cryptocurrency/listings/latest", &RequestOptions[[]CryptocurrencyListing]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type CryptocurrencyListingsHistoricalOptions struct {
-	Date string
+	Date string `query:"date"`
 	CryptocurrencyListingsOptions
 }
 
 func (c *Client) GetCryptocurrencyListingsHistorical(ctx context.Context, opts *CryptocurrencyListingsHistoricalOptions) (*APIResponse[[]CryptocurrencyListing], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.Add("date", opts.Date)
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		params.AddFloat("price_min", opts.PriceMin)
-		params.AddFloat("price_max", opts.PriceMax)
-		params.AddFloat("market_cap_min", opts.MarketCapMin)
-		params.AddFloat("market_cap_max", opts.MarketCapMax)
-		params.AddFloat("volume_24h_min", opts.Volume24hMin)
-		params.AddFloat("volume_24h_max", opts.Volume24hMax)
-		params.AddFloat("circulating_supply_min", opts.CirculatingSupplyMin)
-		params.AddFloat("circulating_supply_max", opts.CirculatingSupplyMax)
-		params.AddFloat("percent_change_24h_min", opts.PercentChange24hMin)
-		params.AddFloat("percent_change_24h_max", opts.PercentChange24hMax)
-		params.AddStringSlice("convert", opts.Convert)
-		params.AddIntSlice("convert_id", opts.ConvertID)
-		if opts.Sort != nil {
-			params.Add("sort", string(*opts.Sort))
-		}
-		if opts.SortDir != nil {
-			params.Add("sort_dir", string(*opts.SortDir))
-		}
-		if opts.CryptocurrencyType != nil {
-			params.Add("cryptocurrency_type", string(*opts.CryptocurrencyType))
-		}
-		if opts.Tag != nil {
-			params.Add("tag", *opts.Tag)
-		}
-		params.AddStringSlice("aux", opts.Aux)
-	}
-
 	return get[[]CryptocurrencyListing](c, ctx, "/v1/cryptocurrency/listings/historical", &RequestOptions[[]CryptocurrencyListing]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type CryptocurrencyListingsNewOptions struct {
-	Start     *int
-	Limit     *int
-	Convert   []string
-	ConvertID []int
-	SortDir   *SortDirection
+	Start     *int           `query:"start"`
+	Limit     *int           `query:"limit"`
+	Convert   []string       `query:"convert"`
+	ConvertID []int          `query:"convert_id"`
+	SortDir   *SortDirection `query:"sort_dir"`
 }
 
 func (c *Client) GetCryptocurrencyListingsNew(ctx context.Context, opts *CryptocurrencyListingsNewOptions) (*APIResponse[[]CryptocurrencyListing], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		params.AddStringSlice("convert", opts.Convert)
-		params.AddIntSlice("convert_id", opts.ConvertID)
-		if opts.SortDir != nil {
-			params.Add("sort_dir", string(*opts.SortDir))
-		}
-	}
-
 	return get[[]CryptocurrencyListing](c, ctx, "/v1/cryptocurrency/listings/new", &RequestOptions[[]CryptocurrencyListing]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type CryptocurrencyQuotesOptions struct {
-	ID          []int
-	Slug        []string
-	Symbol      []string
-	Convert     []string
-	ConvertID   []int
-	Aux         []string
-	SkipInvalid *bool
+	ID          []int    `query:"id"`
+	Slug        []string `query:"slug"`
+	Symbol      []string `query:"symbol"`
+	Convert     []string `query:"convert"`
+	ConvertID   []int    `query:"convert_id"`
+	Aux         []string `query:"aux"`
+	SkipInvalid *bool    `query:"skip_invalid"`
 }
 
 func (c *Client) GetCryptocurrencyQuotesLatest(ctx context.Context, opts *CryptocurrencyQuotesOptions) (*APIResponse[map[string][]CryptocurrencyQuote], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddIntSlice("id", opts.ID)
-		params.AddStringSlice("slug", opts.Slug)
-		params.AddStringSlice("symbol", opts.Symbol)
-		params.AddStringSlice("convert", opts.Convert)
-		params.AddIntSlice("convert_id", opts.ConvertID)
-		params.AddStringSlice("aux", opts.Aux)
-		params.AddBool("skip_invalid", opts.SkipInvalid)
-	}
-
-	return getCryptocurrencyQuotes(c, ctx, "/v2/cryptocurrency/quotes/latest", &RequestOptions[any]{
-		QueryParams: params.Build(),
+	return getBatched(c, ctx, encodeQuery(opts), func(ctx context.Context, query url.Values) (*APIResponse[map[string][]CryptocurrencyQuote], error) {
+		return getKeyedList[CryptocurrencyQuote](c, ctx, "/v2/cryptocurrency/quotes/latest", &RequestOptions[any]{
+			QueryParams: query,
+		})
 	})
 }
 
 type CryptocurrencyQuotesHistoricalOptions struct {
-	ID        []int
-	Symbol    []string
-	TimeStart *string
-	TimeEnd   *string
-	Count     *int
-	Interval  *Interval
-	Convert   []string
-	ConvertID []int
-	Aux       []string
-}
-
-func (c *Client) GetCryptocurrencyQuotesHistorical(ctx context.Context, opts *CryptocurrencyQuotesHistoricalOptions) (*APIResponse[map[string][]HistoricalQuote], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddIntSlice("id", opts.ID)
-		params.AddStringSlice("symbol", opts.Symbol)
-		if opts.TimeStart != nil {
-			params.Add("time_start", *opts.TimeStart)
-		}
-		if opts.TimeEnd != nil {
-			params.Add("time_end", *opts.TimeEnd)
-		}
-		params.AddInt("count", opts.Count)
-		if opts.Interval != nil {
-			params.Add("interval", string(*opts.Interval))
-		}
-		params.AddStringSlice("convert", opts.Convert)
-		params.AddIntSlice("convert_id", opts.ConvertID)
-		params.AddStringSlice("aux", opts.Aux)
-	}
-
-	return get[map[string][]HistoricalQuote](c, ctx, "/v2/cryptocurrency/quotes/historical", &RequestOptions[map[string][]HistoricalQuote]{
-		QueryParams: params.Build(),
+	ID        []int     `query:"id"`
+	Symbol    []string  `query:"symbol"`
+	TimeStart *string   `query:"time_start"`
+	TimeEnd   *string   `query:"time_end"`
+	Count     *int      `query:"count"`
+	Interval  *Interval `query:"interval"`
+	Convert   []string  `query:"convert"`
+	ConvertID []int     `query:"convert_id"`
+	Aux       []string  `query:"aux"`
+}
+
+func (c *Client) GetCryptocurrencyQuotesHistorical(ctx context.Context, opts *CryptocurrencyQuotesHistoricalOptions) (*APIResponse[map[string][]CryptocurrencyQuotesHistorical], error) {
+	return getKeyedList[CryptocurrencyQuotesHistorical](c, ctx, "/v2/cryptocurrency/quotes/historical", &RequestOptions[any]{
+		QueryParams: encodeQuery(opts),
 	})
 }
 
-func (c *Client) GetCryptocurrencyQuotesHistoricalV3(ctx context.Context, opts *CryptocurrencyQuotesHistoricalOptions) (*APIResponse[map[string][]HistoricalQuote], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddIntSlice("id", opts.ID)
-		params.AddStringSlice("symbol", opts.Symbol)
-		if opts.TimeStart != nil {
-			params.Add("time_start", *opts.TimeStart)
-		}
-		if opts.TimeEnd != nil {
-			params.Add("time_end", *opts.TimeEnd)
-		}
-		params.AddInt("count", opts.Count)
-		if opts.Interval != nil {
-			params.Add("interval", string(*opts.Interval))
-		}
-		params.AddStringSlice("convert", opts.Convert)
-		params.AddIntSlice("convert_id", opts.ConvertID)
-		params.AddStringSlice("aux", opts.Aux)
-	}
-
-	return get[map[string][]HistoricalQuote](c, ctx, "/v3/cryptocurrency/quotes/historical", &RequestOptions[map[string][]HistoricalQuote]{
-		QueryParams: params.Build(),
+func (c *Client) GetCryptocurrencyQuotesHistoricalV3(ctx context.Context, opts *CryptocurrencyQuotesHistoricalOptions) (*APIResponse[map[string][]CryptocurrencyQuotesHistorical], error) {
+	return getKeyedList[CryptocurrencyQuotesHistorical](c, ctx, "/v3/cryptocurrency/quotes/historical", &RequestOptions[any]{
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type CryptocurrencyMarketPairsOptions struct {
-	ID            *int
-	Slug          *string
-	Symbol        *string
-	Start         *int
-	Limit         *int
-	Aux           []string
-	MatchedID     []int
-	MatchedSymbol []string
-	Category      *PairCategory
-	FeeType       *FeeType
-	Convert       []string
-	ConvertID     []int
+	ID            *int          `query:"id"`
+	Slug          *string       `query:"slug"`
+	Symbol        *string       `query:"symbol"`
+	Start         *int          `query:"start"`
+	Limit         *int          `query:"limit"`
+	Aux           []string      `query:"aux"`
+	MatchedID     []int         `query:"matched_id"`
+	MatchedSymbol []string      `query:"matched_symbol"`
+	Category      *PairCategory `query:"category"`
+	FeeType       *FeeType      `query:"fee_type"`
+	Convert       []string      `query:"convert"`
+	ConvertID     []int         `query:"convert_id"`
 }
 
 func (c *Client) GetCryptocurrencyMarketPairsLatest(ctx context.Context, opts *CryptocurrencyMarketPairsOptions) (*APIResponse[map[string][]MarketPair], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddInt("id", opts.ID)
-		if opts.Slug != nil {
-			params.Add("slug", *opts.Slug)
-		}
-		if opts.Symbol != nil {
-			params.Add("symbol", *opts.Symbol)
-		}
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		params.AddStringSlice("aux", opts.Aux)
-		params.AddIntSlice("matched_id", opts.MatchedID)
-		params.AddStringSlice("matched_symbol", opts.MatchedSymbol)
-		if opts.Category != nil {
-			params.Add("category", string(*opts.Category))
-		}
-		if opts.FeeType != nil {
-			params.Add("fee_type", string(*opts.FeeType))
-		}
-		params.AddStringSlice("convert", opts.Convert)
-		params.AddIntSlice("convert_id", opts.ConvertID)
-	}
-
 	return get[map[string][]MarketPair](c, ctx, "/v2/cryptocurrency/market-pairs/latest", &RequestOptions[map[string][]MarketPair]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type CryptocurrencyOHLCVOptions struct {
-	ID          []int
-	Symbol      []string
-	Convert     []string
-	ConvertID   []int
-	SkipInvalid *bool
+	ID          []int    `query:"id"`
+	Symbol      []string `query:"symbol"`
+	Convert     []string `query:"convert"`
+	ConvertID   []int    `query:"convert_id"`
+	SkipInvalid *bool    `query:"skip_invalid"`
 }
 
 func (c *Client) GetCryptocurrencyOHLCVLatest(ctx context.Context, opts *CryptocurrencyOHLCVOptions) (*APIResponse[map[string]OHLCV], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddIntSlice("id", opts.ID)
-		params.AddStringSlice("symbol", opts.Symbol)
-		params.AddStringSlice("convert", opts.Convert)
-		params.AddIntSlice("convert_id", opts.ConvertID)
-		params.AddBool("skip_invalid", opts.SkipInvalid)
-	}
-
-	return get[map[string]OHLCV](c, ctx, "/v2/cryptocurrency/ohlcv/latest", &RequestOptions[map[string]OHLCV]{
-		QueryParams: params.Build(),
+	return getBatched(c, ctx, encodeQuery(opts), func(ctx context.Context, query url.Values) (*APIResponse[map[string]OHLCV], error) {
+		return get[map[string]OHLCV](c, ctx, "/v2/cryptocurrency/ohlcv/latest", &RequestOptions[map[string]OHLCV]{
+			QueryParams: query,
+		})
 	})
 }
 
 type CryptocurrencyOHLCVHistoricalOptions struct {
-	ID         []int
-	Slug       []string
-	Symbol     []string
-	TimePeriod *string
-	TimeStart  *string
-	TimeEnd    *string
-	Count      *int
-	Interval   *Interval
-	Convert    []string
-	ConvertID  []int
+	ID         []int     `query:"id"`
+	Slug       []string  `query:"slug"`
+	Symbol     []string  `query:"symbol"`
+	TimePeriod *Interval `query:"time_period"` // IntervalDaily or IntervalHourly
+	TimeStart  *string   `query:"time_start"`
+	TimeEnd    *string   `query:"time_end"`
+	Count      *int      `query:"count"`
+	Interval   *Interval `query:"interval"`
+	Convert    []string  `query:"convert"`
+	ConvertID  []int     `query:"convert_id"`
 }
 
 func (c *Client) GetCryptocurrencyOHLCVHistorical(ctx context.Context, opts *CryptocurrencyOHLCVHistoricalOptions) (*APIResponse[map[string][]OHLCV], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddIntSlice("id", opts.ID)
-		params.AddStringSlice("slug", opts.Slug)
-		params.AddStringSlice("symbol", opts.Symbol)
-		if opts.TimePeriod != nil {
-			params.Add("time_period", *opts.TimePeriod)
-		}
-		if opts.TimeStart != nil {
-			params.Add("time_start", *opts.TimeStart)
-		}
-		if opts.TimeEnd != nil {
-			params.Add("time_end", *opts.TimeEnd)
-		}
-		params.AddInt("count", opts.Count)
-		if opts.Interval != nil {
-			params.Add("interval", string(*opts.Interval))
-		}
-		params.AddStringSlice("convert", opts.Convert)
-		params.AddIntSlice("convert_id", opts.ConvertID)
-	}
-
 	return get[map[string][]OHLCV](c, ctx, "/v2/cryptocurrency/ohlcv/historical", &RequestOptions[map[string][]OHLCV]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type CryptocurrencyPricePerformanceStatsOptions struct {
-	ID         []int
-	Slug       []string
-	Symbol     []string
-	TimePeriod *TimePeriod
-	Convert    []string
-	ConvertID  []int
+	ID         []int       `query:"id"`
+	Slug       []string    `query:"slug"`
+	Symbol     []string    `query:"symbol"`
+	TimePeriod *TimePeriod `query:"time_period"`
+	Convert    []string    `query:"convert"`
+	ConvertID  []int       `query:"convert_id"`
 }
 
 func (c *Client) GetCryptocurrencyPricePerformanceStats(ctx context.Context, opts *CryptocurrencyPricePerformanceStatsOptions) (*APIResponse[map[string]PricePerformanceStats], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddIntSlice("id", opts.ID)
-		params.AddStringSlice("slug", opts.Slug)
-		params.AddStringSlice("symbol", opts.Symbol)
-		if opts.TimePeriod != nil {
-			params.Add("time_period", string(*opts.TimePeriod))
-		}
-		params.AddStringSlice("convert", opts.Convert)
-		params.AddIntSlice("convert_id", opts.ConvertID)
-	}
-
 	return get[map[string]PricePerformanceStats](c, ctx, "/v2/cryptocurrency/price-performance-stats/latest", &RequestOptions[map[string]PricePerformanceStats]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type CryptocurrencyCategoriesOptions struct {
-	Start  *int
-	Limit  *int
-	ID     []int
-	Slug   []string
-	Symbol []string
+	Start  *int     `query:"start"`
+	Limit  *int     `query:"limit"`
+	ID     []int    `query:"id"`
+	Slug   []string `query:"slug"`
+	Symbol []string `query:"symbol"`
 }
 
 func (c *Client) GetCryptocurrencyCategories(ctx context.Context, opts *CryptocurrencyCategoriesOptions) (*APIResponse[[]Category], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		params.AddIntSlice("id", opts.ID)
-		params.AddStringSlice("slug", opts.Slug)
-		params.AddStringSlice("symbol", opts.Symbol)
-	}
-
 	return get[[]Category](c, ctx, "/v1/cryptocurrency/categories", &RequestOptions[[]Category]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type CryptocurrencyCategoryOptions struct {
-	ID      string
-	Start   *int
-	Limit   *int
-	Convert []string
+	ID      string   `query:"id"`
+	Start   *int     `query:"start"`
+	Limit   *int     `query:"limit"`
+	Convert []string `query:"convert"`
 }
 
 func (c *Client) GetCryptocurrencyCategory(ctx context.Context, opts *CryptocurrencyCategoryOptions) (*APIResponse[CategoryDetail], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.Add("id", opts.ID)
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		params.AddStringSlice("convert", opts.Convert)
-	}
-
 	return get[CategoryDetail](c, ctx, "/v1/cryptocurrency/category", &RequestOptions[CategoryDetail]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type CryptocurrencyAirdropsOptions struct {
-	Start  *int
-	Limit  *int
-	Status *AirdropStatus
-	ID     *int
-	Slug   *string
-	Symbol *string
+	Start  *int           `query:"start"`
+	Limit  *int           `query:"limit"`
+	Status *AirdropStatus `query:"status"`
+	ID     *int           `query:"id"`
+	Slug   *string        `query:"slug"`
+	Symbol *string        `query:"symbol"`
 }
 
 func (c *Client) GetCryptocurrencyAirdrops(ctx context.Context, opts *CryptocurrencyAirdropsOptions) (*APIResponse[[]Airdrop], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		if opts.Status != nil {
-			params.Add("status", string(*opts.Status))
-		}
-		params.AddInt("id", opts.ID)
-		if opts.Slug != nil {
-			params.Add("slug", *opts.Slug)
-		}
-		if opts.Symbol != nil {
-			params.Add("symbol", *opts.Symbol)
-		}
-	}
-
 	return get[[]Airdrop](c, ctx, "/v1/cryptocurrency/airdrops", &RequestOptions[[]Airdrop]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
@@ -509,147 +255,78 @@ func (c *Client) GetCryptocurrencyAirdrop(ctx context.Context, id string) (*APIR
 }
 
 type CryptocurrencyTrendingOptions struct {
-	Start      *int
-	Limit      *int
-	TimePeriod *TimePeriod
-	Convert    []string
+	Start      *int        `query:"start"`
+	Limit      *int        `query:"limit"`
+	TimePeriod *TimePeriod `query:"time_period"`
+	Convert    []string    `query:"convert"`
 }
 
 func (c *Client) GetCryptocurrencyTrendingLatest(ctx context.Context, opts *CryptocurrencyTrendingOptions) (*APIResponse[[]Trending], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		if opts.TimePeriod != nil {
-			params.Add("time_period", string(*opts.TimePeriod))
-		}
-		params.AddStringSlice("convert", opts.Convert)
-	}
-
 	return get[[]Trending](c, ctx, "/v1/cryptocurrency/trending/latest", &RequestOptions[[]Trending]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 func (c *Client) GetCryptocurrencyTrendingMostVisited(ctx context.Context, opts *CryptocurrencyTrendingOptions) (*APIResponse[[]Trending], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		if opts.TimePeriod != nil {
-			params.Add("time_period", string(*opts.TimePeriod))
-		}
-		params.AddStringSlice("convert", opts.Convert)
-	}
-
 	return get[[]Trending](c, ctx, "/v1/cryptocurrency/trending/most-visited", &RequestOptions[[]Trending]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type CryptocurrencyGainersLosersOptions struct {
-	Start      *int
-	Limit      *int
-	TimePeriod *TimePeriod
-	Convert    []string
-	Sort       *string
-	SortDir    *SortDirection
+	Start      *int           `query:"start"`
+	Limit      *int           `query:"limit"`
+	TimePeriod *TimePeriod    `query:"time_period"`
+	Convert    []string       `query:"convert"`
+	Sort       *string        `query:"sort"`
+	SortDir    *SortDirection `query:"sort_dir"`
 }
 
 func (c *Client) GetCryptocurrencyTrendingGainersLosers(ctx context.Context, opts *CryptocurrencyGainersLosersOptions) (*APIResponse[[]Trending], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		if opts.TimePeriod != nil {
-			params.Add("time_period", string(*opts.TimePeriod))
-		}
-		params.AddStringSlice("convert", opts.Convert)
-		if opts.Sort != nil {
-			params.Add("sort", *opts.Sort)
-		}
-		if opts.SortDir != nil {
-			params.Add("sort_dir", string(*opts.SortDir))
-		}
-	}
-
 	return get[[]Trending](c, ctx, "/v1/cryptocurrency/trending/gainers-losers", &RequestOptions[[]Trending]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
-// getCryptocurrencyQuotes handles the inconsistent CMC API response format
-// Symbol queries return arrays, ID queries return single objects
-func getCryptocurrencyQuotes(c *Client, ctx context.Context, endpoint string, opts *RequestOptions[any]) (*APIResponse[map[string][]CryptocurrencyQuote], error) {
-	var reqOpts *RequestOptions[any]
-	if opts != nil {
-		reqOpts = &RequestOptions[any]{
-			QueryParams: opts.QueryParams,
-			Headers:     opts.Headers,
-		}
-	}
-
-	resp, err := c.doRequest(ctx, endpoint, reqOpts)
+// getKeyedList handles the inconsistent CMC API response format of the v2 and v3
+// cryptocurrency endpoints: Symbol queries return arrays, ID queries return single objects.
+// Single objects are returned as one-element lists.
+func getKeyedList[T any](c *Client, ctx context.Context, endpoint string, opts *RequestOptions[any]) (*APIResponse[map[string][]T], error) {
+	resp, err := get[map[string]oneOrMany[T]](c, ctx, endpoint, &RequestOptions[map[string]oneOrMany[T]]{
+		QueryParams: opts.QueryParams,
+		Headers:     opts.Headers,
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := getResponseBody(resp)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+	result := &APIResponse[map[string][]T]{
+		Data:   make(map[string][]T, len(resp.Data)),
+		Status: resp.Status,
 	}
-
-	// First try to parse as the expected array format (symbol queries)
-	var apiResp APIResponse[map[string][]CryptocurrencyQuote]
-	if err := json.Unmarshal(body, &apiResp); err == nil {
-		// Check for API errors
-		if apiResp.Status.ErrorCode != 0 {
-			errorMsg := "API error"
-			if apiResp.Status.ErrorMessage != nil {
-				errorMsg = *apiResp.Status.ErrorMessage
-			}
-			return nil, &APIError{
-				StatusCode: resp.StatusCode,
-				ErrorCode:  apiResp.Status.ErrorCode,
-				Message:    errorMsg,
-			}
-		}
-		return &apiResp, nil
+	for key, list := range resp.Data {
+		result.Data[key] = list
 	}
+	return result, nil
+}
+
+// oneOrMany decodes a JSON array of T, or a single T as a one-element list.
+type oneOrMany[T any] []T
 
-	// If that fails, try to parse as single object format (ID queries)
-	var apiRespSingle APIResponse[map[string]CryptocurrencyQuote]
-	if err := json.Unmarshal(body, &apiRespSingle); err == nil {
-		// Check for API errors
-		if apiRespSingle.Status.ErrorCode != 0 {
-			errorMsg := "API error"
-			if apiRespSingle.Status.ErrorMessage != nil {
-				errorMsg = *apiRespSingle.Status.ErrorMessage
-			}
-			return nil, &APIError{
-				StatusCode: resp.StatusCode,
-				ErrorCode:  apiRespSingle.Status.ErrorCode,
-				Message:    errorMsg,
-			}
-		}
-		
-		// Convert single objects to arrays for consistent API
-		arrayResult := APIResponse[map[string][]CryptocurrencyQuote]{
-			Data:   make(map[string][]CryptocurrencyQuote),
-			Status: apiRespSingle.Status,
-		}
-		
-		for key, singleQuote := range apiRespSingle.Data {
-			arrayResult.Data[key] = []CryptocurrencyQuote{singleQuote}
-		}
-		
-		return &arrayResult, nil
+func (l *oneOrMany[T]) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*l = nil
+		return nil
+	case len(data) > 0 && data[0] == '[':
+		return json.Unmarshal(data, (*[]T)(l))
 	}
 
-	// If both formats fail, return the original unmarshal error
-	return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	var single T
+	if err := json.Unmarshal(data, &single); err != nil {
+		return err
+	}
+	*l = oneOrMany[T]{single}
+	return nil
 }
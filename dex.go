@@ -0,0 +1,124 @@
+package coinmarketcap
+
+import (
+	"context"
+)
+
+type DexNetworksOptions struct {
+	Start   *int           `query:"start"`
+	Limit   *int           `query:"limit"`
+	Sort    *string        `query:"sort"`
+	SortDir *SortDirection `query:"sort_dir"`
+	Aux     []string       `query:"aux"`
+}
+
+func (c *Client) GetDexNetworks(ctx context.Context, opts *DexNetworksOptions) (*APIResponse[[]DexNetwork], error) {
+	return get[[]DexNetwork](c, ctx, "/v4/dex/networks/list", &RequestOptions[[]DexNetwork]{
+		QueryParams: encodeQuery(opts),
+	})
+}
+
+type DexListingsOptions struct {
+	Start     *int           `query:"start"`
+	Limit     *int           `query:"limit"`
+	Sort      *string        `query:"sort"`
+	SortDir   *SortDirection `query:"sort_dir"`
+	Type      *string        `query:"type"`
+	Aux       []string       `query:"aux"`
+	Convert   []string       `query:"convert"`
+	ConvertID []int          `query:"convert_id"`
+}
+
+func (c *Client) GetDexListingsQuotes(ctx context.Context, opts *DexListingsOptions) (*APIResponse[[]DexListing], error) {
+	return get[[]DexListing](c, ctx, "/v4/dex/listings/quotes", &RequestOptions[[]DexListing]{
+		QueryParams: encodeQuery(opts),
+	})
+}
+
+type DexSpotPairsOptions struct {
+	NetworkID                 *int           `query:"network_id"`
+	NetworkSlug               *string        `query:"network_slug"`
+	DexID                     *int           `query:"dex_id"`
+	DexSlug                   *string        `query:"dex_slug"`
+	BaseAssetID               *int           `query:"base_asset_id"`
+	BaseAssetSymbol           *string        `query:"base_asset_symbol"`
+	BaseAssetContractAddress  *string        `query:"base_asset_contract_address"`
+	BaseAssetUCID             *int           `query:"base_asset_ucid"`
+	QuoteAssetID              *int           `query:"quote_asset_id"`
+	QuoteAssetSymbol          *string        `query:"quote_asset_symbol"`
+	QuoteAssetContractAddress *string        `query:"quote_asset_contract_address"`
+	QuoteAssetUCID            *int           `query:"quote_asset_ucid"`
+	ScrollID                  *string        `query:"scroll_id"`
+	Limit                     *int           `query:"limit"`
+	LiquidityMin              *float64       `query:"liquidity_min"`
+	LiquidityMax              *float64       `query:"liquidity_max"`
+	Volume24hMin              *float64       `query:"volume_24h_min"`
+	Volume24hMax              *float64       `query:"volume_24h_max"`
+	Transactions24hMin        *int           `query:"no_of_transactions_24h_min"`
+	Transactions24hMax        *int           `query:"no_of_transactions_24h_max"`
+	PercentChange24hMin       *float64       `query:"percent_change_24h_min"`
+	PercentChange24hMax       *float64       `query:"percent_change_24h_max"`
+	Sort                      *string        `query:"sort"`
+	SortDir                   *SortDirection `query:"sort_dir"`
+	Aux                       []string       `query:"aux"`
+	ReverseOrder              *bool          `query:"reverse_order"`
+	Convert                   []string       `query:"convert"`
+	ConvertID                 []int          `query:"convert_id"`
+}
+
+func (c *Client) GetDexSpotPairsLatest(ctx context.Context, opts *DexSpotPairsOptions) (*APIResponse[[]DexPair], error) {
+	return get[[]DexPair](c, ctx, "/v4/dex/spot-pairs/latest", &RequestOptions[[]DexPair]{
+		QueryParams: encodeQuery(opts),
+	})
+}
+
+type DexPairOptions struct {
+	ContractAddress []string `query:"contract_address"`
+	NetworkID       *int     `query:"network_id"`
+	NetworkSlug     *string  `query:"network_slug"`
+	Aux             []string `query:"aux"`
+	Convert         []string `query:"convert"`
+	ConvertID       []int    `query:"convert_id"`
+	SkipInvalid     *bool    `query:"skip_invalid"`
+	ReverseOrder    *bool    `query:"reverse_order"`
+}
+
+func (c *Client) GetDexPairsQuotesLatest(ctx context.Context, opts *DexPairOptions) (*APIResponse[[]DexPair], error) {
+	return get[[]DexPair](c, ctx, "/v4/dex/pairs/quotes/latest", &RequestOptions[[]DexPair]{
+		QueryParams: encodeQuery(opts),
+	})
+}
+
+func (c *Client) GetDexPairsOHLCVLatest(ctx context.Context, opts *DexPairOptions) (*APIResponse[[]DexPairOHLCV], error) {
+	return get[[]DexPairOHLCV](c, ctx, "/v4/dex/pairs/ohlcv/latest", &RequestOptions[[]DexPairOHLCV]{
+		QueryParams: encodeQuery(opts),
+	})
+}
+
+type DexPairOHLCVHistoricalOptions struct {
+	ContractAddress []string  `query:"contract_address"`
+	NetworkID       *int      `query:"network_id"`
+	NetworkSlug     *string   `query:"network_slug"`
+	TimePeriod      *Interval `query:"time_period"`
+	TimeStart       *string   `query:"time_start"`
+	TimeEnd         *string   `query:"time_end"`
+	Count           *int      `query:"count"`
+	Interval        *Interval `query:"interval"`
+	Aux             []string  `query:"aux"`
+	Convert         []string  `query:"convert"`
+	ConvertID       []int     `query:"convert_id"`
+	SkipInvalid     *bool     `query:"skip_invalid"`
+	ReverseOrder    *bool     `query:"reverse_order"`
+}
+
+func (c *Client) GetDexPairsOHLCVHistorical(ctx context.Context, opts *DexPairOHLCVHistoricalOptions) (*APIResponse[[]DexPairOHLCVHistorical], error) {
+	return get[[]DexPairOHLCVHistorical](c, ctx, "/v4/dex/pairs/ohlcv/historical", &RequestOptions[[]DexPairOHLCVHistorical]{
+		QueryParams: encodeQuery(opts),
+	})
+}
+
+func (c *Client) GetDexPairsTradeLatest(ctx context.Context, opts *DexPairOptions) (*APIResponse[[]DexPairTrades], error) {
+	return get[[]DexPairTrades](c, ctx, "/v4/dex/pairs/trade/latest", &RequestOptions[[]DexPairTrades]{
+		QueryParams: encodeQuery(opts),
+	})
+}
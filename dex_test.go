@@ -0,0 +1,181 @@
+package coinmarketcap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+func TestGetDexSpotPairsLatest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v4/dex/spot-pairs/latest" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("network_slug"); got != "ethereum" {
+			t.Errorf("expected network_slug=ethereum, got %q", got)
+		}
+		if got := r.URL.Query().Get("liquidity_min"); got != "100000" {
+			t.Errorf("expected liquidity_min=100000, got %q", got)
+		}
+
+		w.Write([]byte(`{
+			"data": [{
+				"contract_address": "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",
+				"name": "USDC/WETH",
+				"base_asset_id": "3408",
+				"base_asset_ucid": "3408",
+				"base_asset_name": "USDC",
+				"base_asset_symbol": "USDC",
+				"base_asset_contract_address": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+				"quote_asset_id": "2396",
+				"quote_asset_ucid": "1027",
+				"quote_asset_name": "WETH",
+				"quote_asset_symbol": "WETH",
+				"quote_asset_contract_address": "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+				"dex_id": "1348",
+				"dex_slug": "uniswap-v3",
+				"network_id": "1",
+				"network_slug": "ethereum",
+				"last_updated": "2024-05-30T12:00:00.000Z",
+				"num_transactions_24h": 5120,
+				"24h_no_of_buys": 2800,
+				"24h_no_of_sells": 2320,
+				"24h_buy_volume": 41000000.5,
+				"24h_sell_volume": 39000000.25,
+				"quote": [{
+					"convert_id": "2781",
+					"price": 1.0001,
+					"liquidity": 250000000,
+					"volume_24h": 80000000.75,
+					"percent_change_price_24h": 0.01
+				}]
+			}],
+			"status": {"error_code": 0, "credit_count": 1}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimit(rate.Limit(1000)))
+
+	resp, err := client.GetDexSpotPairsLatest(context.Background(), &DexSpotPairsOptions{
+		NetworkSlug:  String("ethereum"),
+		LiquidityMin: Float64(100000),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Data) != 1 {
+		t.Fatalf("expected 1 pair, got %d", len(resp.Data))
+	}
+
+	pair := resp.Data[0]
+	if base := pair.BaseToken(); base.Symbol != "USDC" || base.ContractAddress != "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48" {
+		t.Errorf("unexpected base token: %+v", base)
+	}
+	if quote := pair.QuoteToken(); quote.Symbol != "WETH" || quote.UCID != "1027" {
+		t.Errorf("unexpected quote token: %+v", quote)
+	}
+	if pair.Buys24h == nil || *pair.Buys24h != 2800 || pair.Sells24h == nil || *pair.Sells24h != 2320 {
+		t.Errorf("unexpected buy/sell counts: %v/%v", pair.Buys24h, pair.Sells24h)
+	}
+	if len(pair.Quote) != 1 || pair.Quote[0].Liquidity == nil || *pair.Quote[0].Liquidity != 250000000 {
+		t.Errorf("unexpected quote: %+v", pair.Quote)
+	}
+}
+
+func TestGetDexPairsTradeLatest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v4/dex/pairs/trade/latest" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("contract_address"); got != "0xpool" {
+			t.Errorf("expected contract_address=0xpool, got %q", got)
+		}
+
+		w.Write([]byte(`{
+			"data": [{
+				"contract_address": "0xpool",
+				"base_asset_symbol": "PEPE",
+				"quote_asset_symbol": "WETH",
+				"trades": [
+					{"date": "2024-05-30T12:00:01.000Z", "type": "buy", "transaction_hash": "0xabc", "quote": [{"convert_id": "2781", "price": 0.0000151, "total": 1250.5}]},
+					{"date": "2024-05-30T12:00:00.000Z", "type": "sell", "transaction_hash": "0xdef", "quote": [{"convert_id": "2781", "price": 0.0000150, "total": 310}]}
+				]
+			}],
+			"status": {"error_code": 0, "credit_count": 1}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimit(rate.Limit(1000)))
+
+	resp, err := client.GetDexPairsTradeLatest(context.Background(), &DexPairOptions{
+		ContractAddress: []string{"0xpool"},
+		NetworkSlug:     String("ethereum"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	trades := resp.Data[0].Trades
+	if len(trades) != 2 || trades[0].Type != "buy" || trades[1].TransactionHash != "0xdef" {
+		t.Errorf("unexpected trades: %+v", trades)
+	}
+	if trades[0].Quote[0].Total == nil || *trades[0].Quote[0].Total != 1250.5 {
+		t.Errorf("unexpected trade total: %+v", trades[0].Quote)
+	}
+}
+
+func TestDexStringStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		code         int
+		body         string
+		wantErr      ErrorCode
+		wantNetworks int
+	}{
+		{
+			name:         "success",
+			code:         http.StatusOK,
+			body:         `{"data": [{"id": 1, "name": "Ethereum", "network_slug": "ethereum"}], "status": {"timestamp": "2024-05-30T12:00:00.000Z", "error_code": "0", "error_message": "SUCCESS", "elapsed": "12", "credit_count": 1}}`,
+			wantNetworks: 1,
+		},
+		{
+			name:    "error",
+			code:    http.StatusBadRequest,
+			body:    `{"status": {"timestamp": "2024-05-30T12:00:00.000Z", "error_code": "400", "error_message": "Invalid value for \"aux\"", "elapsed": "1", "credit_count": 0}}`,
+			wantErr: ErrorCodeBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.code)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL), WithRateLimit(rate.Limit(1000)), WithRetryPolicy(RetryPolicy{}))
+			resp, err := client.GetDexNetworks(context.Background(), nil)
+
+			if tt.wantErr != 0 {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.ErrorCode != tt.wantErr {
+					t.Fatalf("expected error code %d, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(resp.Data) != tt.wantNetworks || resp.Status.Elapsed != 12 || resp.Status.CreditCount != 1 {
+				t.Errorf("unexpected response %+v", resp)
+			}
+		})
+	}
+}
@@ -50,20 +50,18 @@
 //
 // # Error Handling
 //
-// The SDK provides detailed error information through the APIError type:
+// API failures are returned as *APIError with a typed ErrorCode. Use errors.Is with
+// the sentinel errors to check their category:
 //
-//	if err != nil {
-//		if apiErr, ok := err.(*coinmarketcap.APIError); ok {
-//			if apiErr.IsRateLimit() {
-//				// Handle rate limit error
-//				fmt.Printf("Rate limit exceeded: %s\n", apiErr.Error())
-//			} else if apiErr.IsAuthError() {
-//				// Handle authentication error
-//				fmt.Printf("Authentication failed: %s\n", apiErr.Error())
-//			}
-//		}
+//	if errors.Is(err, coinmarketcap.ErrRateLimited) {
+//		// Back off and try again later
+//	} else if errors.Is(err, coinmarketcap.ErrInvalidAPIKey) {
+//		// Check the configured key
 //	}
 //
+// Network, gzip and JSON decoding failures are returned as *TransportError, which
+// records the endpoint, the failed operation and the number of attempts made.
+//
 // # API Coverage
 //
 // The SDK provides complete coverage of CoinMarketCap API endpoints:
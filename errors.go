@@ -0,0 +1,157 @@
+package coinmarketcap
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrorCode is a CoinMarketCap status.error_code value. Zero means success.
+type ErrorCode int
+
+// Error codes documented by CoinMarketCap. Codes below 1000 mirror the HTTP status of
+// generic failures such as invalid parameter values; codes from 1001 concern the API key
+// and its plan.
+const (
+	ErrorCodeBadRequest          ErrorCode = 400  // BAD_REQUEST
+	ErrorCodeUnauthorized        ErrorCode = 401  // UNAUTHORIZED
+	ErrorCodeForbidden           ErrorCode = 403  // FORBIDDEN
+	ErrorCodeNotFound            ErrorCode = 404  // NOT_FOUND
+	ErrorCodeInternalServerError ErrorCode = 500  // INTERNAL_SERVER_ERROR
+	ErrorCodeAPIKeyInvalid       ErrorCode = 1001 // API_KEY_INVALID
+	ErrorCodeAPIKeyMissing       ErrorCode = 1002 // API_KEY_MISSING
+	ErrorCodePlanRequiresPayment ErrorCode = 1003 // API_KEY_PLAN_REQUIRES_PAYMENT
+	ErrorCodePlanPaymentExpired  ErrorCode = 1004 // API_KEY_PLAN_PAYMENT_EXPIRED
+	ErrorCodeAPIKeyRequired      ErrorCode = 1005 // API_KEY_REQUIRED
+	ErrorCodePlanUnauthorized    ErrorCode = 1006 // API_KEY_PLAN_NOT_AUTHORIZED
+	ErrorCodeAPIKeyDisabled      ErrorCode = 1007 // API_KEY_DISABLED
+	ErrorCodeMinuteRateLimit     ErrorCode = 1008 // API_KEY_PLAN_MINUTE_RATE_LIMIT_REACHED
+	ErrorCodeDailyRateLimit      ErrorCode = 1009 // API_KEY_PLAN_DAILY_RATE_LIMIT_REACHED
+	ErrorCodeMonthlyRateLimit    ErrorCode = 1010 // API_KEY_PLAN_MONTHLY_RATE_LIMIT_REACHED
+	ErrorCodeIPRateLimit         ErrorCode = 1011 // IP_RATE_LIMIT_REACHED
+)
+
+var errorCodes = map[ErrorCode]struct{ name, description string }{
+	ErrorCodeBadRequest:          {"BAD_REQUEST", "The request was malformed or a parameter has an invalid value."},
+	ErrorCodeUnauthorized:        {"UNAUTHORIZED", "The request was not authenticated."},
+	ErrorCodeForbidden:           {"FORBIDDEN", "The request is not allowed."},
+	ErrorCodeNotFound:            {"NOT_FOUND", "The endpoint or resource does not exist."},
+	ErrorCodeInternalServerError: {"INTERNAL_SERVER_ERROR", "CoinMarketCap failed to process the request."},
+	ErrorCodeAPIKeyInvalid:       {"API_KEY_INVALID", "This API key is invalid."},
+	ErrorCodeAPIKeyMissing:       {"API_KEY_MISSING", "No API key was sent."},
+	ErrorCodePlanRequiresPayment: {"API_KEY_PLAN_REQUIRES_PAYMENT", "The API key's plan requires a payment."},
+	ErrorCodePlanPaymentExpired:  {"API_KEY_PLAN_PAYMENT_EXPIRED", "The API key's plan payment has expired."},
+	ErrorCodeAPIKeyRequired:      {"API_KEY_REQUIRED", "An API key is required for this call."},
+	ErrorCodePlanUnauthorized:    {"API_KEY_PLAN_NOT_AUTHORIZED", "The API key's plan is not authorized for this endpoint."},
+	ErrorCodeAPIKeyDisabled:      {"API_KEY_DISABLED", "This API key has been disabled."},
+	ErrorCodeMinuteRateLimit:     {"API_KEY_PLAN_MINUTE_RATE_LIMIT_REACHED", "The API key's per-minute rate limit has been reached."},
+	ErrorCodeDailyRateLimit:      {"API_KEY_PLAN_DAILY_RATE_LIMIT_REACHED", "The API key's daily credit limit has been reached."},
+	ErrorCodeMonthlyRateLimit:    {"API_KEY_PLAN_MONTHLY_RATE_LIMIT_REACHED", "The API key's monthly credit limit has been reached."},
+	ErrorCodeIPRateLimit:         {"IP_RATE_LIMIT_REACHED", "The IP rate limit has been reached."},
+}
+
+// String returns the documented name of the code, such as API_KEY_INVALID.
+func (c ErrorCode) String() string {
+	if info, ok := errorCodes[c]; ok {
+		return info.name
+	}
+	if c == 0 {
+		return "OK"
+	}
+	return fmt.Sprintf("ERROR_CODE_%d", int(c))
+}
+
+// Description returns CoinMarketCap's explanation of the code, or an empty string for unknown codes.
+func (c ErrorCode) Description() string {
+	return errorCodes[c].description
+}
+
+// IsRateLimit reports whether the code signals an exhausted rate or credit limit.
+func (c ErrorCode) IsRateLimit() bool {
+	return c == ErrorCodeMinuteRateLimit || c == ErrorCodeDailyRateLimit ||
+		c == ErrorCodeMonthlyRateLimit || c == ErrorCodeIPRateLimit
+}
+
+// IsAuth reports whether the code signals an invalid, missing or disabled API key.
+func (c ErrorCode) IsAuth() bool {
+	return c == ErrorCodeAPIKeyInvalid || c == ErrorCodeAPIKeyMissing ||
+		c == ErrorCodeAPIKeyRequired || c == ErrorCodeAPIKeyDisabled
+}
+
+// IsPayment reports whether the code signals an unpaid or expired plan.
+func (c ErrorCode) IsPayment() bool {
+	return c == ErrorCodePlanRequiresPayment || c == ErrorCodePlanPaymentExpired
+}
+
+// Sentinel errors matched by errors.Is against an *APIError anywhere in the chain:
+//
+//	if errors.Is(err, coinmarketcap.ErrRateLimited) {
+//		// back off
+//	}
+var (
+	// ErrRateLimited matches HTTP 429 and the rate and credit limit codes 1008 to 1011.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrInvalidAPIKey matches HTTP 401 and invalid, missing, required or disabled keys.
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	// ErrPaymentRequired matches HTTP 402 and plans that require or are overdue a payment.
+	ErrPaymentRequired = errors.New("payment required")
+	// ErrPlanUnauthorized matches HTTP 403 and endpoints the key's plan does not include.
+	ErrPlanUnauthorized = errors.New("endpoint not authorized for plan")
+	// ErrNotFound matches HTTP 404 and the NOT_FOUND error code.
+	ErrNotFound = errors.New("not found")
+	// ErrBadRequest matches HTTP 400 and invalid parameter values.
+	ErrBadRequest = errors.New("bad request")
+)
+
+// Is reports whether target is the sentinel error for the API error's category.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.IsRateLimit()
+	case ErrInvalidAPIKey:
+		return e.IsAuthError()
+	case ErrPaymentRequired:
+		return e.IsPaymentRequired()
+	case ErrPlanUnauthorized:
+		return e.ErrorCode == ErrorCodePlanUnauthorized ||
+			(e.ErrorCode == 0 && e.StatusCode == http.StatusForbidden)
+	case ErrNotFound:
+		return e.ErrorCode == ErrorCodeNotFound ||
+			(e.ErrorCode == 0 && e.StatusCode == http.StatusNotFound)
+	case ErrBadRequest:
+		return e.ErrorCode == ErrorCodeBadRequest ||
+			(e.ErrorCode == 0 && e.StatusCode == http.StatusBadRequest)
+	}
+	return false
+}
+
+// Operations reported by TransportError.
+const (
+	OpRequest    = "request"    // sending the request or receiving the response headers
+	OpRead       = "read"       // reading the response body
+	OpDecompress = "decompress" // decoding a gzip response body
+	OpDecode     = "decode"     // unmarshaling the JSON body
+)
+
+// TransportError reports a failure to obtain or decode a response, as opposed to an
+// error the API returned. Err is the underlying network, gzip or JSON error.
+type TransportError struct {
+	Endpoint string
+	Attempts int    // HTTP attempts made; 0 when the body was served from the cache
+	Op       string // OpRequest, OpRead, OpDecompress or OpDecode
+	Err      error
+}
+
+// Error implements the error interface.
+func (e *TransportError) Error() string {
+	if e.Attempts == 0 {
+		return fmt.Sprintf("%s %s failed: %v", e.Op, e.Endpoint, e.Err)
+	}
+	return fmt.Sprintf("%s %s failed after %d attempts: %v", e.Op, e.Endpoint, e.Attempts, e.Err)
+}
+
+// Unwrap returns the underlying error, so errors.Is matches context.DeadlineExceeded
+// and similar causes.
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
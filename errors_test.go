@@ -0,0 +1,153 @@
+package coinmarketcap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestErrorCodeNames(t *testing.T) {
+	tests := []struct {
+		code        ErrorCode
+		name        string
+		description bool
+	}{
+		{0, "OK", false},
+		{ErrorCodeBadRequest, "BAD_REQUEST", true},
+		{ErrorCodeAPIKeyInvalid, "API_KEY_INVALID", true},
+		{ErrorCodePlanUnauthorized, "API_KEY_PLAN_NOT_AUTHORIZED", true},
+		{ErrorCodeIPRateLimit, "IP_RATE_LIMIT_REACHED", true},
+		{1234, "ERROR_CODE_1234", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.code.String(); got != tt.name {
+				t.Errorf("expected %s, got %s", tt.name, got)
+			}
+			if got := tt.code.Description() != ""; got != tt.description {
+				t.Errorf("expected description presence %v, got %q", tt.description, tt.code.Description())
+			}
+		})
+	}
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	sentinels := []error{ErrRateLimited, ErrInvalidAPIKey, ErrPaymentRequired, ErrPlanUnauthorized, ErrNotFound, ErrBadRequest}
+
+	tests := []struct {
+		name     string
+		err      *APIError
+		expected error
+	}{
+		{"minute limit", &APIError{StatusCode: 429, ErrorCode: ErrorCodeMinuteRateLimit}, ErrRateLimited},
+		{"monthly limit", &APIError{StatusCode: 429, ErrorCode: ErrorCodeMonthlyRateLimit}, ErrRateLimited},
+		{"plain 429", &APIError{StatusCode: 429}, ErrRateLimited},
+		{"invalid key", &APIError{StatusCode: 401, ErrorCode: ErrorCodeAPIKeyInvalid}, ErrInvalidAPIKey},
+		{"disabled key", &APIError{StatusCode: 401, ErrorCode: ErrorCodeAPIKeyDisabled}, ErrInvalidAPIKey},
+		{"payment expired", &APIError{StatusCode: 402, ErrorCode: ErrorCodePlanPaymentExpired}, ErrPaymentRequired},
+		{"plan", &APIError{StatusCode: 403, ErrorCode: ErrorCodePlanUnauthorized}, ErrPlanUnauthorized},
+		{"not found", &APIError{StatusCode: 404}, ErrNotFound},
+		{"bad value", &APIError{StatusCode: 400, ErrorCode: ErrorCodeBadRequest}, ErrBadRequest},
+		{"server error", &APIError{StatusCode: 500, ErrorCode: ErrorCodeInternalServerError}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := fmt.Errorf("fetching quotes: %w", &BatchError{Batch: 1, Batches: 2, Err: tt.err})
+			for _, sentinel := range sentinels {
+				if got := errors.Is(wrapped, sentinel); got != (sentinel == tt.expected) {
+					t.Errorf("errors.Is(%v) = %v", sentinel, got)
+				}
+			}
+		})
+	}
+}
+
+func TestTransportErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gzip":
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write([]byte("not gzip"))
+		case "/json":
+			w.Write([]byte(`{"data": [1, 2], "status": {"error_code": 0}}`))
+		case "/drop":
+			hijacker := w.(http.Hijacker)
+			conn, _, _ := hijacker.Hijack()
+			conn.Close()
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1000)),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond}),
+	)
+
+	tests := []struct {
+		endpoint string
+		op       string
+		attempts int
+	}{
+		{"/gzip", OpDecompress, 1},
+		{"/json", OpDecode, 1},
+		{"/drop", OpRequest, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.op, func(t *testing.T) {
+			_, err := get[map[string]int](client, context.Background(), tt.endpoint, nil)
+
+			var transportErr *TransportError
+			if !errors.As(err, &transportErr) {
+				t.Fatalf("expected *TransportError, got %T: %v", err, err)
+			}
+			if transportErr.Op != tt.op || transportErr.Endpoint != tt.endpoint || transportErr.Attempts != tt.attempts {
+				t.Errorf("expected %s on %s after %d attempts, got %+v", tt.op, tt.endpoint, tt.attempts, transportErr)
+			}
+			if transportErr.Unwrap() == nil {
+				t.Error("expected the underlying error to be kept")
+			}
+		})
+	}
+}
+
+func TestQuotesDecodeError(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want any
+	}{
+		{"syntax", `{"data": {"1": `, new(*json.SyntaxError)},
+		{"type", `{"data": {"1": "bitcoin"}, "status": {"error_code": 0}}`, new(*json.UnmarshalTypeError)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL), WithRateLimit(rate.Limit(1000)))
+			_, err := client.GetCryptocurrencyQuotesLatest(context.Background(), &CryptocurrencyQuotesOptions{ID: []int{1}})
+
+			var transportErr *TransportError
+			if !errors.As(err, &transportErr) || transportErr.Op != OpDecode || transportErr.Unwrap() == nil {
+				t.Fatalf("expected a decode *TransportError, got %T: %v", err, err)
+			}
+			if !errors.As(err, tt.want) {
+				t.Errorf("expected the JSON error to be reachable, got %v", err)
+			}
+		})
+	}
+}
@@ -82,9 +82,9 @@ func main() {
 		return
 	}
 
-	if btcData, exists := historical.Data["BTC"]; exists {
-		fmt.Printf("Bitcoin price history (last %d days):\n", len(btcData))
-		for _, quote := range btcData {
+	if btcData, exists := historical.Data["BTC"]; exists && len(btcData) > 0 {
+		fmt.Printf("Bitcoin price history (last %d days):\n", len(btcData[0].Quotes))
+		for _, quote := range btcData[0].Quotes {
 			if usdQuote, exists := quote.Quote["USD"]; exists && usdQuote.Price != nil {
 				fmt.Printf("  %s: $%.2f\n", quote.Timestamp.Format("2006-01-02"), *usdQuote.Price)
 			}
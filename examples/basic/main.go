@@ -54,7 +54,11 @@ func main() {
 		return
 	}
 
-	for _, quote := range quotes.Data {
+	for _, matches := range quotes.Data {
+		quote := coinmarketcap.GetPrimaryQuote(matches)
+		if quote == nil {
+			continue
+		}
 		fmt.Printf("%s (%s):\n", quote.Name, quote.Symbol)
 		for currency, q := range quote.Quote {
 			if q.Price != nil {
@@ -1,193 +1,115 @@
 package coinmarketcap
 
-import "context"
+import (
+	"context"
+	"net/url"
+)
 
 type ExchangeMapOptions struct {
-	ListingStatus *ListingStatus
-	Slug          []string
-	Start         *int
-	Limit         *int
-	Sort          *ExchangeSort
-	Aux           []string
-	CryptoID      []int
+	ListingStatus *ListingStatus `query:"listing_status"`
+	Slug          []string       `query:"slug"`
+	Start         *int           `query:"start"`
+	Limit         *int           `query:"limit"`
+	Sort          *ExchangeSort  `query:"sort"`
+	Aux           []string       `query:"aux"`
+	CryptoID      []int          `query:"crypto_id"`
 }
 
 func (c *Client) GetExchangeMap(ctx context.Context, opts *ExchangeMapOptions) (*APIResponse[[]ExchangeMap], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		if opts.ListingStatus != nil {
-			params.Add("listing_status", string(*opts.ListingStatus))
-		}
-		params.AddStringSlice("slug", opts.Slug)
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		if opts.Sort != nil {
-			params.Add("sort", string(*opts.Sort))
-		}
-		params.AddStringSlice("aux", opts.Aux)
-		params.AddIntSlice("crypto_id", opts.CryptoID)
-	}
-
 	return get[[]ExchangeMap](c, ctx, "/v1/exchange/map", &RequestOptions[[]ExchangeMap]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type ExchangeInfoOptions struct {
-	ID   []int
-	Slug []string
-	Aux  []string
+	ID   []int    `query:"id"`
+	Slug []string `query:"slug"`
+	Aux  []string `query:"aux"`
 }
 
 func (c *Client) GetExchangeInfo(ctx context.Context, opts *ExchangeInfoOptions) (*APIResponse[map[string]ExchangeInfo], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddIntSlice("id", opts.ID)
-		params.AddStringSlice("slug", opts.Slug)
-		params.AddStringSlice("aux", opts.Aux)
-	}
-
-	return get[map[string]ExchangeInfo](c, ctx, "/v1/exchange/info", &RequestOptions[map[string]ExchangeInfo]{
-		QueryParams: params.Build(),
+	return getBatched(c, ctx, encodeQuery(opts), func(ctx context.Context, query url.Values) (*APIResponse[map[string]ExchangeInfo], error) {
+		return get[map[string]ExchangeInfo](c, ctx, "/v1/exchange/info", &RequestOptions[map[string]ExchangeInfo]{
+			QueryParams: query,
+		})
 	})
 }
 
 type ExchangeListingsOptions struct {
-	Start      *int
-	Limit      *int
-	Sort       *ExchangeSort
-	SortDir    *SortDirection
-	MarketType *MarketType
-	Category   *ExchangeCategory
-	Aux        []string
-	Convert    []string
+	Start      *int              `query:"start"`
+	Limit      *int              `query:"limit"`
+	Sort       *ExchangeSort     `query:"sort"`
+	SortDir    *SortDirection    `query:"sort_dir"`
+	MarketType *MarketType       `query:"market_type"`
+	Category   *ExchangeCategory `query:"category"`
+	Aux        []string          `query:"aux"`
+	Convert    []string          `query:"convert"`
 }
 
 func (c *Client) GetExchangeListingsLatest(ctx context.Context, opts *ExchangeListingsOptions) (*APIResponse[[]ExchangeListing], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		if opts.Sort != nil {
-			params.Add("sort", string(*opts.Sort))
-		}
-		if opts.SortDir != nil {
-			params.Add("sort_dir", string(*opts.SortDir))
-		}
-		if opts.MarketType != nil {
-			params.Add("market_type", string(*opts.MarketType))
-		}
-		if opts.Category != nil {
-			params.Add("category", string(*opts.Category))
-		}
-		params.AddStringSlice("aux", opts.Aux)
-		params.AddStringSlice("convert", opts.Convert)
-	}
-
 	return get[[]ExchangeListing](c, ctx, "/v1/exchange/listings/latest", &RequestOptions[[]ExchangeListing]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type ExchangeQuotesOptions struct {
-	ID      []int
-	Slug    []string
-	Convert []string
-	Aux     []string
+	ID      []int    `query:"id"`
+	Slug    []string `query:"slug"`
+	Convert []string `query:"convert"`
+	Aux     []string `query:"aux"`
 }
 
 func (c *Client) GetExchangeQuotesLatest(ctx context.Context, opts *ExchangeQuotesOptions) (*APIResponse[map[string]ExchangeQuote], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddIntSlice("id", opts.ID)
-		params.AddStringSlice("slug", opts.Slug)
-		params.AddStringSlice("convert", opts.Convert)
-		params.AddStringSlice("aux", opts.Aux)
-	}
-
 	return get[map[string]ExchangeQuote](c, ctx, "/v1/exchange/quotes/latest", &RequestOptions[map[string]ExchangeQuote]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type ExchangeQuotesHistoricalOptions struct {
-	ID        []int
-	Slug      []string
-	TimeStart *string
-	TimeEnd   *string
-	Count     *int
-	Interval  *Interval
-	Convert   []string
-	Aux       []string
+	ID        []int     `query:"id"`
+	Slug      []string  `query:"slug"`
+	TimeStart *string   `query:"time_start"`
+	TimeEnd   *string   `query:"time_end"`
+	Count     *int      `query:"count"`
+	Interval  *Interval `query:"interval"`
+	Convert   []string  `query:"convert"`
+	Aux       []string  `query:"aux"`
 }
 
-func (c *Client) GetExchangeQuotesHistorical(ctx context.Context, opts *ExchangeQuotesHistoricalOptions) (*APIResponse[map[string][]HistoricalQuote], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddIntSlice("id", opts.ID)
-		params.AddStringSlice("slug", opts.Slug)
-		params.Add("time_start", *opts.TimeStart)
-		params.Add("time_end", *opts.TimeEnd)
-		params.AddInt("count", opts.Count)
-		if opts.Interval != nil {
-			params.Add("interval", string(*opts.Interval))
-		}
-		params.AddStringSlice("convert", opts.Convert)
-		params.AddStringSlice("aux", opts.Aux)
-	}
-
-	return get[map[string][]HistoricalQuote](c, ctx, "/v1/exchange/quotes/historical", &RequestOptions[map[string][]HistoricalQuote]{
-		QueryParams: params.Build(),
+// GetExchangeQuotesHistorical returns the volume history of exchanges keyed by ID.
+func (c *Client) GetExchangeQuotesHistorical(ctx context.Context, opts *ExchangeQuotesHistoricalOptions) (*APIResponse[map[string]ExchangeQuotesHistorical], error) {
+	resp, err := get[exchangeHistory](c, ctx, "/v1/exchange/quotes/historical", &RequestOptions[exchangeHistory]{
+		QueryParams: encodeQuery(opts),
 	})
+	if err != nil {
+		return nil, err
+	}
+	return &APIResponse[map[string]ExchangeQuotesHistorical]{Data: resp.Data, Status: resp.Status}, nil
 }
 
 type ExchangeMarketPairsOptions struct {
-	ID            *int
-	Slug          *string
-	Start         *int
-	Limit         *int
-	Aux           []string
-	MatchedID     []int
-	MatchedSymbol []string
-	Category      *PairCategory
-	FeeType       *FeeType
-	Convert       []string
+	ID            *int          `query:"id"`
+	Slug          *string       `query:"slug"`
+	Start         *int          `query:"start"`
+	Limit         *int          `query:"limit"`
+	Aux           []string      `query:"aux"`
+	MatchedID     []int         `query:"matched_id"`
+	MatchedSymbol []string      `query:"matched_symbol"`
+	Category      *PairCategory `query:"category"`
+	FeeType       *FeeType      `query:"fee_type"`
+	Convert       []string      `query:"convert"`
 }
 
 func (c *Client) GetExchangeMarketPairsLatest(ctx context.Context, opts *ExchangeMarketPairsOptions) (*APIResponse[[]MarketPair], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddInt("id", opts.ID)
-		params.Add("slug", *opts.Slug)
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		params.AddStringSlice("aux", opts.Aux)
-		params.AddIntSlice("matched_id", opts.MatchedID)
-		params.AddStringSlice("matched_symbol", opts.MatchedSymbol)
-		if opts.Category != nil {
-			params.Add("category", string(*opts.Category))
-		}
-		if opts.FeeType != nil {
-			params.Add("fee_type", string(*opts.FeeType))
-		}
-		params.AddStringSlice("convert", opts.Convert)
-	}
-
 	return get[[]MarketPair](c, ctx, "/v1/exchange/market-pairs/latest", &RequestOptions[[]MarketPair]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
-func (c *Client) GetExchangeAssets(ctx context.Context, id int) (*APIResponse[map[string]interface{}], error) {
+func (c *Client) GetExchangeAssets(ctx context.Context, id int) (*APIResponse[[]ExchangeAsset], error) {
 	params := NewParamBuilder().AddInt("id", &id)
 
-	return get[map[string]interface{}](c, ctx, "/v1/exchange/assets", &RequestOptions[map[string]interface{}]{
+	return get[[]ExchangeAsset](c, ctx, "/v1/exchange/assets", &RequestOptions[[]ExchangeAsset]{
 		QueryParams: params.Build(),
 	})
 }
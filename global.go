@@ -1,98 +1,64 @@
 package coinmarketcap
 
-import "context"
+import (
+	"context"
+	"sort"
+	"time"
+)
 
 type GlobalMetricsOptions struct {
-	Convert   []string
-	ConvertID []int
+	Convert   []string `query:"convert"`
+	ConvertID []int    `query:"convert_id"`
 }
 
 func (c *Client) GetGlobalMetricsLatest(ctx context.Context, opts *GlobalMetricsOptions) (*APIResponse[GlobalMetrics], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddStringSlice("convert", opts.Convert)
-		params.AddIntSlice("convert_id", opts.ConvertID)
-	}
-
 	return get[GlobalMetrics](c, ctx, "/v1/global-metrics/quotes/latest", &RequestOptions[GlobalMetrics]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type GlobalMetricsHistoricalOptions struct {
-	TimeStart *string
-	TimeEnd   *string
-	Count     *int
-	Interval  *Interval
-	Convert   []string
-	Aux       []string
+	TimeStart *string   `query:"time_start"`
+	TimeEnd   *string   `query:"time_end"`
+	Count     *int      `query:"count"`
+	Interval  *Interval `query:"interval"`
+	Convert   []string  `query:"convert"`
+	Aux       []string  `query:"aux"`
 }
 
 func (c *Client) GetGlobalMetricsHistorical(ctx context.Context, opts *GlobalMetricsHistoricalOptions) (*APIResponse[[]GlobalMetrics], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.Add("time_start", *opts.TimeStart)
-		params.Add("time_end", *opts.TimeEnd)
-		params.AddInt("count", opts.Count)
-		if opts.Interval != nil {
-			params.Add("interval", string(*opts.Interval))
-		}
-		params.AddStringSlice("convert", opts.Convert)
-		params.AddStringSlice("aux", opts.Aux)
-	}
-
 	return get[[]GlobalMetrics](c, ctx, "/v1/global-metrics/quotes/historical", &RequestOptions[[]GlobalMetrics]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type FiatMapOptions struct {
-	Start         *int
-	Limit         *int
-	Sort          *string
-	IncludeMetals *bool
+	Start         *int    `query:"start"`
+	Limit         *int    `query:"limit"`
+	Sort          *string `query:"sort"`
+	IncludeMetals *bool   `query:"include_metals"`
 }
 
 func (c *Client) GetFiatMap(ctx context.Context, opts *FiatMapOptions) (*APIResponse[[]FiatMap], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		params.Add("sort", *opts.Sort)
-		params.AddBool("include_metals", opts.IncludeMetals)
-	}
-
 	return get[[]FiatMap](c, ctx, "/v1/fiat/map", &RequestOptions[[]FiatMap]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
+// PriceConversionOptions configures GetPriceConversion. Amount is required and always
+// sent, so it must be set to a non-zero value.
 type PriceConversionOptions struct {
-	Amount    float64
-	ID        *int
-	Symbol    *string
-	Time      *string
-	Convert   []string
-	ConvertID []int
+	Amount    float64  `query:"amount"`
+	ID        *int     `query:"id"`
+	Symbol    *string  `query:"symbol"`
+	Time      *string  `query:"time"`
+	Convert   []string `query:"convert"`
+	ConvertID []int    `query:"convert_id"`
 }
 
 func (c *Client) GetPriceConversion(ctx context.Context, opts *PriceConversionOptions) (*APIResponse[PriceConversion], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddFloat("amount", &opts.Amount)
-		params.AddInt("id", opts.ID)
-		params.Add("symbol", *opts.Symbol)
-		params.Add("time", *opts.Time)
-		params.AddStringSlice("convert", opts.Convert)
-		params.AddIntSlice("convert_id", opts.ConvertID)
-	}
-
 	return get[PriceConversion](c, ctx, "/v2/tools/price-conversion", &RequestOptions[PriceConversion]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
@@ -101,198 +67,182 @@ func (c *Client) GetPostmanCollection(ctx context.Context) (*APIResponse[interfa
 }
 
 type BlockchainStatsOptions struct {
-	ID     []int
-	Symbol []string
-	Slug   []string
+	ID     []int    `query:"id"`
+	Symbol []string `query:"symbol"`
+	Slug   []string `query:"slug"`
 }
 
 func (c *Client) GetBlockchainStatsLatest(ctx context.Context, opts *BlockchainStatsOptions) (*APIResponse[map[string]BlockchainStats], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddIntSlice("id", opts.ID)
-		params.AddStringSlice("symbol", opts.Symbol)
-		params.AddStringSlice("slug", opts.Slug)
-	}
-
 	return get[map[string]BlockchainStats](c, ctx, "/v1/blockchain/statistics/latest", &RequestOptions[map[string]BlockchainStats]{
-		QueryParams: params.Build(),
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type ContentLatestOptions struct {
-	Start            *int
-	Limit            *int
-	Category         *string
-	CryptocurrencyID *int
-	Language         *string
-	Sort             *string
-}
-
-func (c *Client) GetContentLatest(ctx context.Context, opts *ContentLatestOptions) (*APIResponse[[]interface{}], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		params.Add("category", *opts.Category)
-		params.AddInt("cryptocurrency_id", opts.CryptocurrencyID)
-		params.Add("language", *opts.Language)
-		params.Add("sort", *opts.Sort)
-	}
+	Start            *int    `query:"start"`
+	Limit            *int    `query:"limit"`
+	Category         *string `query:"category"`
+	CryptocurrencyID *int    `query:"cryptocurrency_id"`
+	Language         *string `query:"language"`
+	Sort             *string `query:"sort"`
+}
 
-	return get[[]interface{}](c, ctx, "/v1/content/latest", &RequestOptions[[]interface{}]{
-		QueryParams: params.Build(),
+func (c *Client) GetContentLatest(ctx context.Context, opts *ContentLatestOptions) (*APIResponse[[]NewsArticle], error) {
+	return get[[]NewsArticle](c, ctx, "/v1/content/latest", &RequestOptions[[]NewsArticle]{
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type ContentPostsOptions struct {
-	TimePeriod       *TimePeriod
-	CryptocurrencyID *int
-	Start            *int
-	Limit            *int
-	Sort             *string
+	TimePeriod       *TimePeriod `query:"time_period"`
+	CryptocurrencyID *int        `query:"cryptocurrency_id"`
+	Start            *int        `query:"start"`
+	Limit            *int        `query:"limit"`
+	Sort             *string     `query:"sort"`
+	LastScore        *string     `query:"last_score"`
 }
 
-func (c *Client) GetContentPostsTop(ctx context.Context, opts *ContentPostsOptions) (*APIResponse[[]interface{}], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		if opts.TimePeriod != nil {
-			params.Add("time_period", string(*opts.TimePeriod))
-		}
-		params.AddInt("cryptocurrency_id", opts.CryptocurrencyID)
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		params.Add("sort", *opts.Sort)
-	}
-
-	return get[[]interface{}](c, ctx, "/v1/content/posts/top", &RequestOptions[[]interface{}]{
-		QueryParams: params.Build(),
+func (c *Client) GetContentPostsTop(ctx context.Context, opts *ContentPostsOptions) (*APIResponse[PostList], error) {
+	return get[PostList](c, ctx, "/v1/content/posts/top", &RequestOptions[PostList]{
+		QueryParams: encodeQuery(opts),
 	})
 }
 
-func (c *Client) GetContentPostsLatest(ctx context.Context, opts *ContentPostsOptions) (*APIResponse[[]interface{}], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddInt("cryptocurrency_id", opts.CryptocurrencyID)
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		params.Add("sort", *opts.Sort)
-	}
-
-	return get[[]interface{}](c, ctx, "/v1/content/posts/latest", &RequestOptions[[]interface{}]{
-		QueryParams: params.Build(),
+func (c *Client) GetContentPostsLatest(ctx context.Context, opts *ContentPostsOptions) (*APIResponse[PostList], error) {
+	return get[PostList](c, ctx, "/v1/content/posts/latest", &RequestOptions[PostList]{
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type ContentCommentsOptions struct {
-	PostID string
-	Start  *int
-	Limit  *int
+	PostID string `query:"post_id"`
+	Start  *int   `query:"start"`
+	Limit  *int   `query:"limit"`
 }
 
-func (c *Client) GetContentPostsComments(ctx context.Context, opts *ContentCommentsOptions) (*APIResponse[[]interface{}], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.Add("post_id", opts.PostID)
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-	}
-
-	return get[[]interface{}](c, ctx, "/v1/content/posts/comments", &RequestOptions[[]interface{}]{
-		QueryParams: params.Build(),
+func (c *Client) GetContentPostsComments(ctx context.Context, opts *ContentCommentsOptions) (*APIResponse[[]Comment], error) {
+	return get[[]Comment](c, ctx, "/v1/content/posts/comments", &RequestOptions[[]Comment]{
+		QueryParams: encodeQuery(opts),
 	})
 }
 
 type CommunityTrendingOptions struct {
-	Start      *int
-	Limit      *int
-	TimePeriod *TimePeriod
+	Start      *int        `query:"start"`
+	Limit      *int        `query:"limit"`
+	TimePeriod *TimePeriod `query:"time_period"`
 }
 
-func (c *Client) GetCommunityTrendingTopic(ctx context.Context, opts *CommunityTrendingOptions) (*APIResponse[[]interface{}], error) {
-	params := NewParamBuilder()
-
-	if opts != nil {
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		if opts.TimePeriod != nil {
-			params.Add("time_period", string(*opts.TimePeriod))
-		}
-	}
+func (c *Client) GetCommunityTrendingTopic(ctx context.Context, opts *CommunityTrendingOptions) (*APIResponse[[]TrendingTopic], error) {
+	return get[[]TrendingTopic](c, ctx, "/v1/community/trending/topic", &RequestOptions[[]TrendingTopic]{
+		QueryParams: encodeQuery(opts),
+	})
+}
 
-	return get[[]interface{}](c, ctx, "/v1/community/trending/topic", &RequestOptions[[]interface{}]{
-		QueryParams: params.Build(),
+func (c *Client) GetCommunityTrendingToken(ctx context.Context, opts *CommunityTrendingOptions) (*APIResponse[[]TrendingToken], error) {
+	return get[[]TrendingToken](c, ctx, "/v1/community/trending/token", &RequestOptions[[]TrendingToken]{
+		QueryParams: encodeQuery(opts),
 	})
 }
 
-func (c *Client) GetCommunityTrendingToken(ctx context.Context, opts *CommunityTrendingOptions) (*APIResponse[[]interface{}], error) {
-	params := NewParamBuilder()
+func (c *Client) GetKeyInfo(ctx context.Context) (*APIResponse[KeyInfo], error) {
+	resp, err := get[KeyInfo](c, ctx, "/v1/key/info", &RequestOptions[KeyInfo]{})
+	if err != nil {
+		return nil, err
+	}
 
-	if opts != nil {
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
-		if opts.TimePeriod != nil {
-			params.Add("time_period", string(*opts.TimePeriod))
-		}
+	if c.keys == nil {
+		c.credits.Seed(&resp.Data)
 	}
+	return resp, nil
+}
 
-	return get[[]interface{}](c, ctx, "/v1/community/trending/token", &RequestOptions[[]interface{}]{
-		QueryParams: params.Build(),
+type IndexOptions struct {
+	TimeStart *string   `query:"time_start"`
+	TimeEnd   *string   `query:"time_end"`
+	Count     *int      `query:"count"`
+	Interval  *Interval `query:"interval"`
+}
+
+// GetIndexLatest returns the latest value and constituents of a CoinMarketCap index.
+func (c *Client) GetIndexLatest(ctx context.Context, index Index) (*APIResponse[IndexValue], error) {
+	return get[IndexValue](c, ctx, "/v3/index/"+string(index)+"-latest", &RequestOptions[IndexValue]{})
+}
+
+// GetIndexHistorical returns historical values and constituents of a CoinMarketCap index.
+func (c *Client) GetIndexHistorical(ctx context.Context, index Index, opts *IndexOptions) (*APIResponse[[]IndexValue], error) {
+	return get[[]IndexValue](c, ctx, "/v3/index/"+string(index)+"-historical", &RequestOptions[[]IndexValue]{
+		QueryParams: encodeQuery(opts),
 	})
 }
 
-func (c *Client) GetKeyInfo(ctx context.Context) (*APIResponse[KeyInfo], error) {
-	return get[KeyInfo](c, ctx, "/v1/key/info", &RequestOptions[KeyInfo]{})
+func (c *Client) GetIndexCMC100Latest(ctx context.Context) (*APIResponse[IndexValue], error) {
+	return c.GetIndexLatest(ctx, IndexCMC100)
 }
 
-type IndexOptions struct {
-	TimeStart *string
-	TimeEnd   *string
-	Count     *string
-	Interval  *string
+func (c *Client) GetIndexCMC100Historical(ctx context.Context, opts *IndexOptions) (*APIResponse[[]IndexValue], error) {
+	return c.GetIndexHistorical(ctx, IndexCMC100, opts)
 }
 
-func (c *Client) GetIndexCMC100Latest(ctx context.Context) (*APIResponse[interface{}], error) {
-	return get[interface{}](c, ctx, "/v3/index/cmc100-latest", &RequestOptions[interface{}]{})
+func (c *Client) GetIndexCMC20Latest(ctx context.Context) (*APIResponse[IndexValue], error) {
+	return c.GetIndexLatest(ctx, IndexCMC20)
 }
 
-func (c *Client) GetIndexCMC100Historical(ctx context.Context, opts *IndexOptions) (*APIResponse[[]interface{}], error) {
-	params := NewParamBuilder()
+func (c *Client) GetIndexCMC20Historical(ctx context.Context, opts *IndexOptions) (*APIResponse[[]IndexValue], error) {
+	return c.GetIndexHistorical(ctx, IndexCMC20, opts)
+}
 
-	if opts != nil {
-		params.Add("time_start", *opts.TimeStart)
-		params.Add("time_end", *opts.TimeEnd)
-		params.Add("count", *opts.Count)
-		params.Add("interval", *opts.Interval)
-	}
+func (c *Client) GetAltcoinSeasonIndexLatest(ctx context.Context) (*APIResponse[IndexValue], error) {
+	return c.GetIndexLatest(ctx, IndexAltcoinSeason)
+}
 
-	return get[[]interface{}](c, ctx, "/v3/index/cmc100-historical", &RequestOptions[[]interface{}]{
-		QueryParams: params.Build(),
-	})
+func (c *Client) GetAltcoinSeasonIndexHistorical(ctx context.Context, opts *IndexOptions) (*APIResponse[[]IndexValue], error) {
+	return c.GetIndexHistorical(ctx, IndexAltcoinSeason, opts)
 }
 
-func (c *Client) GetFearAndGreedLatest(ctx context.Context) (*APIResponse[interface{}], error) {
-	return get[interface{}](c, ctx, "/v3/fear-and-greed/latest", &RequestOptions[interface{}]{})
+func (c *Client) GetFearAndGreedLatest(ctx context.Context) (*APIResponse[FearAndGreed], error) {
+	return get[FearAndGreed](c, ctx, "/v3/fear-and-greed/latest", &RequestOptions[FearAndGreed]{})
 }
 
 type FearAndGreedHistoricalOptions struct {
-	Start *int
-	Limit *int
+	Start *int `query:"start"`
+	Limit *int `query:"limit"`
+}
+
+func (c *Client) GetFearAndGreedHistorical(ctx context.Context, opts *FearAndGreedHistoricalOptions) (*APIResponse[[]FearAndGreed], error) {
+	return get[[]FearAndGreed](c, ctx, "/v3/fear-and-greed/historical", &RequestOptions[[]FearAndGreed]{
+		QueryParams: encodeQuery(opts),
+	})
 }
 
-func (c *Client) GetFearAndGreedHistorical(ctx context.Context, opts *FearAndGreedHistoricalOptions) (*APIResponse[[]interface{}], error) {
-	params := NewParamBuilder()
+// FearAndGreedMaxLimit is the largest page /v3/fear-and-greed/historical returns.
+const FearAndGreedMaxLimit = 500
 
-	if opts != nil {
-		params.AddInt("start", opts.Start)
-		params.AddInt("limit", opts.Limit)
+// GetFearAndGreedRange returns the index readings between from and to (inclusive),
+// ordered from oldest to newest. It pages through the historical endpoint, which
+// returns newest readings first, until it passes from.
+func (c *Client) GetFearAndGreedRange(ctx context.Context, from, to time.Time) ([]FearAndGreed, error) {
+	pager := c.IterateFearAndGreedHistorical(ctx, &FearAndGreedHistoricalOptions{
+		Limit: Int(FearAndGreedMaxLimit),
+	})
+
+	var readings []FearAndGreed
+	for pager.Next() {
+		reading := pager.Item()
+		if reading.Timestamp.Before(from) {
+			break
+		}
+		if reading.Timestamp.After(to) {
+			continue
+		}
+		readings = append(readings, reading)
+	}
+	if err := pager.Err(); err != nil {
+		return nil, err
 	}
 
-	return get[[]interface{}](c, ctx, "/v3/fear-and-greed/historical", &RequestOptions[[]interface{}]{
-		QueryParams: params.Build(),
+	sort.Slice(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
 	})
+
+	return readings, nil
 }
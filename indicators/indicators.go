@@ -0,0 +1,142 @@
+// Package indicators computes technical indicators over candle series from the candles
+// package. Every indicator is streaming: it consumes one value or candle at a time, so
+// it can be fed a full history and then kept current as a poller delivers new data:
+//
+//	rsi := indicators.NewRSI(14)
+//	values, warmup := indicators.Apply[float64, float64](rsi, series.Closes())
+//
+//	for update := range watcher.Updates() {
+//		if value, ok := rsi.Update(*update.Quote.Price); ok {
+//			fmt.Printf("RSI %.2f\n", value)
+//		}
+//	}
+//
+// Constructors panic on periods smaller than one.
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// Indicator consumes inputs one at a time. Update returns the current output, which is
+// valid once ok is true, after the indicator has seen enough inputs to warm up.
+type Indicator[In, Out any] interface {
+	Update(in In) (out Out, ok bool)
+}
+
+// Apply feeds inputs to ind and returns its outputs from the first valid one on.
+// outputs[i] belongs to inputs[warmup+i].
+func Apply[In, Out any](ind Indicator[In, Out], inputs []In) (outputs []Out, warmup int) {
+	warmup = len(inputs)
+	for i, in := range inputs {
+		out, ok := ind.Update(in)
+		if !ok {
+			continue
+		}
+		if outputs == nil {
+			warmup = i
+			outputs = make([]Out, 0, len(inputs)-i)
+		}
+		outputs = append(outputs, out)
+	}
+	return outputs, warmup
+}
+
+func checkPeriod(name string, period int) {
+	if period < 1 {
+		panic(fmt.Sprintf("indicators: %s period must be positive, got %d", name, period))
+	}
+}
+
+// window holds the last n values.
+type window struct {
+	values []float64
+	next   int
+	full   bool
+}
+
+func newWindow(n int) *window {
+	return &window{values: make([]float64, n)}
+}
+
+// push adds v, evicting the oldest value once full.
+func (w *window) push(v float64) {
+	w.values[w.next] = v
+	w.next = (w.next + 1) % len(w.values)
+	if w.next == 0 {
+		w.full = true
+	}
+}
+
+func (w *window) sum() float64 {
+	var sum float64
+	for _, v := range w.values {
+		sum += v
+	}
+	return sum
+}
+
+func (w *window) mean() float64 {
+	return w.sum() / float64(len(w.values))
+}
+
+// stddev returns the standard deviation of a full window, dividing by n-ddof.
+func (w *window) stddev(ddof int) float64 {
+	mean := w.mean()
+	var sum float64
+	for _, v := range w.values {
+		sum += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sum / float64(len(w.values)-ddof))
+}
+
+// SMA is the simple moving average over a period.
+type SMA struct {
+	window *window
+}
+
+// NewSMA creates a simple moving average.
+func NewSMA(period int) *SMA {
+	checkPeriod("SMA", period)
+	return &SMA{window: newWindow(period)}
+}
+
+// Update adds a value. The average is valid after period values.
+func (s *SMA) Update(v float64) (float64, bool) {
+	s.window.push(v)
+	if !s.window.full {
+		return 0, false
+	}
+	return s.window.mean(), true
+}
+
+// EMA is the exponential moving average with smoothing 2/(period+1), seeded with the
+// simple average of the first period values.
+type EMA struct {
+	period int
+	alpha  float64
+	count  int
+	value  float64
+}
+
+// NewEMA creates an exponential moving average.
+func NewEMA(period int) *EMA {
+	checkPeriod("EMA", period)
+	return &EMA{period: period, alpha: 2 / float64(period+1)}
+}
+
+// Update adds a value. The average is valid after period values.
+func (e *EMA) Update(v float64) (float64, bool) {
+	e.count++
+	switch {
+	case e.count < e.period:
+		e.value += v
+		return 0, false
+	case e.count == e.period:
+		e.value = (e.value + v) / float64(e.period)
+	default:
+		e.value += e.alpha * (v - e.value)
+	}
+	return e.value, true
+}
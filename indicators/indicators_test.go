@@ -0,0 +1,184 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Davincible/go-coinmarketcap/candles"
+)
+
+// emaCloses is the 10-day EMA example from StockCharts' ChartSchool, whose published
+// EMA values are checked to two decimals.
+var emaCloses = []float64{
+	22.27, 22.19, 22.08, 22.17, 22.18, 22.13, 22.23, 22.43, 22.24, 22.29,
+	22.15, 22.39, 22.38, 22.61, 23.36, 24.05, 23.75, 23.83, 23.95, 23.63,
+	23.82, 23.87, 23.65, 23.19, 23.10, 23.33, 22.68, 23.10, 22.40, 22.17,
+}
+
+// rsiCloses is the 14-day RSI example from StockCharts' ChartSchool. Its table rounds
+// the intermediate averages, so it differs from exact Wilder smoothing by up to 0.07.
+var rsiCloses = []float64{
+	44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08,
+	45.89, 46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64,
+	46.21, 46.25, 45.71, 46.45, 45.78, 45.35, 44.03, 44.18, 44.22, 44.57,
+	43.42, 42.66, 43.13,
+}
+
+var testCandles = []candles.Candle{
+	{Open: 10, High: 12, Low: 9, Close: 11, Volume: 100},
+	{Open: 11, High: 13, Low: 10, Close: 12.5, Volume: 150},
+	{Open: 12.5, High: 12.8, Low: 11, Close: 11.2, Volume: 120},
+	{Open: 11.2, High: 11.5, Low: 9.5, Close: 10, Volume: 200},
+	{Open: 10, High: 10.8, Low: 9.8, Close: 10.6, Volume: 90},
+	{Open: 10.6, High: 12, Low: 10.4, Close: 11.9, Volume: 160},
+	{Open: 11.9, High: 12.2, Low: 11.1, Close: 11.3, Volume: 110},
+	{Open: 11.3, High: 11.6, Low: 10.2, Close: 10.4, Volume: 130},
+}
+
+func testCloses() []float64 {
+	closes := make([]float64, len(testCandles))
+	for i, c := range testCandles {
+		closes[i] = c.Close
+	}
+	return closes
+}
+
+func assertValues(t *testing.T, name string, got, want []float64, warmup, wantWarmup int, tolerance float64) {
+	t.Helper()
+	if warmup != wantWarmup {
+		t.Errorf("%s: expected warmup %d, got %d", name, wantWarmup, warmup)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%s: expected %d values, got %d: %v", name, len(want), len(got), got)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > tolerance {
+			t.Errorf("%s[%d]: expected %v, got %v", name, i, want[i], got[i])
+		}
+	}
+}
+
+func TestMovingAverages(t *testing.T) {
+	sma, warmup := Apply[float64, float64](NewSMA(10), emaCloses)
+	assertValues(t, "SMA", sma, []float64{
+		22.221, 22.209, 22.229, 22.259, 22.303, 22.421, 22.613, 22.765, 22.905, 23.076, 23.21,
+		23.377, 23.525, 23.652, 23.71, 23.684, 23.612, 23.505, 23.432, 23.277, 23.131,
+	}, warmup, 9, 0.00005)
+
+	ema, warmup := Apply[float64, float64](NewEMA(10), emaCloses)
+	assertValues(t, "EMA", ema, []float64{
+		22.22, 22.21, 22.24, 22.27, 22.33, 22.52, 22.80, 22.97, 23.13, 23.28, 23.34,
+		23.43, 23.51, 23.53, 23.47, 23.40, 23.39, 23.26, 23.23, 23.08, 22.92,
+	}, warmup, 9, 0.005)
+}
+
+func TestRSI(t *testing.T) {
+	rsi, warmup := Apply[float64, float64](NewRSI(14), rsiCloses)
+	assertValues(t, "RSI", rsi, []float64{
+		70.46, 66.25, 66.48, 69.35, 66.29, 57.92, 62.88, 63.21, 56.01, 62.34,
+		54.67, 50.39, 40.02, 41.49, 41.90, 45.50, 37.32, 33.09, 37.79,
+	}, warmup, 14, 0.005)
+
+	flat, _ := Apply[float64, float64](NewRSI(2), []float64{1, 1, 1, 2})
+	if flat[0] != 50 || flat[1] != 100 {
+		t.Errorf("expected 50 without movement and 100 without losses, got %v", flat)
+	}
+}
+
+func TestMACD(t *testing.T) {
+	values, warmup := Apply[float64, MACDValue](NewMACD(5, 10, 3), emaCloses)
+	var macd, signal, histogram []float64
+	for _, v := range values {
+		macd = append(macd, v.MACD)
+		signal = append(signal, v.Signal)
+		histogram = append(histogram, v.Histogram)
+	}
+
+	assertValues(t, "MACD", macd[:5], []float64{0.0415, 0.0487, 0.0845, 0.2126, 0.3741}, warmup, 11, 0.00005)
+	assertValues(t, "Signal", signal[:5], []float64{0.0366, 0.0426, 0.0636, 0.1381, 0.2561}, warmup, 11, 0.00005)
+	assertValues(t, "Histogram", histogram[len(histogram)-3:], []float64{-0.0295, -0.0606, -0.0669}, warmup, 11, 0.00005)
+	if len(values) != 19 {
+		t.Errorf("expected 19 values, got %d", len(values))
+	}
+}
+
+func TestBollinger(t *testing.T) {
+	values, warmup := Apply[float64, Bands](NewBollinger(20, 2), emaCloses)
+	var upper, middle, lower []float64
+	for _, v := range values {
+		upper = append(upper, v.Upper)
+		middle = append(middle, v.Middle)
+		lower = append(lower, v.Lower)
+	}
+
+	assertValues(t, "Upper", upper, []float64{24.1261, 24.2661, 24.3939, 24.4617, 24.4714, 24.4676, 24.4665, 24.4438, 24.4371, 24.4234, 24.4355}, warmup, 19, 0.00005)
+	assertValues(t, "Middle", middle, []float64{22.7155, 22.793, 22.877, 22.9555, 23.0065, 23.0525, 23.1125, 23.135, 23.1685, 23.1765, 23.1705}, warmup, 19, 0.00005)
+	assertValues(t, "Lower", lower, []float64{21.3049, 21.3199, 21.3601, 21.4493, 21.5416, 21.6374, 21.7585, 21.8262, 21.8999, 21.9296, 21.9055}, warmup, 19, 0.00005)
+	if width := values[0].Width(); math.Abs(width-(24.1261-21.3049)/22.7155) > 0.0001 {
+		t.Errorf("unexpected width %v", width)
+	}
+}
+
+func TestCandleIndicators(t *testing.T) {
+	atr, warmup := Apply[candles.Candle, float64](NewATR(3), testCandles)
+	assertValues(t, "ATR", atr, []float64{2.6, 2.4, 1.933333, 1.822222, 1.581481, 1.520988}, warmup, 2, 0.000001)
+
+	vwap, warmup := Apply[candles.Candle, float64](NewVWAP(0), testCandles)
+	assertValues(t, "VWAP", vwap, []float64{10.666667, 11.366667, 11.463964, 11.067251, 10.976263, 11.065447, 11.120789, 11.07327}, warmup, 0, 0.000001)
+
+	rolling, warmup := Apply[candles.Candle, float64](NewVWAP(3), testCandles)
+	assertValues(t, "VWAP(3)", rolling, []float64{11.463964, 11.152482, 10.738211, 10.737778, 11.205556, 11.233333}, warmup, 2, 0.000001)
+
+	anchored := NewVWAP(0)
+	anchored.Update(testCandles[0])
+	anchored.Reset()
+	if v, _ := anchored.Update(testCandles[1]); math.Abs(v-(13+10+12.5)/3) > 1e-9 {
+		t.Errorf("expected Reset to start a new period, got %v", v)
+	}
+	if _, ok := NewVWAP(0).Update(candles.Candle{Close: 1}); ok {
+		t.Error("expected no VWAP without volume")
+	}
+}
+
+func TestVolatilityAndDrawdown(t *testing.T) {
+	volatility, warmup := Apply[float64, float64](NewVolatility(5), testCloses())
+	assertValues(t, "Volatility", volatility, []float64{0.119148, 0.102859, 0.097581}, warmup, 5, 0.000001)
+
+	if got := Annualize(0.01, 24*time.Hour); math.Abs(got-0.01*math.Sqrt(365)) > 1e-12 {
+		t.Errorf("unexpected annualized volatility %v", got)
+	}
+
+	drawdowns, _ := Apply[float64, DrawdownValue](NewDrawdown(), testCloses())
+	var current, maximum []float64
+	for _, d := range drawdowns {
+		current = append(current, d.Drawdown)
+		maximum = append(maximum, d.MaxDrawdown)
+	}
+	assertValues(t, "Drawdown", current, []float64{0, 0, 0.104, 0.2, 0.152, 0.048, 0.096, 0.168}, 0, 0, 1e-9)
+	assertValues(t, "MaxDrawdown", maximum, []float64{0, 0, 0.104, 0.2, 0.2, 0.2, 0.2, 0.2}, 0, 0, 1e-9)
+	if drawdowns[7].Peak != 12.5 {
+		t.Errorf("expected peak 12.5, got %v", drawdowns[7].Peak)
+	}
+}
+
+func TestStreamingMatchesBatch(t *testing.T) {
+	batch, warmup := Apply[float64, float64](NewEMA(10), emaCloses)
+
+	// Warm up on history, then feed the rest one value at a time like a poller would.
+	stream := NewEMA(10)
+	Apply[float64, float64](stream, emaCloses[:20])
+	for i, v := range emaCloses[20:] {
+		got, ok := stream.Update(v)
+		if want := batch[20+i-warmup]; !ok || got != want {
+			t.Errorf("update %d: expected %v, got %v", i, want, got)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a zero period")
+		}
+	}()
+	NewSMA(0)
+}
@@ -0,0 +1,84 @@
+package indicators
+
+// RSI is Wilder's relative strength index: 100 - 100/(1 + average gain/average loss),
+// with the averages seeded by the first period changes and then smoothed by Wilder's
+// method.
+type RSI struct {
+	period  int
+	changes int
+	prev    float64
+	gain    float64
+	loss    float64
+}
+
+// NewRSI creates a relative strength index, commonly with period 14.
+func NewRSI(period int) *RSI {
+	checkPeriod("RSI", period)
+	return &RSI{period: period, changes: -1}
+}
+
+// Update adds a close. The index is valid after period+1 closes.
+func (r *RSI) Update(v float64) (float64, bool) {
+	r.changes++
+	change := v - r.prev
+	r.prev = v
+	if r.changes == 0 {
+		return 0, false
+	}
+
+	gain, loss := max(change, 0), max(-change, 0)
+	n := float64(r.period)
+	switch {
+	case r.changes < r.period:
+		r.gain += gain
+		r.loss += loss
+		return 0, false
+	case r.changes == r.period:
+		r.gain = (r.gain + gain) / n
+		r.loss = (r.loss + loss) / n
+	default:
+		r.gain = (r.gain*(n-1) + gain) / n
+		r.loss = (r.loss*(n-1) + loss) / n
+	}
+
+	switch {
+	case r.loss == 0 && r.gain == 0:
+		return 50, true
+	case r.loss == 0:
+		return 100, true
+	}
+	return 100 - 100/(1+r.gain/r.loss), true
+}
+
+// MACDValue is one output of MACD.
+type MACDValue struct {
+	MACD      float64 // fast EMA minus slow EMA
+	Signal    float64 // EMA of MACD
+	Histogram float64 // MACD minus Signal
+}
+
+// MACD is the moving average convergence divergence.
+type MACD struct {
+	fast, slow, signal *EMA
+}
+
+// NewMACD creates a MACD, commonly with periods 12, 26 and 9.
+func NewMACD(fast, slow, signal int) *MACD {
+	return &MACD{fast: NewEMA(fast), slow: NewEMA(slow), signal: NewEMA(signal)}
+}
+
+// Update adds a close. The value is valid after slow+signal-1 closes.
+func (m *MACD) Update(v float64) (MACDValue, bool) {
+	fast, fastOK := m.fast.Update(v)
+	slow, slowOK := m.slow.Update(v)
+	if !fastOK || !slowOK {
+		return MACDValue{}, false
+	}
+
+	line := fast - slow
+	signal, ok := m.signal.Update(line)
+	if !ok {
+		return MACDValue{}, false
+	}
+	return MACDValue{MACD: line, Signal: signal, Histogram: line - signal}, true
+}
@@ -0,0 +1,82 @@
+package indicators
+
+import "github.com/Davincible/go-coinmarketcap/candles"
+
+// VWAP is the volume-weighted average of the typical price (high+low+close)/3.
+type VWAP struct {
+	prices  *window // price times volume
+	volumes *window
+	price   float64
+	volume  float64
+}
+
+// NewVWAP creates a VWAP over the last period candles, or over every candle since
+// creation or the last Reset if period is zero.
+func NewVWAP(period int) *VWAP {
+	if period == 0 {
+		return &VWAP{}
+	}
+	checkPeriod("VWAP", period)
+	return &VWAP{prices: newWindow(period), volumes: newWindow(period)}
+}
+
+// Update adds a candle. The average is valid once the window is full and has volume.
+func (v *VWAP) Update(c candles.Candle) (float64, bool) {
+	price := (c.High + c.Low + c.Close) / 3 * c.Volume
+	if v.prices == nil {
+		v.price += price
+		v.volume += c.Volume
+	} else {
+		v.prices.push(price)
+		v.volumes.push(c.Volume)
+		if !v.prices.full {
+			return 0, false
+		}
+		v.price, v.volume = v.prices.sum(), v.volumes.sum()
+	}
+
+	if v.volume == 0 {
+		return 0, false
+	}
+	return v.price / v.volume, true
+}
+
+// Reset starts a new anchored period, e.g. at the start of each day.
+func (v *VWAP) Reset() {
+	period := 0
+	if v.prices != nil {
+		period = len(v.prices.values)
+	}
+	*v = *NewVWAP(period)
+}
+
+// DrawdownValue is one output of Drawdown.
+type DrawdownValue struct {
+	Peak        float64 // the highest value so far
+	Drawdown    float64 // the fall from Peak as a fraction, e.g. 0.2 for 20%
+	MaxDrawdown float64 // the largest Drawdown so far
+}
+
+// Drawdown tracks the fall from the running peak.
+type Drawdown struct {
+	value DrawdownValue
+	seen  bool
+}
+
+// NewDrawdown creates a drawdown tracker.
+func NewDrawdown() *Drawdown {
+	return &Drawdown{}
+}
+
+// Update adds a value. The drawdown is always valid.
+func (d *Drawdown) Update(v float64) (DrawdownValue, bool) {
+	if !d.seen || v > d.value.Peak {
+		d.value.Peak = v
+		d.seen = true
+	}
+	if d.value.Peak > 0 {
+		d.value.Drawdown = (d.value.Peak - v) / d.value.Peak
+	}
+	d.value.MaxDrawdown = max(d.value.MaxDrawdown, d.value.Drawdown)
+	return d.value, true
+}
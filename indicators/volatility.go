@@ -0,0 +1,120 @@
+package indicators
+
+import (
+	"math"
+	"time"
+
+	"github.com/Davincible/go-coinmarketcap/candles"
+)
+
+// Bands is one output of Bollinger.
+type Bands struct {
+	Upper  float64
+	Middle float64
+	Lower  float64
+}
+
+// Width returns the distance between the bands relative to the middle band.
+func (b Bands) Width() float64 {
+	return (b.Upper - b.Lower) / b.Middle
+}
+
+// Bollinger is Bollinger bands: the simple moving average plus and minus k population
+// standard deviations.
+type Bollinger struct {
+	window *window
+	k      float64
+}
+
+// NewBollinger creates Bollinger bands, commonly with period 20 and k 2.
+func NewBollinger(period int, k float64) *Bollinger {
+	checkPeriod("Bollinger", period)
+	return &Bollinger{window: newWindow(period), k: k}
+}
+
+// Update adds a close. The bands are valid after period closes.
+func (b *Bollinger) Update(v float64) (Bands, bool) {
+	b.window.push(v)
+	if !b.window.full {
+		return Bands{}, false
+	}
+
+	middle, deviation := b.window.mean(), b.k*b.window.stddev(0)
+	return Bands{Upper: middle + deviation, Middle: middle, Lower: middle - deviation}, true
+}
+
+// ATR is Wilder's average true range. The true range of a candle is the largest of its
+// range and its distances from the previous close.
+type ATR struct {
+	period int
+	count  int
+	prev   float64
+	value  float64
+}
+
+// NewATR creates an average true range, commonly with period 14.
+func NewATR(period int) *ATR {
+	checkPeriod("ATR", period)
+	return &ATR{period: period}
+}
+
+// Update adds a candle. The average is valid after period candles.
+func (a *ATR) Update(c candles.Candle) (float64, bool) {
+	trueRange := c.High - c.Low
+	if a.count > 0 {
+		trueRange = max(trueRange, math.Abs(c.High-a.prev), math.Abs(c.Low-a.prev))
+	}
+	a.prev = c.Close
+	a.count++
+
+	n := float64(a.period)
+	switch {
+	case a.count < a.period:
+		a.value += trueRange
+		return 0, false
+	case a.count == a.period:
+		a.value = (a.value + trueRange) / n
+	default:
+		a.value = (a.value*(n-1) + trueRange) / n
+	}
+	return a.value, true
+}
+
+// Volatility is the sample standard deviation of log returns over a period, per
+// interval of the input. See Annualize.
+type Volatility struct {
+	window *window
+	prev   float64
+	seen   bool
+}
+
+// NewVolatility creates a rolling volatility over period returns.
+func NewVolatility(period int) *Volatility {
+	checkPeriod("Volatility", period)
+	return &Volatility{window: newWindow(period)}
+}
+
+// Update adds a close. The volatility is valid after period+1 closes; for period 1 it
+// is always zero.
+func (v *Volatility) Update(price float64) (float64, bool) {
+	prev, seen := v.prev, v.seen
+	v.prev, v.seen = price, true
+	if !seen {
+		return 0, false
+	}
+
+	v.window.push(math.Log(price / prev))
+	if !v.window.full {
+		return 0, false
+	}
+	if len(v.window.values) == 1 {
+		return 0, true
+	}
+	return v.window.stddev(1), true
+}
+
+// Annualize scales a per-interval volatility to a year of continuous trading, as
+// crypto markets never close.
+func Annualize(volatility float64, interval time.Duration) float64 {
+	return volatility * math.Sqrt(float64(365*24*time.Hour)/float64(interval))
+}
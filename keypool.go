@@ -0,0 +1,339 @@
+package coinmarketcap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ErrNoAvailableKey is matched by errors.Is when every key in a KeyPool is out of rotation.
+var ErrNoAvailableKey = errors.New("no API key available")
+
+// NoAvailableKeyError is returned when no pooled key can serve a request. It wraps the
+// error that took the first returning key out of rotation, so errors.Is also matches
+// ErrRateLimited, ErrInvalidAPIKey or ErrBudgetExceeded as appropriate.
+type NoAvailableKeyError struct {
+	Next time.Time // when the first key returns; zero if none will without Enable
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *NoAvailableKeyError) Error() string {
+	msg := ErrNoAvailableKey.Error()
+	if !e.Next.IsZero() {
+		msg += " until " + e.Next.Format(time.RFC3339)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Is reports whether target is ErrNoAvailableKey.
+func (e *NoAvailableKeyError) Is(target error) bool {
+	return target == ErrNoAvailableKey
+}
+
+// Unwrap returns the error that disabled the key.
+func (e *NoAvailableKeyError) Unwrap() error {
+	return e.Err
+}
+
+// KeySelection decides which available key of a KeyPool serves the next request.
+type KeySelection int
+
+const (
+	// RoundRobin cycles through the available keys in order.
+	RoundRobin KeySelection = iota
+	// LeastUsed picks the available key that has spent the fewest credits today,
+	// then the one that has served the fewest requests.
+	LeastUsed
+)
+
+// PooledKey configures one key of a KeyPool.
+type PooledKey struct {
+	// Name identifies the key in KeyStatus. It defaults to the last four characters of Key.
+	Name string
+	Key  string
+	// RateLimit is the key's own limit in requests per second. Zero uses the client's rate limit.
+	RateLimit rate.Limit
+	// DailyCreditBudget and MonthlyCreditBudget cap the key's spending. Zero means unlimited.
+	// They are soft limits: requests in flight on the key count only once they complete.
+	DailyCreditBudget   int
+	MonthlyCreditBudget int
+}
+
+// KeyStatus is a snapshot of one pooled key.
+type KeyStatus struct {
+	Name          string
+	Available     bool
+	DisabledUntil time.Time // zero while available, or when only Enable brings the key back
+	Err           error     // why the key is out of rotation
+	Requests      int
+	Credits       CreditUsage
+}
+
+// KeyPool spreads requests over several API keys, each with its own rate limiter and
+// CreditMeter. A key that is rate limited (1008-1011) leaves the rotation until its
+// limit resets, at the next minute or at the daily or monthly reset of its meter; an
+// invalid or disabled key (1001, 1007) stays out until Enable is called. The failed
+// request moves on to the next key right away. Keys whose budget is used up are skipped.
+//
+// Requests that set the X-CMC_PRO_API_KEY header themselves, for example from a
+// middleware, bypass the pool. A KeyPool is safe for concurrent use.
+type KeyPool struct {
+	selection KeySelection
+	now       func() time.Time
+
+	mu   sync.Mutex
+	keys []*pooledKey
+	next int
+}
+
+type pooledKey struct {
+	name      string
+	key       string
+	rateLimit rate.Limit
+	limiter   *rate.Limiter
+	credits   *CreditMeter
+	requests  int
+
+	disabled      bool
+	disabledUntil time.Time
+	err           error
+}
+
+// NewKeyPool creates a pool of keys that are picked according to selection.
+func NewKeyPool(selection KeySelection, keys ...PooledKey) *KeyPool {
+	p := &KeyPool{selection: selection, now: time.Now}
+
+	for _, key := range keys {
+		name := key.Name
+		if name == "" {
+			name = maskKey(key.Key)
+		}
+		p.keys = append(p.keys, &pooledKey{
+			name:      name,
+			key:       key.Key,
+			rateLimit: key.RateLimit,
+			credits:   NewCreditMeter(key.DailyCreditBudget, key.MonthlyCreditBudget),
+		})
+	}
+
+	return p
+}
+
+// WithAPIKeys rotates requests over several keys round-robin. Each key gets its own
+// limiter at the client's rate limit. Use WithKeyPool for per-key limits and budgets.
+func WithAPIKeys(keys ...string) Option {
+	return func(c *ClientConfig) {
+		c.APIKeys = keys
+	}
+}
+
+// WithKeyPool makes the client take its API keys from pool. It overrides
+// WithAPIKey and WithAPIKeys.
+func WithKeyPool(pool *KeyPool) Option {
+	return func(c *ClientConfig) {
+		c.KeyPool = pool
+	}
+}
+
+// KeyPool returns the client's key pool, or nil when it uses a single key.
+func (c *Client) KeyPool() *KeyPool {
+	return c.keys
+}
+
+// init creates the limiters of keys without their own rate limit.
+func (p *KeyPool) init(limit rate.Limit) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, k := range p.keys {
+		if k.limiter != nil {
+			continue
+		}
+		keyLimit := limit
+		if k.rateLimit > 0 {
+			keyLimit = k.rateLimit
+		}
+		k.limiter = rate.NewLimiter(keyLimit, 1)
+	}
+}
+
+// acquire picks the key for the next attempt.
+func (p *KeyPool) acquire() (*pooledKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	unavailable := &NoAvailableKeyError{}
+	var picked *pooledKey
+
+	for i := range p.keys {
+		idx := (p.next + i) % len(p.keys)
+		k := p.keys[idx]
+
+		if k.disabled && (k.disabledUntil.IsZero() || now.Before(k.disabledUntil)) {
+			unavailable.add(k.disabledUntil, k.err)
+			continue
+		}
+		k.disabled, k.disabledUntil, k.err = false, time.Time{}, nil
+
+		if err := k.credits.Check(); err != nil {
+			var budgetErr *BudgetError
+			errors.As(err, &budgetErr)
+			unavailable.add(budgetErr.Reset, err)
+			continue
+		}
+
+		if p.selection == RoundRobin {
+			picked = k
+			p.next = idx + 1
+			break
+		}
+		if picked == nil || k.lessUsed(picked) {
+			picked = k
+		}
+	}
+
+	if picked == nil {
+		return nil, unavailable
+	}
+
+	picked.requests++
+	return picked, nil
+}
+
+// add records a key that is out of rotation, keeping the one that returns first.
+func (e *NoAvailableKeyError) add(until time.Time, err error) {
+	if e.Err != nil {
+		if until.IsZero() || (!e.Next.IsZero() && !until.Before(e.Next)) {
+			return
+		}
+	}
+	e.Next, e.Err = until, err
+}
+
+// lessUsed reports whether k has been used less than other today. Callers must hold p.mu.
+func (k *pooledKey) lessUsed(other *pooledKey) bool {
+	kCredits, otherCredits := k.credits.Usage().Daily, other.credits.Usage().Daily
+	if kCredits != otherCredits {
+		return kCredits < otherCredits
+	}
+	return k.requests < other.requests
+}
+
+// report takes k out of rotation when err shows that it is rate limited, out of
+// credits, invalid or disabled, and reports whether it did. retryAfter is the
+// Retry-After delay sent with the response, if any.
+func (p *KeyPool) report(k *pooledKey, err *APIError, retryAfter time.Duration) bool {
+	now := p.now()
+
+	var until time.Time
+	switch {
+	case err.ErrorCode == ErrorCodeMinuteRateLimit, err.ErrorCode == ErrorCodeIPRateLimit,
+		err.ErrorCode == 0 && err.StatusCode == http.StatusTooManyRequests:
+		until = now.Truncate(time.Minute).Add(time.Minute)
+		if retryAfter > 0 {
+			until = now.Add(retryAfter)
+		}
+	case err.ErrorCode == ErrorCodeDailyRateLimit:
+		until = k.credits.Usage().DailyReset
+	case err.ErrorCode == ErrorCodeMonthlyRateLimit:
+		until = k.credits.Usage().MonthlyReset
+	case err.ErrorCode == ErrorCodeAPIKeyInvalid, err.ErrorCode == ErrorCodeAPIKeyDisabled:
+		// Stays out until Enable is called.
+	default:
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k.disabled, k.disabledUntil, k.err = true, until, err
+	return true
+}
+
+// Enable puts the named key back into rotation and reports whether it exists.
+func (p *KeyPool) Enable(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, k := range p.keys {
+		if k.name == name {
+			k.disabled, k.disabledUntil, k.err = false, time.Time{}, nil
+			return true
+		}
+	}
+	return false
+}
+
+// Status returns a snapshot of every key in the pool.
+func (p *KeyPool) Status() []KeyStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	status := make([]KeyStatus, 0, len(p.keys))
+	for _, k := range p.keys {
+		disabled := k.disabled && (k.disabledUntil.IsZero() || now.Before(k.disabledUntil))
+		s := KeyStatus{
+			Name:     k.name,
+			Requests: k.requests,
+			Credits:  k.credits.Usage(),
+		}
+		if disabled {
+			s.DisabledUntil, s.Err = k.disabledUntil, k.err
+		} else if err := k.credits.Check(); err != nil {
+			s.Err = err
+		}
+		s.Available = s.Err == nil
+		status = append(status, s)
+	}
+	return status
+}
+
+// Credits returns the credit meter of the named key, or nil if there is none.
+func (p *KeyPool) Credits(name string) *CreditMeter {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, k := range p.keys {
+		if k.name == name {
+			return k.credits
+		}
+	}
+	return nil
+}
+
+// sync seeds every key's credit meter from GetKeyInfo, sent with that key.
+func (p *KeyPool) sync(ctx context.Context, c *Client) error {
+	p.mu.Lock()
+	keys := append([]*pooledKey(nil), p.keys...)
+	p.mu.Unlock()
+
+	for _, k := range keys {
+		resp, err := get[KeyInfo](c, ctx, "/v1/key/info", &RequestOptions[KeyInfo]{
+			Headers: map[string]string{apiKeyHeader: k.key},
+		})
+		if err != nil {
+			return fmt.Errorf("key %s: %w", k.name, err)
+		}
+		k.credits.Seed(&resp.Data)
+	}
+	return nil
+}
+
+// maskKey shortens an API key to its last four characters for display.
+func maskKey(key string) string {
+	if len(key) <= 4 {
+		return key
+	}
+	return "..." + key[len(key)-4:]
+}
@@ -0,0 +1,211 @@
+package coinmarketcap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// keyServer answers with one credit per request, unless a key has a queued failure.
+type keyServer struct {
+	mu       sync.Mutex
+	keys     []string
+	failures map[string][]string // key -> bodies with status.error_code
+}
+
+func (s *keyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := r.Header.Get("X-CMC_PRO_API_KEY")
+	s.keys = append(s.keys, key)
+
+	if queued := s.failures[key]; len(queued) > 0 {
+		s.failures[key] = queued[1:]
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(queued[0]))
+		return
+	}
+	w.Write([]byte(`{"data": {}, "status": {"error_code": 0, "credit_count": 1}}`))
+}
+
+func (s *keyServer) fail(key string, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[key] = append(s.failures[key], body)
+}
+
+func (s *keyServer) seen() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := s.keys
+	s.keys = nil
+	return keys
+}
+
+const (
+	minuteLimitBody = `{"status": {"error_code": 1008, "error_message": "minute limit"}}`
+	invalidKeyBody  = `{"status": {"error_code": 1001, "error_message": "invalid key"}}`
+)
+
+func newKeyServer(t *testing.T) (*keyServer, string) {
+	handler := &keyServer{failures: make(map[string][]string)}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return handler, server.URL
+}
+
+func callTimes(t *testing.T, client *Client, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if _, err := get[map[string]interface{}](client, context.Background(), "/test", nil); err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i, err)
+		}
+	}
+}
+
+func TestKeyPoolRoundRobin(t *testing.T) {
+	handler, url := newKeyServer(t)
+	client := NewClient(
+		WithBaseURL(url),
+		WithRateLimit(rate.Limit(1000)),
+		WithAPIKeys("key-a", "key-b", "key-c"),
+	)
+
+	callTimes(t, client, 6)
+
+	expected := []string{"key-a", "key-b", "key-c", "key-a", "key-b", "key-c"}
+	if got := handler.seen(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected keys %v, got %v", expected, got)
+	}
+
+	for _, status := range client.KeyPool().Status() {
+		if !status.Available || status.Requests != 2 || status.Credits.Daily != 2 {
+			t.Errorf("unexpected status %+v", status)
+		}
+	}
+	if client.Credits().Usage().Daily != 6 {
+		t.Errorf("expected the client meter to count every key, got %d", client.Credits().Usage().Daily)
+	}
+}
+
+func TestKeyPoolFailover(t *testing.T) {
+	handler, url := newKeyServer(t)
+	pool := NewKeyPool(RoundRobin,
+		PooledKey{Name: "a", Key: "key-a"},
+		PooledKey{Name: "b", Key: "key-b"},
+	)
+	now := time.Now()
+	pool.now = func() time.Time { return now }
+
+	client := NewClient(
+		WithBaseURL(url),
+		WithRateLimit(rate.Limit(1000)),
+		WithRetryPolicy(RetryPolicy{}),
+		WithKeyPool(pool),
+	)
+
+	handler.fail("key-a", minuteLimitBody)
+	callTimes(t, client, 3)
+
+	expected := []string{"key-a", "key-b", "key-b", "key-b"}
+	if got := handler.seen(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected failover to key-b, got %v", got)
+	}
+
+	status := pool.Status()[0]
+	if status.Available || !status.DisabledUntil.Equal(now.Add(30*time.Second)) || !errors.Is(status.Err, ErrRateLimited) {
+		t.Errorf("expected key a to be out until Retry-After, got %+v", status)
+	}
+
+	now = now.Add(31 * time.Second)
+	callTimes(t, client, 2)
+	if got := handler.seen(); !reflect.DeepEqual(got, []string{"key-a", "key-b"}) {
+		t.Errorf("expected key a back in rotation, got %v", got)
+	}
+}
+
+func TestKeyPoolExhausted(t *testing.T) {
+	handler, url := newKeyServer(t)
+	client := NewClient(
+		WithBaseURL(url),
+		WithRateLimit(rate.Limit(1000)),
+		WithKeyPool(NewKeyPool(LeastUsed,
+			PooledKey{Name: "a", Key: "key-a"},
+			PooledKey{Name: "b", Key: "key-b"},
+		)),
+	)
+	pool := client.KeyPool()
+
+	handler.fail("key-a", invalidKeyBody)
+	handler.fail("key-b", minuteLimitBody)
+
+	_, err := get[map[string]interface{}](client, context.Background(), "/test", nil)
+	if !errors.Is(err, ErrNoAvailableKey) || !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrNoAvailableKey wrapping the rate limit, got %v", err)
+	}
+
+	var noKey *NoAvailableKeyError
+	if !errors.As(err, &noKey) || noKey.Next.IsZero() {
+		t.Errorf("expected the time key b returns, got %+v", noKey)
+	}
+	if got := handler.seen(); len(got) != 2 {
+		t.Errorf("expected one attempt per key, got %v", got)
+	}
+
+	if status := pool.Status()[0]; !status.DisabledUntil.IsZero() || !errors.Is(status.Err, ErrInvalidAPIKey) {
+		t.Errorf("expected key a to be out until enabled, got %+v", status)
+	}
+
+	if !pool.Enable("a") || pool.Enable("missing") {
+		t.Fatal("expected Enable to report whether the key exists")
+	}
+	callTimes(t, client, 1)
+	if got := handler.seen(); !reflect.DeepEqual(got, []string{"key-a"}) {
+		t.Errorf("expected the enabled key to be used, got %v", got)
+	}
+}
+
+func TestKeyPoolLeastUsedAndBudgets(t *testing.T) {
+	handler, url := newKeyServer(t)
+	pool := NewKeyPool(LeastUsed,
+		PooledKey{Name: "small", Key: "key-small", DailyCreditBudget: 1},
+		PooledKey{Name: "large", Key: "key-large", RateLimit: rate.Limit(1000)},
+	)
+	pool.Credits("large").Record("/test", 2)
+
+	client := NewClient(
+		WithBaseURL(url),
+		WithRateLimit(rate.Limit(1000)),
+		WithKeyPool(pool),
+	)
+
+	callTimes(t, client, 3)
+
+	expected := []string{"key-small", "key-large", "key-large"}
+	if got := handler.seen(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected the least used key until its budget ran out, got %v", got)
+	}
+	if status := pool.Status()[0]; status.Available || !errors.Is(status.Err, ErrBudgetExceeded) {
+		t.Errorf("expected the small key to be over budget, got %+v", status)
+	}
+
+	// A key set explicitly bypasses the pool.
+	_, err := get[map[string]interface{}](client, context.Background(), "/test", &RequestOptions[map[string]interface{}]{
+		Headers: map[string]string{"X-CMC_PRO_API_KEY": "key-override"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := handler.seen(); !reflect.DeepEqual(got, []string{"key-override"}) {
+		t.Errorf("expected the header override to be sent, got %v", got)
+	}
+}
@@ -0,0 +1,69 @@
+package coinmarketcap
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Request is an API call passing through the middleware chain. Middleware may change
+// Query and Header before calling the next handler; the changes apply to the HTTP
+// request and to the cache key.
+type Request struct {
+	Endpoint string
+	Query    url.Values
+	Header   http.Header // per-request headers, applied over the client defaults
+}
+
+// Response is the undecoded result of a Request.
+type Response struct {
+	StatusCode int
+	Header     http.Header // nil when served from the cache
+	Body       []byte      // decompressed JSON body
+	Status     Status      // decoded status envelope
+	Attempts   int         // HTTP attempts made; 0 when served from the cache
+	Cached     bool
+}
+
+// Handler executes a Request. The innermost handler applies the cache, the credit
+// budget, rate limiting and retries.
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Middleware wraps a Handler to add behavior around every API call:
+//
+//	audit := func(next coinmarketcap.Handler) coinmarketcap.Handler {
+//		return func(ctx context.Context, req *coinmarketcap.Request) (*coinmarketcap.Response, error) {
+//			req.Header.Set("X-Tenant", tenantFrom(ctx))
+//			resp, err := next(ctx, req)
+//			if err == nil {
+//				log.Printf("%s cost %d credits", req.Endpoint, resp.Status.CreditCount)
+//			}
+//			return resp, err
+//		}
+//	}
+//
+// A response whose Status reports an error code is returned without an error; the
+// client turns it into an *APIError after the chain returns. HTTP error responses
+// arrive as an *APIError.
+type Middleware func(next Handler) Handler
+
+// WithMiddleware appends middleware to the client. The first middleware is the
+// outermost: it sees each request first and its response last.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *ClientConfig) {
+		c.Middleware = append(c.Middleware, middleware...)
+	}
+}
+
+// chain wraps handler in middleware so that middleware[0] runs first.
+func chain(handler Handler, middleware []Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// decodeError wraps a JSON unmarshal failure for the response in a *TransportError.
+func (r *Response) decodeError(endpoint string, err error) error {
+	return &TransportError{Endpoint: endpoint, Attempts: r.Attempts, Op: OpDecode, Err: err}
+}
@@ -0,0 +1,149 @@
+package coinmarketcap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestMiddlewareOrderAndMutation(t *testing.T) {
+	var gotTenant, gotConvert string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTenant = r.Header.Get("X-Tenant")
+		gotConvert = r.URL.Query().Get("convert")
+		w.Write([]byte(`{"data": {}, "status": {"error_code": 0, "credit_count": 2}}`))
+	}))
+	defer server.Close()
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				calls = append(calls, name+" before")
+				resp, err := next(ctx, req)
+				calls = append(calls, name+" after")
+				return resp, err
+			}
+		}
+	}
+
+	var status Status
+	tag := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			req.Header.Set("X-Tenant", "acme")
+			req.Query.Set("convert", "EUR")
+			resp, err := next(ctx, req)
+			if err == nil {
+				status = resp.Status
+			}
+			return resp, err
+		}
+	}
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1000)),
+		WithMiddleware(trace("outer"), trace("inner")),
+		WithMiddleware(tag),
+	)
+
+	query := map[string][]string{"convert": {"USD"}}
+	_, err := get[map[string]interface{}](client, context.Background(), "/test", &RequestOptions[map[string]interface{}]{
+		QueryParams: query,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"outer before", "inner before", "inner after", "outer after"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected call order %v, got %v", expected, calls)
+	}
+	if gotTenant != "acme" || gotConvert != "EUR" {
+		t.Errorf("expected mutated request, got tenant %q and convert %q", gotTenant, gotConvert)
+	}
+	if query["convert"][0] != "USD" {
+		t.Error("middleware must not modify the caller's query values")
+	}
+	if status.CreditCount != 2 {
+		t.Errorf("expected middleware to see the decoded status, got %+v", status)
+	}
+}
+
+func TestMiddlewareSeesErrorsAndCacheHits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/fiat/map" {
+			w.Write([]byte(`{"data": [], "status": {"error_code": 0}}`))
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"status": {"error_code": 1001, "error_message": "This API Key is invalid."}}`))
+	}))
+	defer server.Close()
+
+	var errs []error
+	var cached []bool
+	observe := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			resp, err := next(ctx, req)
+			errs = append(errs, err)
+			cached = append(cached, resp != nil && resp.Cached)
+			return resp, err
+		}
+	}
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1000)),
+		WithCache(NewMemoryCache(10)),
+		WithMiddleware(observe),
+	)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetFiatMap(ctx, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := client.GetGlobalMetricsLatest(ctx, nil); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("expected ErrInvalidAPIKey, got %v", err)
+	}
+
+	if !reflect.DeepEqual(cached, []bool{false, true, false}) {
+		t.Errorf("expected the second call to be a cache hit, got %v", cached)
+	}
+	if errs[0] != nil || !errors.Is(errs[2], ErrInvalidAPIKey) {
+		t.Errorf("expected middleware to see the API error, got %v", errs)
+	}
+}
+
+func TestMiddlewareCanShortCircuit(t *testing.T) {
+	client := NewClient(
+		WithBaseURL("http://127.0.0.1:0"),
+		WithMiddleware(func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				return &Response{
+					StatusCode: http.StatusOK,
+					Body:       []byte(`{"data": {"value": 42, "timestamp": "1700000000"}, "status": {"error_code": 0}}`),
+				}, nil
+			}
+		}),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	resp, err := client.GetFearAndGreedLatest(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Data.Value != 42 {
+		t.Errorf("expected the stubbed value, got %d", resp.Data.Value)
+	}
+}
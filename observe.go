@@ -0,0 +1,95 @@
+package coinmarketcap
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// RequestStats describes one API call, as reported to an Observer.
+//
+// Latency covers the whole call. RateLimitWait and BackoffWait are the parts of it
+// spent in the client's rate limiter and sleeping between retries; the remainder was
+// spent on the network and in CoinMarketCap.
+type RequestStats struct {
+	Endpoint      string
+	Start         time.Time
+	Latency       time.Duration
+	RateLimitWait time.Duration
+	BackoffWait   time.Duration
+	Attempts      int // HTTP attempts made; 0 for cache hits and budget refusals
+	StatusCode    int // HTTP status of the last response, 0 when none was received
+	ErrorCode     ErrorCode
+	CreditCount   int   // credits charged; 0 for cache hits
+	BytesReceived int64 // response body bytes read from the wire over all attempts, before decompression
+	Cached        bool
+	Err           error
+}
+
+// Retries returns the number of attempts after the first.
+func (s RequestStats) Retries() int {
+	if s.Attempts <= 1 {
+		return 0
+	}
+	return s.Attempts - 1
+}
+
+// Observer receives telemetry for every request the client serves, including cache hits.
+// RequestStart may return a derived context, for example one carrying a span; it is
+// passed to the request and to RequestEnd. Observers must be safe for concurrent use.
+type Observer interface {
+	RequestStart(ctx context.Context, endpoint string) context.Context
+	RequestEnd(ctx context.Context, stats RequestStats)
+}
+
+// WithObserver adds observers that are called for every request. The
+// cmcobserve package provides a Prometheus collector and a span tracer.
+func WithObserver(observers ...Observer) Option {
+	return func(c *ClientConfig) {
+		c.Observers = append(c.Observers, observers...)
+	}
+}
+
+// finish fills in the outcome of a request.
+func (s *RequestStats) finish(resp *Response, err error) {
+	s.Latency = time.Since(s.Start)
+	s.Err = err
+
+	if resp != nil {
+		s.StatusCode = resp.StatusCode
+		s.ErrorCode = resp.Status.ErrorCode
+		s.Cached = resp.Cached
+		if !resp.Cached {
+			// A cached body still reports the credits of the request that stored it.
+			s.CreditCount = resp.Status.CreditCount
+		}
+		return
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		s.StatusCode = apiErr.StatusCode
+		s.ErrorCode = apiErr.ErrorCode
+	}
+}
+
+// backoff sleeps for d, adding the time slept to BackoffWait.
+func (s *RequestStats) backoff(ctx context.Context, d time.Duration) error {
+	start := time.Now()
+	err := sleepContext(ctx, d)
+	s.BackoffWait += time.Since(start)
+	return err
+}
+
+// countingReader counts the bytes read from a response body.
+type countingReader struct {
+	io.ReadCloser
+	n *int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	*r.n += int64(n)
+	return n, err
+}
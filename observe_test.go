@@ -0,0 +1,115 @@
+package coinmarketcap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+type ctxKey string
+
+// recordingObserver keeps the stats of every request and tags the context in RequestStart.
+type recordingObserver struct {
+	mu     sync.Mutex
+	starts []string
+	stats  []RequestStats
+	tagged []bool
+}
+
+func (o *recordingObserver) RequestStart(ctx context.Context, endpoint string) context.Context {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.starts = append(o.starts, endpoint)
+	return context.WithValue(ctx, ctxKey("observer"), endpoint)
+}
+
+func (o *recordingObserver) RequestEnd(ctx context.Context, stats RequestStats) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.stats = append(o.stats, stats)
+	o.tagged = append(o.tagged, ctx.Value(ctxKey("observer")) == stats.Endpoint)
+}
+
+func TestObserverStats(t *testing.T) {
+	const (
+		busy    = `{"status": {"error_code": 500, "error_message": "busy"}}`
+		invalid = `{"status": {"error_code": 1001, "error_message": "invalid key"}}`
+		ok      = `{"data": {}, "status": {"error_code": 0, "credit_count": 3}}`
+	)
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case r.URL.Path == "/retry" && calls == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(busy))
+		case r.URL.Path == "/denied":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(invalid))
+		default:
+			w.Write([]byte(ok))
+		}
+	}))
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(20)),
+		WithRetryPolicy(RetryPolicy{
+			MaxRetries:           2,
+			BaseDelay:            5 * time.Millisecond,
+			RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		}),
+		WithCache(NewMemoryCache(10)),
+		WithCacheTTL("/retry", time.Minute),
+		WithObserver(observer),
+	)
+
+	ctx := context.Background()
+	for _, endpoint := range []string{"/retry", "/retry", "/denied"} {
+		get[map[string]interface{}](client, ctx, endpoint, nil)
+	}
+
+	if len(observer.stats) != 3 {
+		t.Fatalf("expected 3 observed requests, got %d", len(observer.stats))
+	}
+	for i, tagged := range observer.tagged {
+		if !tagged {
+			t.Errorf("request %d: expected RequestEnd to receive the context from RequestStart", i)
+		}
+	}
+
+	retried := observer.stats[0]
+	if retried.Attempts != 2 || retried.Retries() != 1 || retried.BackoffWait <= 0 {
+		t.Errorf("expected one retry with a backoff wait, got %+v", retried)
+	}
+	if retried.StatusCode != 200 || retried.CreditCount != 3 || retried.BytesReceived != int64(len(busy)+len(ok)) || retried.Err != nil {
+		t.Errorf("unexpected stats for successful request: %+v", retried)
+	}
+	if retried.Latency < retried.BackoffWait+retried.RateLimitWait {
+		t.Errorf("expected latency %v to include waits", retried.Latency)
+	}
+
+	cached := observer.stats[1]
+	if !cached.Cached || cached.Attempts != 0 || cached.BytesReceived != 0 || cached.CreditCount != 0 {
+		t.Errorf("expected a cache hit without network activity, got %+v", cached)
+	}
+
+	denied := observer.stats[2]
+	if denied.StatusCode != 401 || denied.ErrorCode != ErrorCodeAPIKeyInvalid || !errors.Is(denied.Err, ErrInvalidAPIKey) {
+		t.Errorf("unexpected stats for failed request: %+v", denied)
+	}
+	if denied.BytesReceived != int64(len(invalid)) {
+		t.Errorf("expected the error body to be counted, got %d bytes", denied.BytesReceived)
+	}
+	if denied.RateLimitWait <= 0 {
+		t.Error("expected the rate limiter wait to be measured")
+	}
+}
@@ -0,0 +1,317 @@
+package coinmarketcap
+
+import (
+	"context"
+	"sort"
+)
+
+// MaxPageSize is the largest page the start/limit endpoints accept and the
+// default page size used by the Iterate* methods when no Limit is set.
+const MaxPageSize = 5000
+
+// pageFunc fetches the next page of items and reports whether more pages may follow.
+type pageFunc[T any] func(ctx context.Context) (items []T, more bool, err error)
+
+// Pager iterates over the items of a paginated endpoint, fetching pages on demand.
+// Every page is requested through the client, so rate limiting, caching and retries apply.
+//
+//	pager := client.IterateCryptocurrencyMap(ctx, nil).MaxItems(10000)
+//	for pager.Next() {
+//		crypto := pager.Item()
+//		// ...
+//	}
+//	if err := pager.Err(); err != nil {
+//		// handle error
+//	}
+type Pager[T any] struct {
+	ctx      context.Context
+	fetch    pageFunc[T]
+	maxItems int
+	seen     int
+	page     []T
+	index    int
+	current  T
+	more     bool
+	err      error
+}
+
+// newPager creates a pager driven by the given page function.
+func newPager[T any](ctx context.Context, fetch pageFunc[T]) *Pager[T] {
+	return &Pager[T]{
+		ctx:   ctx,
+		fetch: fetch,
+		more:  true,
+	}
+}
+
+// newOffsetPager creates a pager for start/limit endpoints. It advances start by the
+// number of items received and stops on a short or empty page.
+func newOffsetPager[T any](ctx context.Context, start, limit *int, fetch func(ctx context.Context, start, limit int) ([]T, error)) *Pager[T] {
+	next := 1
+	if start != nil && *start > 0 {
+		next = *start
+	}
+	pageSize := MaxPageSize
+	if limit != nil && *limit > 0 {
+		pageSize = *limit
+	}
+
+	p := &Pager[T]{ctx: ctx, more: true}
+	p.fetch = func(ctx context.Context) ([]T, bool, error) {
+		size := pageSize
+		if p.maxItems > 0 && p.maxItems-p.seen < size {
+			size = p.maxItems - p.seen
+		}
+
+		items, err := fetch(ctx, next, size)
+		if err != nil {
+			return nil, false, err
+		}
+		next += len(items)
+
+		return items, len(items) >= size, nil
+	}
+
+	return p
+}
+
+// MaxItems caps the total number of items the pager yields. Zero means no cap.
+func (p *Pager[T]) MaxItems(n int) *Pager[T] {
+	p.maxItems = n
+	return p
+}
+
+// Next advances to the next item, fetching a new page when needed.
+// It returns false when the items are exhausted, the cap is reached or an error occurs.
+func (p *Pager[T]) Next() bool {
+	if p.err != nil || (p.maxItems > 0 && p.seen >= p.maxItems) {
+		return false
+	}
+
+	for p.index >= len(p.page) {
+		if !p.more {
+			return false
+		}
+		if err := p.ctx.Err(); err != nil {
+			p.err = err
+			return false
+		}
+
+		page, more, err := p.fetch(p.ctx)
+		if err != nil {
+			p.err = err
+			return false
+		}
+
+		p.page = page
+		p.index = 0
+		p.more = more && len(page) > 0
+	}
+
+	p.current = p.page[p.index]
+	p.index++
+	p.seen++
+	return true
+}
+
+// Item returns the current item. It is only valid after Next returned true.
+func (p *Pager[T]) Item() T {
+	return p.current
+}
+
+// Err returns the first error encountered while fetching pages.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// All drains the pager and returns every remaining item.
+func (p *Pager[T]) All() ([]T, error) {
+	var items []T
+	for p.Next() {
+		items = append(items, p.Item())
+	}
+	return items, p.Err()
+}
+
+// IterateCryptocurrencyMap pages through /v1/cryptocurrency/map.
+func (c *Client) IterateCryptocurrencyMap(ctx context.Context, opts *CryptocurrencyMapOptions) *Pager[CryptocurrencyMap] {
+	var o CryptocurrencyMapOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	return newOffsetPager(ctx, o.Start, o.Limit, func(ctx context.Context, start, limit int) ([]CryptocurrencyMap, error) {
+		o.Start, o.Limit = &start, &limit
+		resp, err := c.GetCryptocurrencyMap(ctx, &o)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Data, nil
+	})
+}
+
+// IterateCryptocurrencyListingsLatest pages through /v1/cryptocurrency/listings/latest.
+func (c *Client) IterateCryptocurrencyListingsLatest(ctx context.Context, opts *CryptocurrencyListingsOptions) *Pager[CryptocurrencyListing] {
+	var o CryptocurrencyListingsOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	return newOffsetPager(ctx, o.Start, o.Limit, func(ctx context.Context, start, limit int) ([]CryptocurrencyListing, error) {
+		o.Start, o.Limit = &start, &limit
+		resp, err := c.GetCryptocurrencyListingsLatest(ctx, &o)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Data, nil
+	})
+}
+
+// IterateCryptocurrencyCategories pages through /v1/cryptocurrency/categories.
+func (c *Client) IterateCryptocurrencyCategories(ctx context.Context, opts *CryptocurrencyCategoriesOptions) *Pager[Category] {
+	var o CryptocurrencyCategoriesOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	return newOffsetPager(ctx, o.Start, o.Limit, func(ctx context.Context, start, limit int) ([]Category, error) {
+		o.Start, o.Limit = &start, &limit
+		resp, err := c.GetCryptocurrencyCategories(ctx, &o)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Data, nil
+	})
+}
+
+// IterateCryptocurrencyAirdrops pages through /v1/cryptocurrency/airdrops.
+func (c *Client) IterateCryptocurrencyAirdrops(ctx context.Context, opts *CryptocurrencyAirdropsOptions) *Pager[Airdrop] {
+	var o CryptocurrencyAirdropsOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	return newOffsetPager(ctx, o.Start, o.Limit, func(ctx context.Context, start, limit int) ([]Airdrop, error) {
+		o.Start, o.Limit = &start, &limit
+		resp, err := c.GetCryptocurrencyAirdrops(ctx, &o)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Data, nil
+	})
+}
+
+// IterateCryptocurrencyMarketPairsLatest pages through /v2/cryptocurrency/market-pairs/latest.
+// Pairs from every key of the response map are yielded in key order.
+func (c *Client) IterateCryptocurrencyMarketPairsLatest(ctx context.Context, opts *CryptocurrencyMarketPairsOptions) *Pager[MarketPair] {
+	var o CryptocurrencyMarketPairsOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	return newOffsetPager(ctx, o.Start, o.Limit, func(ctx context.Context, start, limit int) ([]MarketPair, error) {
+		o.Start, o.Limit = &start, &limit
+		resp, err := c.GetCryptocurrencyMarketPairsLatest(ctx, &o)
+		if err != nil {
+			return nil, err
+		}
+
+		keys := make([]string, 0, len(resp.Data))
+		for key := range resp.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var pairs []MarketPair
+		for _, key := range keys {
+			pairs = append(pairs, resp.Data[key]...)
+		}
+		return pairs, nil
+	})
+}
+
+// IterateExchangeMap pages through /v1/exchange/map.
+func (c *Client) IterateExchangeMap(ctx context.Context, opts *ExchangeMapOptions) *Pager[ExchangeMap] {
+	var o ExchangeMapOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	return newOffsetPager(ctx, o.Start, o.Limit, func(ctx context.Context, start, limit int) ([]ExchangeMap, error) {
+		o.Start, o.Limit = &start, &limit
+		resp, err := c.GetExchangeMap(ctx, &o)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Data, nil
+	})
+}
+
+// IterateExchangeListingsLatest pages through /v1/exchange/listings/latest.
+func (c *Client) IterateExchangeListingsLatest(ctx context.Context, opts *ExchangeListingsOptions) *Pager[ExchangeListing] {
+	var o ExchangeListingsOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	return newOffsetPager(ctx, o.Start, o.Limit, func(ctx context.Context, start, limit int) ([]ExchangeListing, error) {
+		o.Start, o.Limit = &start, &limit
+		resp, err := c.GetExchangeListingsLatest(ctx, &o)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Data, nil
+	})
+}
+
+// IterateFearAndGreedHistorical pages through /v3/fear-and-greed/historical, newest readings first.
+func (c *Client) IterateFearAndGreedHistorical(ctx context.Context, opts *FearAndGreedHistoricalOptions) *Pager[FearAndGreed] {
+	var o FearAndGreedHistoricalOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Limit == nil {
+		o.Limit = Int(FearAndGreedMaxLimit)
+	}
+
+	return newOffsetPager(ctx, o.Start, o.Limit, func(ctx context.Context, start, limit int) ([]FearAndGreed, error) {
+		o.Start, o.Limit = &start, &limit
+		resp, err := c.GetFearAndGreedHistorical(ctx, &o)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Data, nil
+	})
+}
+
+// IterateContentPostsTop pages through /v1/content/posts/top by following the last_score cursor.
+func (c *Client) IterateContentPostsTop(ctx context.Context, opts *ContentPostsOptions) *Pager[Post] {
+	return c.iteratePosts(ctx, opts, c.GetContentPostsTop)
+}
+
+// IterateContentPostsLatest pages through /v1/content/posts/latest by following the last_score cursor.
+func (c *Client) IterateContentPostsLatest(ctx context.Context, opts *ContentPostsOptions) *Pager[Post] {
+	return c.iteratePosts(ctx, opts, c.GetContentPostsLatest)
+}
+
+// iteratePosts builds a cursor pager over a posts endpoint. Iteration stops when the
+// API returns no cursor, repeats the previous cursor or returns an empty page.
+func (c *Client) iteratePosts(ctx context.Context, opts *ContentPostsOptions, fetch func(context.Context, *ContentPostsOptions) (*APIResponse[PostList], error)) *Pager[Post] {
+	var o ContentPostsOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	return newPager(ctx, func(ctx context.Context) ([]Post, bool, error) {
+		resp, err := fetch(ctx, &o)
+		if err != nil {
+			return nil, false, err
+		}
+
+		cursor := resp.Data.LastScore
+		more := cursor != "" && (o.LastScore == nil || *o.LastScore != cursor)
+		o.LastScore = &cursor
+
+		return resp.Data.List, more, nil
+	})
+}
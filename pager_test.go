@@ -0,0 +1,190 @@
+package coinmarketcap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// newMapServer serves total cryptocurrencies from /v1/cryptocurrency/map honoring start and limit.
+func newMapServer(total int, limits *[]int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if limits != nil {
+			*limits = append(*limits, limit)
+		}
+
+		data := []CryptocurrencyMap{}
+		for id := start; id < start+limit && id <= total; id++ {
+			data = append(data, CryptocurrencyMap{ID: id, Symbol: "C" + strconv.Itoa(id)})
+		}
+
+		json.NewEncoder(w).Encode(APIResponse[[]CryptocurrencyMap]{Data: data})
+	}))
+}
+
+func TestPagerAdvancesUntilShortPage(t *testing.T) {
+	var limits []int
+	server := newMapServer(7, &limits)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimit(rate.Limit(1000)))
+
+	items, err := client.IterateCryptocurrencyMap(context.Background(), &CryptocurrencyMapOptions{
+		Limit: Int(3),
+	}).All()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(items) != 7 {
+		t.Fatalf("expected 7 items, got %d", len(items))
+	}
+	for i, item := range items {
+		if item.ID != i+1 {
+			t.Errorf("expected ID %d at index %d, got %d", i+1, i, item.ID)
+		}
+	}
+	if len(limits) != 3 {
+		t.Errorf("expected 3 page requests, got %d", len(limits))
+	}
+}
+
+func TestPagerMaxItems(t *testing.T) {
+	var limits []int
+	server := newMapServer(100, &limits)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimit(rate.Limit(1000)))
+
+	opts := &CryptocurrencyMapOptions{Start: Int(11), Limit: Int(3)}
+	pager := client.IterateCryptocurrencyMap(context.Background(), opts).MaxItems(5)
+
+	var ids []int
+	for pager.Next() {
+		ids = append(ids, pager.Item().ID)
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []int{11, 12, 13, 14, 15}
+	if len(ids) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, ids)
+	}
+	for i := range expected {
+		if ids[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, ids)
+			break
+		}
+	}
+
+	// The final page should only request what is left under the cap.
+	if len(limits) != 2 || limits[1] != 2 {
+		t.Errorf("expected page limits [3 2], got %v", limits)
+	}
+
+	// The caller's options must not be mutated by iteration.
+	if *opts.Start != 11 || *opts.Limit != 3 {
+		t.Errorf("expected options to be left untouched, got start=%d limit=%d", *opts.Start, *opts.Limit)
+	}
+}
+
+func TestPagerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status": {"error_code": 400, "error_message": "bad value"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimit(rate.Limit(1000)))
+
+	pager := client.IterateExchangeMap(context.Background(), nil)
+	if pager.Next() {
+		t.Fatal("expected Next to return false")
+	}
+	if _, ok := pager.Err().(*APIError); !ok {
+		t.Errorf("expected APIError, got %T", pager.Err())
+	}
+}
+
+func TestGetFearAndGreedRange(t *testing.T) {
+	// Ten daily readings, newest first, ending on 2024-01-10.
+	end := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		var entries []string
+		for i := start - 1; i < start-1+limit && i < 10; i++ {
+			ts := end.AddDate(0, 0, -i).Unix()
+			entries = append(entries, fmt.Sprintf(`{"timestamp": "%d", "value": %d, "value_classification": "Neutral"}`, ts, 50+i))
+		}
+		fmt.Fprintf(w, `{"data": [%s], "status": {"error_code": 0}}`, strings.Join(entries, ","))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimit(rate.Limit(1000)))
+
+	from := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	readings, err := client.GetFearAndGreedRange(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(readings) != 4 {
+		t.Fatalf("expected 4 readings, got %d", len(readings))
+	}
+	for i, reading := range readings {
+		expected := from.AddDate(0, 0, i)
+		if !reading.Timestamp.Equal(expected) {
+			t.Errorf("reading %d: expected %v, got %v", i, expected, reading.Timestamp)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected a single page request, got %d", requests)
+	}
+}
+
+func TestIterateContentPostsFollowsCursor(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"list": [{"post_id": "1"}, {"post_id": "2"}], "last_score": "s1"}`,
+		"s1": `{"list": [{"post_id": "3"}], "last_score": "s2"}`,
+		"s2": `{"list": [], "last_score": ""}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Query().Get("last_score")]
+		if !ok {
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("last_score"))
+			page = `{"list": []}`
+		}
+		fmt.Fprintf(w, `{"data": %s, "status": {"error_code": 0}}`, page)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimit(rate.Limit(1000)))
+
+	posts, err := client.IterateContentPostsTop(context.Background(), &ContentPostsOptions{Limit: Int(2)}).All()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(posts) != 3 || posts[2].PostID != "3" {
+		t.Errorf("expected posts 1-3, got %+v", posts)
+	}
+}
@@ -0,0 +1,254 @@
+package coinmarketcap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Plan is a CoinMarketCap API subscription tier. Higher tiers include every endpoint
+// of the lower ones.
+type Plan int
+
+// Plan tiers. PlanUnknown, the default, disables plan checks.
+const (
+	PlanUnknown Plan = iota
+	PlanBasic
+	PlanHobbyist
+	PlanStartup
+	PlanStandard
+	PlanProfessional
+	PlanEnterprise
+)
+
+var planNames = map[Plan]string{
+	PlanUnknown:      "Unknown",
+	PlanBasic:        "Basic",
+	PlanHobbyist:     "Hobbyist",
+	PlanStartup:      "Startup",
+	PlanStandard:     "Standard",
+	PlanProfessional: "Professional",
+	PlanEnterprise:   "Enterprise",
+}
+
+// String returns the plan's name as CoinMarketCap writes it.
+func (p Plan) String() string {
+	if name, ok := planNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("Plan(%d)", int(p))
+}
+
+// ParsePlan returns the plan with the given name, ignoring case.
+func ParsePlan(name string) (Plan, bool) {
+	for plan, planName := range planNames {
+		if plan != PlanUnknown && strings.EqualFold(name, planName) {
+			return plan, true
+		}
+	}
+	return PlanUnknown, false
+}
+
+// Monthly credit limits of the self-serve plans, used to recognize a plan from GetKeyInfo.
+var planCreditLimits = []struct {
+	plan    Plan
+	credits int
+}{
+	{PlanBasic, 10_000},
+	{PlanHobbyist, 110_000},
+	{PlanStartup, 300_000},
+	{PlanStandard, 1_200_000},
+	{PlanProfessional, 3_000_000},
+}
+
+// PlanFromKeyInfo recognizes the plan of a key from GetKeyInfo, by its name when
+// present and otherwise by its monthly credit limit. Limits above Professional are
+// reported as PlanEnterprise.
+func PlanFromKeyInfo(info *KeyInfo) Plan {
+	if info == nil {
+		return PlanUnknown
+	}
+	if plan, ok := ParsePlan(info.Plan.Name); ok {
+		return plan
+	}
+
+	limit := info.Plan.CreditLimitMonthly
+	if limit <= 0 {
+		return PlanUnknown
+	}
+	for _, tier := range planCreditLimits {
+		if limit <= tier.credits {
+			return tier.plan
+		}
+	}
+	return PlanEnterprise
+}
+
+// defaultEndpointPlans lists the lowest plan that includes each endpoint, following
+// the CoinMarketCap API documentation.
+var defaultEndpointPlans = map[string]Plan{
+	"/v1/cryptocurrency/map":                            PlanBasic,
+	"/v2/cryptocurrency/info":                           PlanBasic,
+	"/v1/cryptocurrency/listings/latest":                PlanBasic,
+	"/v1/cryptocurrency/listings/new":                   PlanBasic,
+	"/v2/cryptocurrency/quotes/latest":                  PlanBasic,
+	"/v1/cryptocurrency/categories":                     PlanBasic,
+	"/v1/cryptocurrency/category":                       PlanBasic,
+	"/v1/cryptocurrency/airdrops":                       PlanBasic,
+	"/v1/cryptocurrency/airdrop":                        PlanBasic,
+	"/v1/cryptocurrency/listings/historical":            PlanHobbyist,
+	"/v2/cryptocurrency/quotes/historical":              PlanHobbyist,
+	"/v3/cryptocurrency/quotes/historical":              PlanHobbyist,
+	"/v2/cryptocurrency/ohlcv/historical":               PlanHobbyist,
+	"/v2/cryptocurrency/ohlcv/latest":                   PlanStartup,
+	"/v2/cryptocurrency/price-performance-stats/latest": PlanStartup,
+	"/v1/cryptocurrency/trending/latest":                PlanStartup,
+	"/v1/cryptocurrency/trending/most-visited":          PlanStartup,
+	"/v1/cryptocurrency/trending/gainers-losers":        PlanStartup,
+	"/v2/cryptocurrency/market-pairs/latest":            PlanStandard,
+
+	"/v1/exchange/map":                 PlanBasic,
+	"/v1/exchange/info":                PlanBasic,
+	"/v1/exchange/assets":              PlanBasic,
+	"/v1/exchange/listings/latest":     PlanStandard,
+	"/v1/exchange/quotes/latest":       PlanStandard,
+	"/v1/exchange/quotes/historical":   PlanStandard,
+	"/v1/exchange/market-pairs/latest": PlanStandard,
+
+	"/v1/global-metrics/quotes/latest":     PlanBasic,
+	"/v1/global-metrics/quotes/historical": PlanHobbyist,
+
+	"/v1/fiat/map":                        PlanBasic,
+	"/v1/key/info":                        PlanBasic,
+	"/v2/tools/price-conversion":          PlanBasic,
+	"/v1/tools/postman":                   PlanBasic,
+	"/v1/blockchain/statistics/latest":    PlanBasic,
+	"/v3/fear-and-greed/latest":           PlanBasic,
+	"/v3/fear-and-greed/historical":       PlanBasic,
+	"/v3/index/cmc100-latest":             PlanBasic,
+	"/v3/index/cmc100-historical":         PlanBasic,
+	"/v3/index/cmc20-latest":              PlanBasic,
+	"/v3/index/cmc20-historical":          PlanBasic,
+	"/v3/index/altcoin-season-latest":     PlanBasic,
+	"/v3/index/altcoin-season-historical": PlanBasic,
+
+	"/v1/content/latest":           PlanBasic,
+	"/v1/content/posts/top":        PlanBasic,
+	"/v1/content/posts/latest":     PlanBasic,
+	"/v1/content/posts/comments":   PlanBasic,
+	"/v1/community/trending/topic": PlanBasic,
+	"/v1/community/trending/token": PlanBasic,
+
+	"/v4/dex/networks/list":          PlanBasic,
+	"/v4/dex/listings/quotes":        PlanBasic,
+	"/v4/dex/spot-pairs/latest":      PlanBasic,
+	"/v4/dex/pairs/quotes/latest":    PlanBasic,
+	"/v4/dex/pairs/ohlcv/latest":     PlanBasic,
+	"/v4/dex/pairs/ohlcv/historical": PlanBasic,
+	"/v4/dex/pairs/trade/latest":     PlanBasic,
+}
+
+// DefaultEndpointPlan returns the lowest plan that includes an endpoint according to the
+// CoinMarketCap API documentation, or PlanUnknown for endpoints it does not list. Plans
+// also limit how far back historical endpoints reach; that is left to the API.
+func DefaultEndpointPlan(endpoint string) Plan {
+	return defaultEndpointPlans[endpoint]
+}
+
+// PlanError is returned, without sending a request, when an endpoint needs a higher
+// plan than the client's. errors.Is matches it with ErrPlanUnauthorized.
+type PlanError struct {
+	Endpoint string
+	Plan     Plan // the client's plan
+	Required Plan // the lowest plan that includes the endpoint
+}
+
+// Error implements the error interface.
+func (e *PlanError) Error() string {
+	return fmt.Sprintf("%s requires the %s plan or higher (current plan: %s)", e.Endpoint, e.Required, e.Plan)
+}
+
+// Is reports whether target is ErrPlanUnauthorized.
+func (e *PlanError) Is(target error) bool {
+	return target == ErrPlanUnauthorized
+}
+
+// WithPlan sets the plan of the API key. Calls to endpoints the plan does not include
+// then fail with a *PlanError instead of costing a round trip. See also DetectPlan.
+func WithPlan(plan Plan) Option {
+	return func(c *ClientConfig) {
+		c.Plan = plan
+	}
+}
+
+// WithEndpointPlan overrides the lowest plan that includes an endpoint
+// (e.g. "/v2/cryptocurrency/ohlcv/latest"), for entries that are missing from or
+// disagree with DefaultEndpointPlan. PlanUnknown removes the check.
+func WithEndpointPlan(endpoint string, plan Plan) Option {
+	return func(c *ClientConfig) {
+		if c.EndpointPlans == nil {
+			c.EndpointPlans = make(map[string]Plan)
+		}
+		c.EndpointPlans[endpoint] = plan
+	}
+}
+
+// Plan returns the client's plan.
+func (c *Client) Plan() Plan {
+	return Plan(c.plan.Load())
+}
+
+// SetPlan changes the client's plan.
+func (c *Client) SetPlan(plan Plan) {
+	c.plan.Store(int32(plan))
+}
+
+// DetectPlan sets the client's plan from GetKeyInfo, which costs no credits, and returns it.
+func (c *Client) DetectPlan(ctx context.Context) (Plan, error) {
+	resp, err := c.GetKeyInfo(ctx)
+	if err != nil {
+		return PlanUnknown, err
+	}
+
+	plan := PlanFromKeyInfo(&resp.Data)
+	c.SetPlan(plan)
+	return plan, nil
+}
+
+// RequiredPlan returns the lowest plan that includes endpoint, honoring overrides.
+// Endpoints without an entry return PlanUnknown.
+func (c *Client) RequiredPlan(endpoint string) Plan {
+	if plan, ok := c.endpointPlans[endpoint]; ok {
+		return plan
+	}
+	return DefaultEndpointPlan(endpoint)
+}
+
+// Supports reports whether the client's plan includes endpoint. It is true for every
+// endpoint while the plan is unknown.
+func (c *Client) Supports(endpoint string) bool {
+	return c.checkPlan(endpoint) == nil
+}
+
+// Capabilities reports, for every endpoint with a known plan, whether the client's
+// plan includes it.
+func (c *Client) Capabilities() map[string]bool {
+	capabilities := make(map[string]bool, len(defaultEndpointPlans))
+	for endpoint := range defaultEndpointPlans {
+		capabilities[endpoint] = c.Supports(endpoint)
+	}
+	for endpoint := range c.endpointPlans {
+		capabilities[endpoint] = c.Supports(endpoint)
+	}
+	return capabilities
+}
+
+// checkPlan returns a *PlanError if the client's plan does not include endpoint.
+func (c *Client) checkPlan(endpoint string) error {
+	plan := c.Plan()
+	required := c.RequiredPlan(endpoint)
+	if plan == PlanUnknown || plan >= required {
+		return nil
+	}
+	return &PlanError{Endpoint: endpoint, Plan: plan, Required: required}
+}
@@ -0,0 +1,168 @@
+package coinmarketcap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+func TestPlanFromKeyInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		plan    string
+		monthly int
+		want    Plan
+	}{
+		{"by name", "Professional", 0, PlanProfessional},
+		{"name ignores case", "hobbyist", 10_000, PlanHobbyist},
+		{"basic limit", "", 10_000, PlanBasic},
+		{"startup limit", "", 300_000, PlanStartup},
+		{"standard limit", "", 1_200_000, PlanStandard},
+		{"above professional", "", 30_000_000, PlanEnterprise},
+		{"unknown name falls back to limit", "custom", 110_000, PlanHobbyist},
+		{"no information", "", 0, PlanUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var info KeyInfo
+			info.Plan.Name = tt.plan
+			info.Plan.CreditLimitMonthly = tt.monthly
+			if got := PlanFromKeyInfo(&info); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if plan, ok := ParsePlan("STANDARD"); !ok || plan != PlanStandard || plan.String() != "Standard" {
+		t.Errorf("expected ParsePlan to find Standard, got %v", plan)
+	}
+	if _, ok := ParsePlan("unknown"); ok {
+		t.Error("expected ParsePlan to reject the unknown plan")
+	}
+}
+
+func TestPlanGating(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"data": [], "status": {"error_code": 0}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1000)),
+		WithPlan(PlanBasic),
+		WithEndpointPlan("/v1/cryptocurrency/categories", PlanStandard),
+	)
+	ctx := context.Background()
+
+	_, err := client.GetCryptocurrencyOHLCVHistorical(ctx, &CryptocurrencyOHLCVHistoricalOptions{Symbol: []string{"BTC"}})
+	if !errors.Is(err, ErrPlanUnauthorized) {
+		t.Fatalf("expected ErrPlanUnauthorized, got %v", err)
+	}
+	var planErr *PlanError
+	if !errors.As(err, &planErr) || planErr.Required != PlanHobbyist || planErr.Plan != PlanBasic {
+		t.Errorf("unexpected plan error %+v", planErr)
+	}
+	if _, err := client.GetCryptocurrencyCategories(ctx, nil); !errors.As(err, &planErr) || planErr.Required != PlanStandard {
+		t.Errorf("expected the override to apply, got %v", err)
+	}
+	if requests.Load() != 0 {
+		t.Errorf("expected gated calls not to be sent, got %d requests", requests.Load())
+	}
+
+	if _, err := client.GetFiatMap(ctx, nil); err != nil {
+		t.Fatalf("unexpected error for a Basic endpoint: %v", err)
+	}
+
+	capabilities := client.Capabilities()
+	if !capabilities["/v1/fiat/map"] || capabilities["/v2/cryptocurrency/market-pairs/latest"] || capabilities["/v1/cryptocurrency/categories"] {
+		t.Errorf("unexpected capabilities %v", capabilities)
+	}
+
+	client.SetPlan(PlanStandard)
+	if !client.Supports("/v2/cryptocurrency/market-pairs/latest") || !client.Supports("/v1/exchange/unknown") {
+		t.Error("expected the Standard plan to include market pairs and unlisted endpoints")
+	}
+}
+
+func TestDetectPlan(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"plan": {"credit_limit_monthly": 300000}}, "status": {"error_code": 0}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimit(rate.Limit(1000)))
+	if client.Plan() != PlanUnknown || !client.Supports("/v2/cryptocurrency/market-pairs/latest") {
+		t.Fatal("expected no plan checks before detection")
+	}
+
+	plan, err := client.DetectPlan(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan != PlanStartup || client.Plan() != PlanStartup {
+		t.Errorf("expected Startup, got %v", plan)
+	}
+	if client.RequiredPlan("/v2/cryptocurrency/ohlcv/latest") != PlanStartup || client.Supports("/v1/exchange/listings/latest") {
+		t.Error("expected the detected plan to gate Standard endpoints")
+	}
+}
+
+// TestEndpointPlansCoverClient calls every Get method and checks that the documented
+// plans list each endpoint it requests.
+func TestEndpointPlansCoverClient(t *testing.T) {
+	errStop := errors.New("stop")
+	var endpoints []string
+	client := NewClient(WithMiddleware(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			endpoints = append(endpoints, req.Endpoint)
+			return nil, errStop
+		}
+	}))
+
+	value := reflect.ValueOf(client)
+	for i := 0; i < value.NumMethod(); i++ {
+		method := value.Type().Method(i)
+		if !strings.HasPrefix(method.Name, "Get") {
+			continue
+		}
+
+		indexes := []Index{""}
+		for j := 1; j < method.Type.NumIn(); j++ {
+			if method.Type.In(j) == reflect.TypeOf(Index("")) {
+				indexes = []Index{IndexCMC100, IndexCMC20, IndexAltcoinSeason}
+			}
+		}
+		for _, index := range indexes {
+			args := []reflect.Value{reflect.ValueOf(context.Background())}
+			for j := 2; j < method.Type.NumIn(); j++ {
+				arg := reflect.New(method.Type.In(j)).Elem()
+				if arg.Type() == reflect.TypeOf(index) {
+					arg.Set(reflect.ValueOf(index))
+				}
+				args = append(args, arg)
+			}
+
+			endpoints = endpoints[:0]
+			value.Method(i).Call(args)
+			if len(endpoints) == 0 {
+				t.Errorf("%s: expected a request", method.Name)
+			}
+			for _, endpoint := range endpoints {
+				if DefaultEndpointPlan(endpoint) == PlanUnknown {
+					t.Errorf("%s: no plan listed for %s", method.Name, endpoint)
+				}
+			}
+		}
+	}
+}
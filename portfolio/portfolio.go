@@ -0,0 +1,305 @@
+// Package portfolio values holdings of many assets in one or more currencies:
+//
+//	p := portfolio.New()
+//	p.Add(1, 0.5)          // by CoinMarketCap ID
+//	p.AddSymbol("ETH", 10) // by symbol
+//
+//	valuation, err := p.Value(ctx, client, "USD", "EUR")
+//	fmt.Println(valuation.Totals["EUR"].Value)
+//
+// Symbols are not unique; a symbol holding is valued as the active asset with the best
+// rank. Use IDs, for example from the cmcresolve package, when that matters.
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// Holding is an amount of one asset, identified by ID or by symbol.
+type Holding struct {
+	ID     int    // zero for symbol holdings
+	Symbol string // empty for ID holdings
+	Amount float64
+}
+
+// String returns the symbol, or the ID as "#1027".
+func (h Holding) String() string {
+	if h.Symbol != "" {
+		return h.Symbol
+	}
+	return "#" + strconv.Itoa(h.ID)
+}
+
+// Portfolio maps assets to the amounts held.
+type Portfolio struct {
+	IDs     map[int]float64
+	Symbols map[string]float64 // upper case
+}
+
+// New creates an empty portfolio.
+func New() *Portfolio {
+	return &Portfolio{IDs: make(map[int]float64), Symbols: make(map[string]float64)}
+}
+
+// Add adds amount of the asset with the given CoinMarketCap ID. Negative amounts reduce
+// the holding.
+func (p *Portfolio) Add(id int, amount float64) {
+	if p.IDs == nil {
+		p.IDs = make(map[int]float64)
+	}
+	p.IDs[id] += amount
+}
+
+// AddSymbol adds amount of the asset with the given symbol, ignoring case.
+func (p *Portfolio) AddSymbol(symbol string, amount float64) {
+	if p.Symbols == nil {
+		p.Symbols = make(map[string]float64)
+	}
+	p.Symbols[strings.ToUpper(symbol)] += amount
+}
+
+// Holdings returns the holdings, IDs in ascending order followed by sorted symbols.
+func (p *Portfolio) Holdings() []Holding {
+	holdings := make([]Holding, 0, len(p.IDs)+len(p.Symbols))
+	for id, amount := range p.IDs {
+		holdings = append(holdings, Holding{ID: id, Amount: amount})
+	}
+	for symbol, amount := range p.Symbols {
+		holdings = append(holdings, Holding{Symbol: strings.ToUpper(symbol), Amount: amount})
+	}
+	sort.Slice(holdings, func(i, j int) bool {
+		a, b := holdings[i], holdings[j]
+		if (a.Symbol == "") != (b.Symbol == "") {
+			return a.Symbol == ""
+		}
+		if a.Symbol == "" {
+			return a.ID < b.ID
+		}
+		return a.Symbol < b.Symbol
+	})
+	return holdings
+}
+
+// Value values the portfolio at the latest quotes in each convert currency, USD if none
+// are given. Quotes are fetched in batches with GetCryptocurrencyQuotesLatest; holdings
+// the API does not know are reported in Missing.
+func (p *Portfolio) Value(ctx context.Context, client *cmc.Client, convert ...string) (*Valuation, error) {
+	currencies, err := resolveCurrencies(ctx, client, convert)
+	if err != nil {
+		return nil, err
+	}
+	symbols, ids := convertParams(currencies)
+	skipInvalid := true
+
+	return p.value(currencies, time.Time{}, func(byID []int, bySymbol []string) (map[string]asset, error) {
+		resp, err := client.GetCryptocurrencyQuotesLatest(ctx, &cmc.CryptocurrencyQuotesOptions{
+			ID:          byID,
+			Symbol:      bySymbol,
+			Convert:     symbols,
+			ConvertID:   ids,
+			SkipInvalid: &skipInvalid,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		assets := make(map[string]asset, len(resp.Data))
+		for key, entries := range resp.Data {
+			if entry, ok := best(entries); ok {
+				assets[strings.ToUpper(key)] = asset{
+					id:     entry.ID,
+					symbol: entry.Symbol,
+					name:   entry.Name,
+					time:   entry.LastUpdated,
+					quotes: entry.Quote,
+				}
+			}
+		}
+		return assets, nil
+	})
+}
+
+// ValueAt values the portfolio at a past time with the historical quotes endpoint, using
+// the last quote at or before at. The P&L fields are only filled when the historical
+// quotes carry percent changes.
+func (p *Portfolio) ValueAt(ctx context.Context, client *cmc.Client, at time.Time, convert ...string) (*Valuation, error) {
+	currencies, err := resolveCurrencies(ctx, client, convert)
+	if err != nil {
+		return nil, err
+	}
+	symbols, ids := convertParams(currencies)
+	timeStart := at.Add(-time.Hour).UTC().Format(time.RFC3339)
+	timeEnd := at.UTC().Format(time.RFC3339)
+	interval := cmc.Interval5m
+
+	return p.value(currencies, at, func(byID []int, bySymbol []string) (map[string]asset, error) {
+		resp, err := client.GetCryptocurrencyQuotesHistorical(ctx, &cmc.CryptocurrencyQuotesHistoricalOptions{
+			ID:        byID,
+			Symbol:    bySymbol,
+			TimeStart: &timeStart,
+			TimeEnd:   &timeEnd,
+			Interval:  &interval,
+			Convert:   symbols,
+			ConvertID: ids,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		assets := make(map[string]asset, len(resp.Data))
+		for key, entries := range resp.Data {
+			entry, ok := withHistory(entries)
+			if !ok {
+				continue
+			}
+			point, _ := lastAt(entry.Quotes, at)
+			assets[strings.ToUpper(key)] = asset{
+				id:     entry.ID,
+				symbol: entry.Symbol,
+				name:   entry.Name,
+				time:   point.Timestamp,
+				quotes: point.Quote,
+			}
+		}
+		return assets, nil
+	})
+}
+
+// asset is the quote data of one holding, from either endpoint.
+type asset struct {
+	id     int
+	symbol string
+	name   string
+	time   time.Time
+	quotes map[string]*cmc.Quote
+}
+
+// value fetches quotes for ID and symbol holdings separately, as the API takes only one
+// kind per request, and builds the valuation.
+func (p *Portfolio) value(currencies []Currency, at time.Time, fetch func([]int, []string) (map[string]asset, error)) (*Valuation, error) {
+	holdings := p.Holdings()
+	var ids []int
+	var symbols []string
+	for _, h := range holdings {
+		if h.Symbol == "" {
+			ids = append(ids, h.ID)
+		} else {
+			symbols = append(symbols, h.Symbol)
+		}
+	}
+
+	assets := make(map[string]asset)
+	if len(ids) > 0 {
+		found, err := fetch(ids, nil)
+		if err != nil {
+			return nil, fmt.Errorf("portfolio: %w", err)
+		}
+		for key, a := range found {
+			assets["#"+key] = a
+		}
+	}
+	if len(symbols) > 0 {
+		found, err := fetch(nil, symbols)
+		if err != nil {
+			return nil, fmt.Errorf("portfolio: %w", err)
+		}
+		for key, a := range found {
+			assets[key] = a
+		}
+	}
+
+	v := newValuation(currencies, at)
+	for _, h := range holdings {
+		a, ok := assets[h.String()]
+		if !ok {
+			v.Missing = append(v.Missing, h)
+			continue
+		}
+		if a.id == 0 {
+			a.id = h.ID
+		}
+		if a.symbol == "" {
+			a.symbol = h.Symbol
+		}
+		v.add(h, a)
+	}
+	v.finish()
+	return v, nil
+}
+
+// best picks the active asset with the best rank from the candidates of a symbol.
+func best(entries []cmc.CryptocurrencyQuote) (cmc.CryptocurrencyQuote, bool) {
+	if len(entries) == 0 {
+		return cmc.CryptocurrencyQuote{}, false
+	}
+
+	rank := func(e cmc.CryptocurrencyQuote) (bool, int) {
+		active := e.IsActive == nil || *e.IsActive == 1
+		if e.CMCRank == nil || *e.CMCRank <= 0 {
+			return active, int(^uint(0) >> 1)
+		}
+		return active, *e.CMCRank
+	}
+
+	choice := entries[0]
+	for _, e := range entries[1:] {
+		activeE, rankE := rank(e)
+		activeC, rankC := rank(choice)
+		if activeE && !activeC || activeE == activeC && rankE < rankC {
+			choice = e
+		}
+	}
+	return choice, true
+}
+
+// withHistory picks the first active asset with quotes from the candidates of a symbol,
+// or the first inactive one if there is none.
+func withHistory(entries []cmc.CryptocurrencyQuotesHistorical) (cmc.CryptocurrencyQuotesHistorical, bool) {
+	var inactive *cmc.CryptocurrencyQuotesHistorical
+	for i := range entries {
+		e := &entries[i]
+		if len(e.Quotes) == 0 {
+			continue
+		}
+		if e.IsActive == nil || *e.IsActive == 1 {
+			return *e, true
+		}
+		if inactive == nil {
+			inactive = e
+		}
+	}
+	if inactive == nil {
+		return cmc.CryptocurrencyQuotesHistorical{}, false
+	}
+	return *inactive, true
+}
+
+// lastAt returns the last point at or before at, or the earliest point if all are later.
+func lastAt(points []cmc.HistoricalQuote, at time.Time) (cmc.HistoricalQuote, bool) {
+	if len(points) == 0 {
+		return cmc.HistoricalQuote{}, false
+	}
+
+	var choice *cmc.HistoricalQuote
+	earliest := &points[0]
+	for i := range points {
+		p := &points[i]
+		if p.Timestamp.Before(earliest.Timestamp) {
+			earliest = p
+		}
+		if !p.Timestamp.After(at) && (choice == nil || p.Timestamp.After(choice.Timestamp)) {
+			choice = p
+		}
+	}
+	if choice == nil {
+		choice = earliest
+	}
+	return *choice, true
+}
@@ -0,0 +1,145 @@
+package portfolio
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+	"github.com/Davincible/go-coinmarketcap/cmctest"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func testPortfolio() *Portfolio {
+	p := New()
+	p.Add(1, 0.5)
+	p.AddSymbol("eth", 2)
+	p.Add(1027, 1) // merged with the ETH symbol holding
+	p.Add(999999, 1)
+	return p
+}
+
+func TestValue(t *testing.T) {
+	fake := cmctest.NewServer()
+	defer fake.Close()
+
+	valuation, err := testPortfolio().Value(context.Background(), fake.Client(), "USD", "eur")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(valuation.Positions) != 2 || valuation.Positions[0].Symbol != "BTC" || valuation.Positions[1].Amount != 3 {
+		t.Fatalf("unexpected positions %+v", valuation.Positions)
+	}
+	if len(valuation.Missing) != 1 || valuation.Missing[0].ID != 999999 {
+		t.Errorf("expected the unknown ID to be missing, got %+v", valuation.Missing)
+	}
+	if euro := valuation.Currencies[1]; !euro.Fiat || euro.ID != 2790 || euro.Sign != "€" {
+		t.Errorf("expected EUR from the fiat map, got %+v", euro)
+	}
+	if valuation.Time.IsZero() {
+		t.Error("expected the valuation time from the quotes")
+	}
+
+	btc, eth := 0.5*65000.0, 3*3500.0
+	pnl24h := (btc - btc/1.015) + (eth - eth/1.021)
+	usd := valuation.Totals["USD"]
+	if !near(usd.Value, btc+eth) || !near(usd.PnL24h, pnl24h) || !near(usd.PercentChange24h, pnl24h/(btc+eth-pnl24h)*100) {
+		t.Errorf("unexpected USD total %+v", usd)
+	}
+	if eur := valuation.Totals["EUR"]; !near(eur.Value, (btc+eth)*0.92) {
+		t.Errorf("unexpected EUR total %+v", eur)
+	}
+
+	value := valuation.Positions[0].Values["USD"]
+	if !near(value.Weight, btc/(btc+eth)) || !near(value.PnL7d, btc-btc/1.042) || value.Price != 65000 {
+		t.Errorf("unexpected BTC value %+v", value)
+	}
+
+	// Fiat-only valuations are requested by ID.
+	for _, r := range fake.Requests() {
+		if r.Endpoint == "/v2/cryptocurrency/quotes/latest" && (r.Query.Get("convert_id") != "2781,2790" || r.Query.Get("convert") != "") {
+			t.Errorf("expected fiat convert IDs, got %v", r.Query)
+		}
+	}
+}
+
+func TestValueReusesCachedFiatMap(t *testing.T) {
+	fake := cmctest.NewServer()
+	defer fake.Close()
+
+	client := fake.Client(cmc.WithCache(cmc.NewMemoryCache(10)))
+	for _, convert := range [][]string{{"USD"}, {"EUR", "BTC"}, {"GBP"}} {
+		if _, err := testPortfolio().Value(context.Background(), client, convert...); err != nil {
+			t.Fatalf("%v: unexpected error: %v", convert, err)
+		}
+	}
+
+	fiatRequests := 0
+	for _, r := range fake.Requests() {
+		if r.Endpoint == "/v1/fiat/map" {
+			fiatRequests++
+		}
+	}
+	if fiatRequests != 1 {
+		t.Errorf("expected the fiat map to come from the client's cache, got %d requests", fiatRequests)
+	}
+}
+
+func TestValueInCryptocurrency(t *testing.T) {
+	fake := cmctest.NewServer()
+	defer fake.Close()
+
+	valuation, err := testPortfolio().Value(context.Background(), fake.Client(), "BTC", "USD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := valuation.Totals["BTC"].Value; !near(got, 0.5+3*3500.0/65000) {
+		t.Errorf("expected the value in BTC, got %v", got)
+	}
+	if valuation.Currencies[0].Fiat || !valuation.Currencies[1].Fiat {
+		t.Errorf("unexpected currencies %+v", valuation.Currencies)
+	}
+}
+
+func TestValueAt(t *testing.T) {
+	fake := cmctest.NewServer()
+	defer fake.Close()
+
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	point := func(offset time.Duration, price float64) cmc.HistoricalQuote {
+		return cmc.HistoricalQuote{Timestamp: at.Add(offset), Quote: map[string]*cmc.Quote{"2781": {Price: &price}}}
+	}
+	fake.SetFixture("/v2/cryptocurrency/quotes/historical", map[string]any{
+		"1": cmc.CryptocurrencyQuotesHistorical{ID: 1, Symbol: "BTC", Name: "Bitcoin", Quotes: []cmc.HistoricalQuote{
+			point(-10*time.Minute, 60000), point(-5*time.Minute, 61000), point(5*time.Minute, 62000),
+		}},
+		"ETH": []cmc.CryptocurrencyQuotesHistorical{
+			{ID: 1027, Symbol: "ETH", Name: "Ethereum", Quotes: []cmc.HistoricalQuote{point(-5*time.Minute, 3000)}},
+		},
+	})
+
+	p := New()
+	p.Add(1, 2)
+	p.AddSymbol("ETH", 10)
+	valuation, err := p.ValueAt(context.Background(), fake.Client(), at)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !valuation.Time.Equal(at) || !near(valuation.Totals["USD"].Value, 2*61000+10*3000) {
+		t.Errorf("expected the value at the last quotes before %v, got %+v", at, valuation)
+	}
+	if btc := valuation.Positions[0]; btc.ID != 1 || !btc.Time.Equal(at.Add(-5*time.Minute)) {
+		t.Errorf("unexpected BTC position %+v", btc)
+	}
+	for _, r := range fake.Requests() {
+		if r.Endpoint == "/v2/cryptocurrency/quotes/historical" && r.Query.Get("time_end") != "2024-03-01T12:00:00Z" {
+			t.Errorf("expected time_end to be the valuation time, got %v", r.Query)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cmc "github.com/Davincible/go-coinmarketcap"
+)
+
+// Currency is a currency a portfolio is valued in.
+type Currency struct {
+	Symbol string
+	ID     int // the CoinMarketCap ID of a fiat currency, zero otherwise
+	Name   string
+	Sign   string
+	Fiat   bool
+}
+
+// Valuation is the value of a portfolio in one or more currencies.
+type Valuation struct {
+	Time       time.Time // the requested time, or the newest quote's update time
+	Currencies []Currency
+	Positions  []Position       // by value in the first currency, largest first
+	Totals     map[string]Total // by currency symbol
+	Missing    []Holding        // holdings without a quote
+}
+
+// Position is the value of one asset.
+type Position struct {
+	ID     int
+	Symbol string
+	Name   string
+	Amount float64
+	Time   time.Time                // when the quote was last updated
+	Values map[string]PositionValue // by currency symbol
+}
+
+// PositionValue is the value of a position in one currency. The P&L is derived from the
+// quote's percent changes, assuming the amount was held over the whole period.
+type PositionValue struct {
+	Price            float64
+	Value            float64
+	PnL24h           float64
+	PnL7d            float64
+	PercentChange24h float64
+	PercentChange7d  float64
+	Weight           float64 // the share of the portfolio's total value, from 0 to 1
+}
+
+// Total is the value of a portfolio in one currency.
+type Total struct {
+	Value            float64
+	PnL24h           float64
+	PnL7d            float64
+	PercentChange24h float64
+	PercentChange7d  float64
+}
+
+// resolveCurrencies looks up fiat currencies with GetFiatMap. Other symbols are taken to
+// be cryptocurrencies. The fiat map is reused through the client's response cache.
+func resolveCurrencies(ctx context.Context, client *cmc.Client, convert []string) ([]Currency, error) {
+	if len(convert) == 0 {
+		convert = []string{"USD"}
+	}
+
+	resp, err := client.GetFiatMap(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("portfolio: fiat map: %w", err)
+	}
+	fiats := make(map[string]cmc.FiatMap, len(resp.Data))
+	for _, fiat := range resp.Data {
+		fiats[strings.ToUpper(fiat.Symbol)] = fiat
+	}
+
+	currencies := make([]Currency, 0, len(convert))
+	seen := make(map[string]bool, len(convert))
+	for _, symbol := range convert {
+		symbol = strings.ToUpper(symbol)
+		if seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+
+		currency := Currency{Symbol: symbol}
+		if fiat, ok := fiats[symbol]; ok {
+			currency = Currency{Symbol: symbol, ID: fiat.ID, Name: fiat.Name, Sign: fiat.Sign, Fiat: true}
+		}
+		currencies = append(currencies, currency)
+	}
+	return currencies, nil
+}
+
+// convertParams requests fiat-only valuations by ID, which is unambiguous, and mixed
+// ones by symbol, as the API does not accept both at once.
+func convertParams(currencies []Currency) (symbols []string, ids []int) {
+	fiat := true
+	for _, c := range currencies {
+		symbols = append(symbols, c.Symbol)
+		ids = append(ids, c.ID)
+		fiat = fiat && c.Fiat
+	}
+	if fiat {
+		return nil, ids
+	}
+	return symbols, nil
+}
+
+// quoteIn returns the quote of a currency, keyed by ID or symbol.
+func quoteIn(quotes map[string]*cmc.Quote, c Currency) *cmc.Quote {
+	for _, key := range []string{c.Symbol, strconv.Itoa(c.ID)} {
+		if quote, ok := quotes[key]; ok && quote != nil {
+			return quote
+		}
+	}
+	for key, quote := range quotes {
+		if strings.EqualFold(key, c.Symbol) && quote != nil {
+			return quote
+		}
+	}
+	return nil
+}
+
+func newValuation(currencies []Currency, at time.Time) *Valuation {
+	return &Valuation{Time: at, Currencies: currencies, Totals: make(map[string]Total, len(currencies))}
+}
+
+// add values a holding, merging it with a position of the same asset.
+func (v *Valuation) add(h Holding, a asset) {
+	var position *Position
+	for i := range v.Positions {
+		if v.Positions[i].ID == a.id && a.id != 0 {
+			position = &v.Positions[i]
+			break
+		}
+	}
+	if position == nil {
+		v.Positions = append(v.Positions, Position{ID: a.id, Symbol: a.symbol, Name: a.name, Values: make(map[string]PositionValue)})
+		position = &v.Positions[len(v.Positions)-1]
+	}
+	position.Amount += h.Amount
+	if a.time.After(position.Time) {
+		position.Time = a.time
+	}
+
+	for _, c := range v.Currencies {
+		quote := quoteIn(a.quotes, c)
+		if quote == nil || quote.Price == nil {
+			continue
+		}
+
+		value := PositionValue{Price: *quote.Price, Value: *quote.Price * position.Amount}
+		if quote.PercentChange24h != nil {
+			value.PercentChange24h = *quote.PercentChange24h
+			value.PnL24h = pnl(value.Value, value.PercentChange24h)
+		}
+		if quote.PercentChange7d != nil {
+			value.PercentChange7d = *quote.PercentChange7d
+			value.PnL7d = pnl(value.Value, value.PercentChange7d)
+		}
+		position.Values[c.Symbol] = value
+	}
+}
+
+// pnl returns the change in value that a percent change ending at value represents.
+func pnl(value, percentChange float64) float64 {
+	return value - value/(1+percentChange/100)
+}
+
+// finish computes the totals and weights and sorts the positions.
+func (v *Valuation) finish() {
+	for _, c := range v.Currencies {
+		var total Total
+		for _, p := range v.Positions {
+			value := p.Values[c.Symbol]
+			total.Value += value.Value
+			total.PnL24h += value.PnL24h
+			total.PnL7d += value.PnL7d
+		}
+		if start := total.Value - total.PnL24h; start != 0 {
+			total.PercentChange24h = total.PnL24h / start * 100
+		}
+		if start := total.Value - total.PnL7d; start != 0 {
+			total.PercentChange7d = total.PnL7d / start * 100
+		}
+		v.Totals[c.Symbol] = total
+
+		for _, p := range v.Positions {
+			if value, ok := p.Values[c.Symbol]; ok && total.Value != 0 {
+				value.Weight = value.Value / total.Value
+				p.Values[c.Symbol] = value
+			}
+		}
+	}
+
+	if v.Time.IsZero() {
+		for _, p := range v.Positions {
+			if p.Time.After(v.Time) {
+				v.Time = p.Time
+			}
+		}
+	}
+
+	if len(v.Currencies) > 0 {
+		first := v.Currencies[0].Symbol
+		sort.SliceStable(v.Positions, func(i, j int) bool {
+			return v.Positions[i].Values[first].Value > v.Positions[j].Values[first].Value
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package coinmarketcap
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParamValue is the set of value types AddPtr and AddSlice can encode, including
+// named types such as Interval, ListingSort and TimePeriod.
+type ParamValue interface {
+	~string | ~int | ~int64 | ~float64 | ~bool
+}
+
+// AddPtr adds a parameter if the value is not nil. It accepts typed enums directly:
+//
+//	coinmarketcap.AddPtr(params, "interval", opts.Interval)
+func AddPtr[T ParamValue](p *ParamBuilder, key string, value *T) *ParamBuilder {
+	if value != nil {
+		p.addValue(key, reflect.ValueOf(*value))
+	}
+	return p
+}
+
+// AddSlice adds a comma-separated list of values if the slice is not empty.
+func AddSlice[T ParamValue](p *ParamBuilder, key string, values []T) *ParamBuilder {
+	p.addValue(key, reflect.ValueOf(values))
+	return p
+}
+
+// AddStruct adds every field of an options struct that carries a `query:"name"` tag.
+// Nil pointers and empty strings and slices are skipped, other values are always sent.
+// Embedded structs are flattened and fields tagged `query:"-"` are ignored.
+func (p *ParamBuilder) AddStruct(opts any) *ParamBuilder {
+	v := reflect.ValueOf(opts)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return p
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		p.addFields(v)
+	}
+	return p
+}
+
+// addFields encodes the tagged fields of a struct value.
+func (p *ParamBuilder) addFields(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		value := v.Field(i)
+		key := field.Tag.Get("query")
+
+		if field.Anonymous && key == "" {
+			if value.Kind() == reflect.Pointer {
+				if value.IsNil() {
+					continue
+				}
+				value = value.Elem()
+			}
+			if value.Kind() == reflect.Struct {
+				p.addFields(value)
+			}
+			continue
+		}
+
+		if key == "" || key == "-" || !field.IsExported() {
+			continue
+		}
+		p.addValue(key, value)
+	}
+}
+
+// addValue encodes a single field value, dereferencing pointers and joining slices with commas.
+func (p *ParamBuilder) addValue(key string, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			p.addValue(key, v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		parts := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if s, ok := formatParam(v.Index(i)); ok && s != "" {
+				parts = append(parts, s)
+			}
+		}
+		if len(parts) > 0 {
+			p.values.Add(key, strings.Join(parts, ","))
+		}
+	default:
+		if s, ok := formatParam(v); ok {
+			p.Add(key, s)
+		}
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// formatParam renders a scalar value the way the API expects it.
+func formatParam(v reflect.Value) (string, bool) {
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(time.RFC3339), true
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return "", false
+		}
+		return formatParam(v.Elem())
+	}
+	return "", false
+}
+
+// encodeQuery converts an endpoint's options struct into query parameters.
+// A nil options pointer yields empty parameters.
+func encodeQuery(opts any) url.Values {
+	return NewParamBuilder().AddStruct(opts).Build()
+}
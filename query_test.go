@@ -0,0 +1,216 @@
+package coinmarketcap
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// optionsStructs lists every endpoint options struct. TestEncodeQueryAllOptions
+// fails when an *Options struct declared in the package is missing here.
+var optionsStructs = []any{
+	CryptocurrencyMapOptions{},
+	CryptocurrencyInfoOptions{},
+	CryptocurrencyListingsOptions{},
+	CryptocurrencyListingsHistoricalOptions{},
+	CryptocurrencyListingsNewOptions{},
+	CryptocurrencyQuotesOptions{},
+	CryptocurrencyQuotesHistoricalOptions{},
+	CryptocurrencyMarketPairsOptions{},
+	CryptocurrencyOHLCVOptions{},
+	CryptocurrencyOHLCVHistoricalOptions{},
+	CryptocurrencyPricePerformanceStatsOptions{},
+	CryptocurrencyCategoriesOptions{},
+	CryptocurrencyCategoryOptions{},
+	CryptocurrencyAirdropsOptions{},
+	CryptocurrencyTrendingOptions{},
+	CryptocurrencyGainersLosersOptions{},
+	ExchangeMapOptions{},
+	ExchangeInfoOptions{},
+	ExchangeListingsOptions{},
+	ExchangeQuotesOptions{},
+	ExchangeQuotesHistoricalOptions{},
+	ExchangeMarketPairsOptions{},
+	GlobalMetricsOptions{},
+	GlobalMetricsHistoricalOptions{},
+	FiatMapOptions{},
+	PriceConversionOptions{},
+	BlockchainStatsOptions{},
+	ContentLatestOptions{},
+	ContentPostsOptions{},
+	ContentCommentsOptions{},
+	CommunityTrendingOptions{},
+	IndexOptions{},
+	FearAndGreedHistoricalOptions{},
+	DexNetworksOptions{},
+	DexListingsOptions{},
+	DexSpotPairsOptions{},
+	DexPairOptions{},
+	DexPairOHLCVHistoricalOptions{},
+}
+
+// declaredOptionsStructs parses the package sources and returns the names of all
+// non-generic struct types ending in "Options".
+func declaredOptionsStructs(t *testing.T) []string {
+	t.Helper()
+
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("failed to list package files: %v", err)
+	}
+
+	var names []string
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", path, err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok || spec.TypeParams != nil || !strings.HasSuffix(spec.Name.Name, "Options") {
+				return true
+			}
+			if _, ok := spec.Type.(*ast.StructType); ok {
+				names = append(names, spec.Name.Name)
+			}
+			return true
+		})
+	}
+	return names
+}
+
+// fillFields sets every tagged field of v to a non-zero sample value and returns the expected query keys.
+func fillFields(t *testing.T, v reflect.Value) []string {
+	t.Helper()
+
+	var keys []string
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		value := v.Field(i)
+
+		if field.Anonymous {
+			keys = append(keys, fillFields(t, value)...)
+			continue
+		}
+
+		key := field.Tag.Get("query")
+		if key == "" {
+			t.Errorf("%s.%s has no query tag", v.Type().Name(), field.Name)
+			continue
+		}
+		keys = append(keys, key)
+
+		switch value.Kind() {
+		case reflect.Pointer:
+			elem := reflect.New(field.Type.Elem())
+			setSample(elem.Elem())
+			value.Set(elem)
+		case reflect.Slice:
+			slice := reflect.MakeSlice(field.Type, 1, 1)
+			setSample(slice.Index(0))
+			value.Set(slice)
+		default:
+			setSample(value)
+		}
+	}
+	return keys
+}
+
+func setSample(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Int, reflect.Int64:
+		v.SetInt(7)
+	case reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Struct:
+		if v.Type() == timeType {
+			v.Set(reflect.ValueOf(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+		}
+	}
+}
+
+func TestEncodeQueryAllOptions(t *testing.T) {
+	registered := make(map[string]bool)
+	for _, opts := range optionsStructs {
+		registered[reflect.TypeOf(opts).Name()] = true
+	}
+	for _, name := range declaredOptionsStructs(t) {
+		if !registered[name] {
+			t.Errorf("%s is not covered by optionsStructs", name)
+		}
+	}
+
+	for _, opts := range optionsStructs {
+		typ := reflect.TypeOf(opts)
+		t.Run(typ.Name(), func(t *testing.T) {
+			// A zero value must encode without panicking.
+			encodeQuery(reflect.New(typ).Interface())
+
+			filled := reflect.New(typ)
+			keys := fillFields(t, filled.Elem())
+
+			query := encodeQuery(filled.Interface())
+			for _, key := range keys {
+				if query.Get(key) == "" {
+					t.Errorf("field %q did not reach the query string: %v", key, query)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeQuerySkipsNil(t *testing.T) {
+	opts := &ContentLatestOptions{Sort: String("trending"), Limit: Int(5)}
+
+	query := encodeQuery(opts)
+	if len(query) != 2 || query.Get("sort") != "trending" || query.Get("limit") != "5" {
+		t.Errorf("expected only sort and limit, got %v", query)
+	}
+
+	if query := encodeQuery((*ContentLatestOptions)(nil)); len(query) != 0 {
+		t.Errorf("expected nil options to produce no params, got %v", query)
+	}
+}
+
+func TestEncodeQueryEmbedded(t *testing.T) {
+	sort := SortMarketCap
+	opts := &CryptocurrencyListingsHistoricalOptions{
+		Date: "2024-01-01",
+		CryptocurrencyListingsOptions: CryptocurrencyListingsOptions{
+			Sort:    &sort,
+			Convert: []string{"USD", "EUR"},
+		},
+	}
+
+	query := encodeQuery(opts)
+	if query.Get("date") != "2024-01-01" || query.Get("sort") != string(sort) || query.Get("convert") != "USD,EUR" {
+		t.Errorf("unexpected query: %v", query)
+	}
+}
+
+func TestAddPtr(t *testing.T) {
+	interval := IntervalDaily
+	var period *TimePeriod
+
+	params := NewParamBuilder()
+	AddPtr(params, "interval", &interval)
+	AddPtr(params, "time_period", period)
+	AddSlice(params, "id", []int{1, 1027})
+
+	query := params.Build()
+	if query.Get("interval") != string(IntervalDaily) || query.Has("time_period") || query.Get("id") != "1,1027" {
+		t.Errorf("unexpected query: %v", query)
+	}
+}
@@ -0,0 +1,153 @@
+package coinmarketcap
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxRetryDelay caps the exponential backoff between attempts.
+const DefaultMaxRetryDelay = 30 * time.Second
+
+// RetryPolicy controls how failed requests are retried.
+// Transport errors are always retried; HTTP responses are retried when their
+// status code or CMC error code is listed as retryable.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry; it doubles on each further attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the wait before a retry, both the computed backoff and the delay
+	// asked for by a Retry-After header. Zero means no cap.
+	MaxDelay time.Duration
+	// Jitter randomizes each backoff by up to this fraction in either direction (0 to 1).
+	Jitter float64
+	// RetryableStatusCodes lists HTTP status codes that should be retried.
+	RetryableStatusCodes []int
+	// RetryableErrorCodes lists CMC status.error_code values that should be retried.
+	RetryableErrorCodes []ErrorCode
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured. It retries
+// 429 and 5xx responses as well as the per-minute and IP rate limit error codes,
+// which clear on their own. Daily and monthly credit exhaustion is not retried.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: MaxRetries,
+		BaseDelay:  DefaultRetryDelay,
+		MaxDelay:   DefaultMaxRetryDelay,
+		Jitter:     0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryableErrorCodes: []ErrorCode{
+			ErrorCodeMinuteRateLimit,
+			ErrorCodeIPRateLimit,
+		},
+	}
+}
+
+// WithRetryPolicy sets the retry policy used for every request.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *ClientConfig) {
+		c.RetryPolicy = policy
+	}
+}
+
+// shouldRetry reports whether an HTTP error response is retryable under the policy.
+func (p RetryPolicy) shouldRetry(err *APIError) bool {
+	for _, code := range p.RetryableStatusCodes {
+		if err.StatusCode == code {
+			return true
+		}
+	}
+	if err.ErrorCode != 0 {
+		for _, code := range p.RetryableErrorCodes {
+			if err.ErrorCode == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before retry number attempt (0-based) using
+// exponential growth, the MaxDelay cap and jitter.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(attempt))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		delay *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+
+	return time.Duration(delay)
+}
+
+// retryDelay returns the wait before retry number attempt (0-based): the Retry-After
+// delay when the response carried one, capped at MaxDelay, and the backoff otherwise.
+func (p RetryPolicy) retryDelay(attempt int, retryAfter time.Duration, ok bool) time.Duration {
+	if !ok {
+		return p.backoff(attempt)
+	}
+	if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return retryAfter
+}
+
+// parseRetryAfter interprets a Retry-After header given either as a number of
+// seconds or as an HTTP-date. It reports false when the header is absent or invalid.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		if delay := at.Sub(now); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,217 @@
+package coinmarketcap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"seconds", "5", 5 * time.Second, true},
+		{"http date", now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{"past date", now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"empty", "", 0, false},
+		{"garbage", "soon", 0, false},
+		{"negative", "-3", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, ok := parseRetryAfter(tt.value, now)
+			if ok != tt.ok || delay != tt.expected {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tt.expected, tt.ok, delay, ok)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		BaseDelay: 100 * time.Millisecond,
+		MaxDelay:  500 * time.Millisecond,
+		Jitter:    0.5,
+	}
+
+	for attempt := 0; attempt < 6; attempt++ {
+		base := 100 * time.Millisecond << attempt
+		if base > policy.MaxDelay {
+			base = policy.MaxDelay
+		}
+		delay := policy.backoff(attempt)
+		if delay < base/2 || delay > base*3/2 {
+			t.Errorf("attempt %d: delay %v outside jitter bounds of %v", attempt, delay, base)
+		}
+	}
+}
+
+func TestRetryPolicyRetryDelay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		name       string
+		policy     RetryPolicy
+		retryAfter time.Duration
+		ok         bool
+		expected   time.Duration
+	}{
+		{"retry after", policy, 500 * time.Millisecond, true, 500 * time.Millisecond},
+		{"retry after capped", policy, time.Hour, true, time.Second},
+		{"retry after uncapped", RetryPolicy{}, time.Hour, true, time.Hour},
+		{"backoff", policy, 0, false, 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if delay := tt.policy.retryDelay(0, tt.retryAfter, tt.ok); delay != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, delay)
+			}
+		})
+	}
+}
+
+func TestDoRequestCapsRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"data": {}, "status": {"error_code": 0}}`))
+	}))
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.MaxDelay = 10 * time.Millisecond
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1000)),
+		WithRetryPolicy(policy),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := get[map[string]interface{}](client, ctx, "/test", nil); err != nil {
+		t.Fatalf("expected the retry to succeed after MaxDelay, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	policy := DefaultRetryPolicy()
+
+	tests := []struct {
+		name     string
+		err      *APIError
+		expected bool
+	}{
+		{"too many requests", &APIError{StatusCode: 429}, true},
+		{"service unavailable", &APIError{StatusCode: 503}, true},
+		{"minute rate limit", &APIError{StatusCode: 400, ErrorCode: 1008}, true},
+		{"daily limit", &APIError{StatusCode: 400, ErrorCode: 1009}, false},
+		{"bad request", &APIError{StatusCode: 400, ErrorCode: 400}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if policy.shouldRetry(tt.err) != tt.expected {
+				t.Errorf("expected shouldRetry to be %v", tt.expected)
+			}
+		})
+	}
+}
+
+func TestDoRequestRetriesServerErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data": {}, "status": {"error_code": 0}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1000)),
+		WithRetryPolicy(RetryPolicy{
+			MaxRetries:           3,
+			BaseDelay:            time.Millisecond,
+			RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+		}),
+	)
+
+	if _, err := get[map[string]interface{}](client, context.Background(), "/test", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestDoRequestDoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status": {"error_code": 1009, "error_message": "daily limit reached"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1000)),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond}),
+	)
+
+	_, err := get[map[string]interface{}](client, context.Background(), "/test", nil)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 attempt, got %d", calls)
+	}
+}
+
+func TestDoRequestBackoffHonorsContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimit(rate.Limit(1000)),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := get[map[string]interface{}](client, ctx, "/test", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected backoff to stop on cancellation, took %v", elapsed)
+	}
+}
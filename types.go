@@ -1,24 +1,68 @@
 // Package coinmarketcap provides types and structures for CoinMarketCap API responses.
 package coinmarketcap
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // APIResponse represents the standard response format from CoinMarketCap API.
 // All API endpoints return data in this consistent structure with generic data type T.
 type APIResponse[T any] struct {
 	Data   T      `json:"data"`
 	Status Status `json:"status"`
+
+	cached bool // served from the client's cache
 }
 
 // Status contains metadata about the API response including error information and credit usage.
 type Status struct {
 	Timestamp    time.Time `json:"timestamp"`
-	ErrorCode    int       `json:"error_code"`
+	ErrorCode    ErrorCode `json:"error_code"`
 	ErrorMessage *string   `json:"error_message"`
 	Elapsed      int       `json:"elapsed"`
 	CreditCount  int       `json:"credit_count"`
 }
 
+// UnmarshalJSON decodes the numeric fields sent either as numbers or, as the v4 DEX
+// endpoints do, as strings.
+func (s *Status) UnmarshalJSON(data []byte) error {
+	type status Status // without the UnmarshalJSON method
+	var raw struct {
+		status
+		ErrorCode   json.Number `json:"error_code"`
+		Elapsed     json.Number `json:"elapsed"`
+		CreditCount json.Number `json:"credit_count"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*s = Status(raw.status)
+	for _, field := range []struct {
+		name  string
+		value json.Number
+		dst   *int
+	}{
+		{"error_code", raw.ErrorCode, (*int)(&s.ErrorCode)},
+		{"elapsed", raw.Elapsed, &s.Elapsed},
+		{"credit_count", raw.CreditCount, &s.CreditCount},
+	} {
+		if field.value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(field.value.String())
+		if err != nil {
+			return fmt.Errorf("status %s: %w", field.name, err)
+		}
+		*field.dst = n
+	}
+	return nil
+}
+
 // Quote represents price and market data for a cryptocurrency in a specific currency.
 type Quote struct {
 	Price                 *float64   `json:"price"`
@@ -43,6 +87,7 @@ type CryptocurrencyMap struct {
 	Name                string     `json:"name"`
 	Symbol              string     `json:"symbol"`
 	Slug                string     `json:"slug"`
+	Rank                *int       `json:"rank,omitempty"`
 	IsActive            *int       `json:"is_active,omitempty"`
 	Status              *int       `json:"status,omitempty"`
 	FirstHistoricalData *time.Time `json:"first_historical_data,omitempty"`
@@ -138,6 +183,16 @@ type CryptocurrencyQuote struct {
 	Quote                         map[string]*Quote `json:"quote"`
 }
 
+// CryptocurrencyQuotesHistorical is the quote history of one cryptocurrency.
+type CryptocurrencyQuotesHistorical struct {
+	ID       int               `json:"id"`
+	Name     string            `json:"name"`
+	Symbol   string            `json:"symbol"`
+	IsActive *int              `json:"is_active"`
+	IsFiat   *int              `json:"is_fiat"`
+	Quotes   []HistoricalQuote `json:"quotes"`
+}
+
 // HistoricalQuote represents historical price data at a specific timestamp.
 type HistoricalQuote struct {
 	Timestamp      time.Time         `json:"timestamp"`
@@ -335,6 +390,279 @@ type ExchangeQuote struct {
 	Quote                  map[string]*Quote `json:"quote,omitempty"`
 }
 
+// ExchangeQuotesHistorical is the volume history of one exchange.
+type ExchangeQuotesHistorical struct {
+	ID     int               `json:"id"`
+	Name   string            `json:"name"`
+	Slug   string            `json:"slug"`
+	Quotes []HistoricalQuote `json:"quotes"`
+}
+
+// exchangeHistory decodes the data of /v1/exchange/quotes/historical: a single
+// exchange when one is requested, and exchanges keyed by ID otherwise.
+type exchangeHistory map[string]ExchangeQuotesHistorical
+
+func (h *exchangeHistory) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	if _, ok := fields["quotes"]; ok {
+		var single ExchangeQuotesHistorical
+		if err := json.Unmarshal(data, &single); err != nil {
+			return err
+		}
+		*h = exchangeHistory{strconv.Itoa(single.ID): single}
+		return nil
+	}
+
+	var keyed map[string]ExchangeQuotesHistorical
+	if err := json.Unmarshal(data, &keyed); err != nil {
+		return err
+	}
+	*h = keyed
+	return nil
+}
+
+type ExchangeAsset struct {
+	WalletAddress string                `json:"wallet_address"`
+	Balance       float64               `json:"balance"`
+	Platform      ExchangeAssetPlatform `json:"platform"`
+	Currency      ExchangeAssetCurrency `json:"currency"`
+}
+
+type ExchangeAssetPlatform struct {
+	CryptoID int    `json:"crypto_id"`
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+}
+
+type ExchangeAssetCurrency struct {
+	CryptoID int     `json:"crypto_id"`
+	PriceUSD float64 `json:"price_usd"`
+	Symbol   string  `json:"symbol"`
+	Name     string  `json:"name"`
+}
+
+// ValueUSD returns the wallet balance valued at the currency's USD price.
+func (a ExchangeAsset) ValueUSD() float64 {
+	return a.Balance * a.Currency.PriceUSD
+}
+
+// ReserveTotal is the combined balance of one currency across exchange wallets.
+type ReserveTotal struct {
+	Balance  float64
+	ValueUSD float64
+	Wallets  int
+}
+
+// ChainReserves is the combined value of all currencies an exchange holds on one chain.
+type ChainReserves struct {
+	ValueUSD   float64
+	Wallets    int
+	Currencies map[string]ReserveTotal // keyed by currency symbol
+}
+
+// ReservesByCurrency totals exchange assets per currency symbol across all chains.
+func ReservesByCurrency(assets []ExchangeAsset) map[string]ReserveTotal {
+	totals := make(map[string]ReserveTotal)
+	for _, asset := range assets {
+		totals[asset.Currency.Symbol] = totals[asset.Currency.Symbol].add(asset)
+	}
+	return totals
+}
+
+// ReservesByChain totals exchange assets per platform symbol, broken down by currency.
+func ReservesByChain(assets []ExchangeAsset) map[string]ChainReserves {
+	chains := make(map[string]ChainReserves)
+	for _, asset := range assets {
+		chain := chains[asset.Platform.Symbol]
+		if chain.Currencies == nil {
+			chain.Currencies = make(map[string]ReserveTotal)
+		}
+		chain.ValueUSD += asset.ValueUSD()
+		chain.Wallets++
+		chain.Currencies[asset.Currency.Symbol] = chain.Currencies[asset.Currency.Symbol].add(asset)
+		chains[asset.Platform.Symbol] = chain
+	}
+	return chains
+}
+
+// TotalReservesUSD returns the USD value of all exchange assets.
+func TotalReservesUSD(assets []ExchangeAsset) float64 {
+	var total float64
+	for _, asset := range assets {
+		total += asset.ValueUSD()
+	}
+	return total
+}
+
+func (t ReserveTotal) add(asset ExchangeAsset) ReserveTotal {
+	t.Balance += asset.Balance
+	t.ValueUSD += asset.ValueUSD()
+	t.Wallets++
+	return t
+}
+
+type DexNetwork struct {
+	ID                 int        `json:"id"`
+	Name               string     `json:"name"`
+	NetworkSlug        string     `json:"network_slug"`
+	CryptocurrencyID   *int       `json:"cryptocurrency_id"`
+	CryptocurrencySlug *string    `json:"cryptocurrency_slug,omitempty"`
+	WrappedTokenID     *int       `json:"wrapped_token_id"`
+	WrappedTokenSlug   *string    `json:"wrapped_token_slug,omitempty"`
+	CreatedAt          *time.Time `json:"created_at,omitempty"`
+}
+
+type DexListing struct {
+	ID             int               `json:"id"`
+	Name           string            `json:"name"`
+	Slug           string            `json:"slug"`
+	Status         *string           `json:"status"`
+	Type           *string           `json:"type"`
+	NumMarketPairs *int              `json:"num_market_pairs"`
+	LastUpdated    *time.Time        `json:"last_updated"`
+	Quote          []DexListingQuote `json:"quote"`
+}
+
+type DexListingQuote struct {
+	ConvertID          string     `json:"convert_id"`
+	MarketShare        *float64   `json:"market_share"`
+	NumTransactions24h *int       `json:"num_transactions_24h"`
+	Volume24h          *float64   `json:"volume_24h"`
+	LastUpdated        *time.Time `json:"last_updated"`
+}
+
+// DexToken is one side of a DEX pair, identified on-chain by its contract address.
+type DexToken struct {
+	ID              string
+	UCID            string
+	Name            string
+	Symbol          string
+	ContractAddress string
+}
+
+// DexPairInfo identifies a DEX pool and its base and quote tokens. It is embedded in
+// every v4 pair response.
+type DexPairInfo struct {
+	ContractAddress           string `json:"contract_address"`
+	Name                      string `json:"name"`
+	BaseAssetID               string `json:"base_asset_id"`
+	BaseAssetUCID             string `json:"base_asset_ucid"`
+	BaseAssetName             string `json:"base_asset_name"`
+	BaseAssetSymbol           string `json:"base_asset_symbol"`
+	BaseAssetContractAddress  string `json:"base_asset_contract_address"`
+	QuoteAssetID              string `json:"quote_asset_id"`
+	QuoteAssetUCID            string `json:"quote_asset_ucid"`
+	QuoteAssetName            string `json:"quote_asset_name"`
+	QuoteAssetSymbol          string `json:"quote_asset_symbol"`
+	QuoteAssetContractAddress string `json:"quote_asset_contract_address"`
+	DexID                     string `json:"dex_id"`
+	DexSlug                   string `json:"dex_slug"`
+	NetworkID                 string `json:"network_id"`
+	NetworkSlug               string `json:"network_slug"`
+}
+
+// BaseToken returns the pair's base token.
+func (p DexPairInfo) BaseToken() DexToken {
+	return DexToken{
+		ID:              p.BaseAssetID,
+		UCID:            p.BaseAssetUCID,
+		Name:            p.BaseAssetName,
+		Symbol:          p.BaseAssetSymbol,
+		ContractAddress: p.BaseAssetContractAddress,
+	}
+}
+
+// QuoteToken returns the pair's quote token.
+func (p DexPairInfo) QuoteToken() DexToken {
+	return DexToken{
+		ID:              p.QuoteAssetID,
+		UCID:            p.QuoteAssetUCID,
+		Name:            p.QuoteAssetName,
+		Symbol:          p.QuoteAssetSymbol,
+		ContractAddress: p.QuoteAssetContractAddress,
+	}
+}
+
+type DexPair struct {
+	DexPairInfo
+	LastUpdated        *time.Time     `json:"last_updated"`
+	CreatedAt          *time.Time     `json:"created_at"`
+	PoolCreated        *time.Time     `json:"pool_created"`
+	NumTransactions24h *int           `json:"num_transactions_24h"`
+	Holders            *int           `json:"holders"`
+	Buys24h            *int           `json:"24h_no_of_buys"`
+	Sells24h           *int           `json:"24h_no_of_sells"`
+	BuyVolume24h       *float64       `json:"24h_buy_volume"`
+	SellVolume24h      *float64       `json:"24h_sell_volume"`
+	ScrollID           *string        `json:"scroll_id,omitempty"`
+	Quote              []DexPairQuote `json:"quote"`
+}
+
+type DexPairQuote struct {
+	ConvertID             string     `json:"convert_id"`
+	Price                 *float64   `json:"price"`
+	PriceByQuoteAsset     *float64   `json:"price_by_quote_asset"`
+	Volume24h             *float64   `json:"volume_24h"`
+	Liquidity             *float64   `json:"liquidity"`
+	FullyDilutedValue     *float64   `json:"fully_diluted_value"`
+	PercentChangePrice1h  *float64   `json:"percent_change_price_1h"`
+	PercentChangePrice24h *float64   `json:"percent_change_price_24h"`
+	LastUpdated           *time.Time `json:"last_updated"`
+}
+
+type DexPairOHLCV struct {
+	DexPairInfo
+	LastUpdated *time.Time      `json:"last_updated"`
+	Quote       []DexOHLCVQuote `json:"quote"`
+}
+
+type DexPairOHLCVHistorical struct {
+	DexPairInfo
+	Quotes []DexOHLCVPeriod `json:"quotes"`
+}
+
+type DexOHLCVPeriod struct {
+	TimeOpen  *time.Time      `json:"time_open"`
+	TimeClose *time.Time      `json:"time_close"`
+	Quote     []DexOHLCVQuote `json:"quote"`
+}
+
+type DexOHLCVQuote struct {
+	ConvertID   string     `json:"convert_id"`
+	Open        *float64   `json:"open"`
+	High        *float64   `json:"high"`
+	Low         *float64   `json:"low"`
+	Close       *float64   `json:"close"`
+	Volume      *float64   `json:"volume"`
+	Timestamp   *time.Time `json:"timestamp,omitempty"`
+	LastUpdated *time.Time `json:"last_updated,omitempty"`
+}
+
+type DexPairTrades struct {
+	DexPairInfo
+	Trades []DexTrade `json:"trades"`
+}
+
+type DexTrade struct {
+	Date            *time.Time      `json:"date"`
+	Type            string          `json:"type"` // "buy" or "sell"
+	TransactionHash string          `json:"transaction_hash"`
+	Quote           []DexTradeQuote `json:"quote"`
+}
+
+type DexTradeQuote struct {
+	ConvertID         string   `json:"convert_id"`
+	Price             *float64 `json:"price"`
+	PriceByQuoteAsset *float64 `json:"price_by_quote_asset"`
+	AmountBaseAsset   *float64 `json:"amount_base_asset"`
+	AmountQuoteAsset  *float64 `json:"amount_quote_asset"`
+	Total             *float64 `json:"total"`
+}
+
 type GlobalMetrics struct {
 	BtcDominance                   *float64          `json:"btc_dominance"`
 	EthDominance                   *float64          `json:"eth_dominance"`
@@ -442,6 +770,301 @@ type KeyInfo struct {
 	} `json:"usage"`
 }
 
+// IndexValue represents a CoinMarketCap index level, such as the CMC100, with its constituents.
+// Latest readings set LastUpdate and NextUpdate; historical readings set UpdateTime.
+type IndexValue struct {
+	Value                    float64            `json:"value"`
+	Value24hPercentageChange *float64           `json:"value_24h_percentage_change,omitempty"`
+	Constituents             []IndexConstituent `json:"constituents,omitempty"`
+	LastUpdate               *time.Time         `json:"last_update,omitempty"`
+	NextUpdate               *time.Time         `json:"next_update,omitempty"`
+	UpdateTime               *time.Time         `json:"update_time,omitempty"`
+}
+
+// IndexConstituent represents a cryptocurrency included in an index and its weight.
+type IndexConstituent struct {
+	ID     int     `json:"id"`
+	Name   string  `json:"name"`
+	Symbol string  `json:"symbol"`
+	URL    string  `json:"url"`
+	Weight float64 `json:"weight"`
+}
+
+// Time returns the time the reading refers to: UpdateTime for historical readings, LastUpdate otherwise.
+func (v IndexValue) Time() time.Time {
+	switch {
+	case v.UpdateTime != nil:
+		return *v.UpdateTime
+	case v.LastUpdate != nil:
+		return *v.LastUpdate
+	default:
+		return time.Time{}
+	}
+}
+
+// Weights returns the constituent weights keyed by CMC ID.
+func (v IndexValue) Weights() map[int]float64 {
+	weights := make(map[int]float64, len(v.Constituents))
+	for _, constituent := range v.Constituents {
+		weights[constituent.ID] = constituent.Weight
+	}
+	return weights
+}
+
+// IndexRebalance describes a change in an index's constituents between two readings.
+type IndexRebalance struct {
+	Time    time.Time
+	Added   []IndexConstituent
+	Removed []IndexConstituent
+}
+
+// IndexRebalances returns the points in a historical series where constituents were
+// added or removed. The series is expected in chronological order.
+func IndexRebalances(history []IndexValue) []IndexRebalance {
+	var rebalances []IndexRebalance
+
+	for i := 1; i < len(history); i++ {
+		prev, curr := history[i-1], history[i]
+		if len(prev.Constituents) == 0 || len(curr.Constituents) == 0 {
+			continue
+		}
+
+		prevWeights, currWeights := prev.Weights(), curr.Weights()
+		rebalance := IndexRebalance{Time: curr.Time()}
+
+		for _, constituent := range curr.Constituents {
+			if _, ok := prevWeights[constituent.ID]; !ok {
+				rebalance.Added = append(rebalance.Added, constituent)
+			}
+		}
+		for _, constituent := range prev.Constituents {
+			if _, ok := currWeights[constituent.ID]; !ok {
+				rebalance.Removed = append(rebalance.Removed, constituent)
+			}
+		}
+
+		if len(rebalance.Added) > 0 || len(rebalance.Removed) > 0 {
+			rebalances = append(rebalances, rebalance)
+		}
+	}
+
+	return rebalances
+}
+
+// FearAndGreed represents a CMC Crypto Fear and Greed Index reading.
+// Historical entries carry a Timestamp; the latest reading carries an UpdateTime,
+// which is also copied into Timestamp so both can be handled the same way.
+type FearAndGreed struct {
+	Value               int        `json:"value"`
+	ValueClassification string     `json:"value_classification"`
+	Timestamp           time.Time  `json:"timestamp"`
+	UpdateTime          *time.Time `json:"update_time,omitempty"`
+}
+
+// UnmarshalJSON decodes timestamps sent either as Unix seconds or as RFC3339 strings.
+func (f *FearAndGreed) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Value               json.Number     `json:"value"`
+		ValueClassification string          `json:"value_classification"`
+		Timestamp           json.RawMessage `json:"timestamp"`
+		UpdateTime          json.RawMessage `json:"update_time"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*f = FearAndGreed{ValueClassification: raw.ValueClassification}
+
+	if raw.Value != "" {
+		value, err := raw.Value.Float64()
+		if err != nil {
+			return fmt.Errorf("invalid fear and greed value %q: %w", raw.Value, err)
+		}
+		f.Value = int(value)
+	}
+
+	timestamp, err := parseFlexibleTime(raw.Timestamp)
+	if err != nil {
+		return err
+	}
+	f.Timestamp = timestamp
+
+	updateTime, err := parseFlexibleTime(raw.UpdateTime)
+	if err != nil {
+		return err
+	}
+	if !updateTime.IsZero() {
+		f.UpdateTime = &updateTime
+		if f.Timestamp.IsZero() {
+			f.Timestamp = updateTime
+		}
+	}
+
+	return nil
+}
+
+// parseFlexibleInt parses a JSON integer given either as a number or as a quoted string.
+// Null or empty values yield zero.
+func parseFlexibleInt(raw json.RawMessage) (int, error) {
+	value := strings.Trim(strings.TrimSpace(string(raw)), `"`)
+	if value == "" || value == "null" {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer %q: %w", value, err)
+	}
+	return int(n), nil
+}
+
+// parseFlexibleTime parses a JSON time value given as an RFC3339 string or as Unix
+// seconds or milliseconds, either quoted or as a number. Null or empty values yield the zero time.
+func parseFlexibleTime(raw json.RawMessage) (time.Time, error) {
+	value := strings.Trim(strings.TrimSpace(string(raw)), `"`)
+	if value == "" || value == "null" {
+		return time.Time{}, nil
+	}
+
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		// Values beyond the year 5138 in seconds are treated as milliseconds.
+		if unix > 1e11 {
+			return time.UnixMilli(unix).UTC(), nil
+		}
+		return time.Unix(unix, 0).UTC(), nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", value, err)
+	}
+	return t, nil
+}
+
+// ContentAsset is a cryptocurrency referenced by a news article, post or comment.
+type ContentAsset struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name,omitempty"`
+	Symbol string `json:"symbol"`
+	Slug   string `json:"slug"`
+}
+
+// NewsArticle represents a news item or Alexandria article from /v1/content/latest.
+type NewsArticle struct {
+	Cover      *string         `json:"cover"`
+	Assets     []ContentAsset  `json:"assets"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  *time.Time      `json:"updated_at"`
+	ReleasedAt *time.Time      `json:"released_at"`
+	Meta       NewsArticleMeta `json:"meta"`
+}
+
+// NewsArticleMeta holds the descriptive fields of a NewsArticle.
+type NewsArticleMeta struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Subtitle   string `json:"subtitle"`
+	SourceName string `json:"sourceName"`
+	SourceURL  string `json:"sourceUrl"`
+	Language   string `json:"language"`
+	Type       string `json:"type"`
+	Status     string `json:"status"`
+	Visibility bool   `json:"visibility"`
+}
+
+// PostAuthor identifies the author of a community post or comment.
+type PostAuthor struct {
+	Nickname  string `json:"nickname"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// Post represents a CMC community post. Counts and post times are sent as strings
+// by the API and decoded into numbers and times.
+type Post struct {
+	PostID       string         `json:"post_id"`
+	Owner        PostAuthor     `json:"owner"`
+	TextContent  string         `json:"text_content"`
+	Photos       []string       `json:"photos"`
+	CommentCount int            `json:"comment_count"`
+	LikeCount    int            `json:"like_count"`
+	RepostCount  int            `json:"repost_count"`
+	PostTime     time.Time      `json:"post_time"`
+	LanguageCode string         `json:"language_code"`
+	Currencies   []ContentAsset `json:"currencies"`
+}
+
+// UnmarshalJSON decodes counts and post times sent either as strings or as numbers.
+func (p *Post) UnmarshalJSON(data []byte) error {
+	type alias Post
+	var raw struct {
+		alias
+		CommentCount json.RawMessage `json:"comment_count"`
+		LikeCount    json.RawMessage `json:"like_count"`
+		RepostCount  json.RawMessage `json:"repost_count"`
+		PostTime     json.RawMessage `json:"post_time"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*p = Post(raw.alias)
+
+	var err error
+	if p.CommentCount, err = parseFlexibleInt(raw.CommentCount); err != nil {
+		return err
+	}
+	if p.LikeCount, err = parseFlexibleInt(raw.LikeCount); err != nil {
+		return err
+	}
+	if p.RepostCount, err = parseFlexibleInt(raw.RepostCount); err != nil {
+		return err
+	}
+	if p.PostTime, err = parseFlexibleTime(raw.PostTime); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Comment represents a comment on a community post. Comments share the shape of posts.
+type Comment struct {
+	Post
+}
+
+// PostList is a page of community posts. LastScore is the cursor for the next page.
+type PostList struct {
+	List      []Post `json:"list"`
+	LastScore string `json:"last_score"`
+}
+
+// TrendingTopic represents a trending community topic.
+type TrendingTopic struct {
+	Rank  int    `json:"rank"`
+	Topic string `json:"topic"`
+}
+
+// TrendingToken represents a cryptocurrency trending in the community.
+type TrendingToken struct {
+	ID       int               `json:"id"`
+	Name     string            `json:"name"`
+	Symbol   string            `json:"symbol"`
+	Slug     string            `json:"slug"`
+	Rank     int               `json:"rank"`
+	CMCRank  *int              `json:"cmc_rank"`
+	IsActive *int              `json:"is_active"`
+	IsFiat   *int              `json:"is_fiat"`
+	Quote    map[string]*Quote `json:"quote,omitempty"`
+}
+
+// Index identifies a CoinMarketCap index served under /v3/index.
+type Index string
+
+const (
+	IndexCMC100        Index = "cmc100"
+	IndexCMC20         Index = "cmc20"
+	IndexAltcoinSeason Index = "altcoin-season"
+)
+
 type ListingSort string
 
 const (
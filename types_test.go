@@ -3,6 +3,7 @@ package coinmarketcap
 import (
 	"encoding/json"
 	"testing"
+	"time"
 )
 
 func TestAPIResponseUnmarshaling(t *testing.T) {
@@ -154,6 +155,68 @@ func TestCryptocurrencyListingUnmarshaling(t *testing.T) {
 	}
 }
 
+func TestQuotesHistoricalUnmarshaling(t *testing.T) {
+	jsonData := `{
+		"data": {
+			"1": {
+				"id": 1,
+				"name": "Bitcoin",
+				"symbol": "BTC",
+				"is_active": 1,
+				"is_fiat": 0,
+				"quotes": [
+					{"timestamp": "2024-03-01T00:00:00.000Z", "quote": {"USD": {"price": 61000.5, "volume_24h": 3.1e10}}},
+					{"timestamp": "2024-03-01T00:05:00.000Z", "quote": {"USD": {"price": 61050.25}}}
+				]
+			},
+			"ETH": [
+				{"id": 1027, "name": "Ethereum", "symbol": "ETH", "is_active": 1, "quotes": []}
+			]
+		},
+		"status": {"error_code": 0}
+	}`
+
+	var response APIResponse[map[string]oneOrMany[CryptocurrencyQuotesHistorical]]
+	if err := json.Unmarshal([]byte(jsonData), &response); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	btc := response.Data["1"]
+	if len(btc) != 1 || btc[0].ID != 1 || len(btc[0].Quotes) != 2 {
+		t.Fatalf("expected the ID lookup as a one-element list, got %+v", btc)
+	}
+	if price := btc[0].Quotes[1].Quote["USD"].Price; price == nil || *price != 61050.25 {
+		t.Errorf("expected price 61050.25, got %v", price)
+	}
+	if eth := response.Data["ETH"]; len(eth) != 1 || eth[0].Symbol != "ETH" || eth[0].Quotes == nil {
+		t.Errorf("expected the symbol lookup as a list, got %+v", eth)
+	}
+}
+
+func TestExchangeHistoryUnmarshaling(t *testing.T) {
+	quotes := `"quotes": [{"timestamp": "2024-03-01T00:00:00.000Z", "quote": {"USD": {"volume_24h": 1.5e10}}}]`
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"single", `{"id": 270, "name": "Binance", "slug": "binance", ` + quotes + `}`},
+		{"keyed", `{"270": {"id": 270, "name": "Binance", "slug": "binance", ` + quotes + `}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var history exchangeHistory
+			if err := json.Unmarshal([]byte(tt.data), &history); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+			binance, ok := history["270"]
+			if !ok || binance.Slug != "binance" || len(binance.Quotes) != 1 || *binance.Quotes[0].Quote["USD"].Volume24h != 1.5e10 {
+				t.Errorf("unexpected history %+v", history)
+			}
+		})
+	}
+}
+
 func TestOHLCVUnmarshaling(t *testing.T) {
 	jsonData := `{
 		"time_open": "2023-01-01T00:00:00.000Z",
@@ -372,3 +435,209 @@ func TestConstantValues(t *testing.T) {
 		t.Errorf("expected IntervalDaily to be 'daily', got %s", IntervalDaily)
 	}
 }
+
+func TestFearAndGreedUnmarshaling(t *testing.T) {
+	latestJSON := `{"value": 38, "update_time": "2024-09-19T02:54:56.017Z", "value_classification": "Fear"}`
+
+	var latest FearAndGreed
+	if err := json.Unmarshal([]byte(latestJSON), &latest); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if latest.Value != 38 || latest.ValueClassification != "Fear" {
+		t.Errorf("expected value 38 (Fear), got %d (%s)", latest.Value, latest.ValueClassification)
+	}
+	if latest.UpdateTime == nil || latest.UpdateTime.Year() != 2024 {
+		t.Errorf("expected update time in 2024, got %v", latest.UpdateTime)
+	}
+	if !latest.Timestamp.Equal(*latest.UpdateTime) {
+		t.Errorf("expected timestamp to default to update time, got %v", latest.Timestamp)
+	}
+
+	historicalJSON := `[
+		{"timestamp": "1726704000", "value": 38, "value_classification": "Fear"},
+		{"timestamp": 1726617600, "value": 55, "value_classification": "Neutral"}
+	]`
+
+	var historical []FearAndGreed
+	if err := json.Unmarshal([]byte(historicalJSON), &historical); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if len(historical) != 2 {
+		t.Fatalf("expected 2 readings, got %d", len(historical))
+	}
+	if !historical[0].Timestamp.Equal(time.Unix(1726704000, 0)) {
+		t.Errorf("expected timestamp 1726704000, got %v", historical[0].Timestamp)
+	}
+	if historical[1].Value != 55 || historical[1].UpdateTime != nil {
+		t.Errorf("unexpected second reading: %+v", historical[1])
+	}
+}
+
+func TestIndexValueUnmarshaling(t *testing.T) {
+	jsonData := `[
+		{
+			"update_time": "2024-01-01T00:00:00.000Z",
+			"value": 200.5,
+			"constituents": [
+				{"id": 1, "name": "Bitcoin", "symbol": "BTC", "url": "https://coinmarketcap.com/currencies/bitcoin/", "weight": 0.55},
+				{"id": 1027, "name": "Ethereum", "symbol": "ETH", "url": "https://coinmarketcap.com/currencies/ethereum/", "weight": 0.25}
+			]
+		},
+		{
+			"update_time": "2024-01-08T00:00:00.000Z",
+			"value": 210.1,
+			"constituents": [
+				{"id": 1, "name": "Bitcoin", "symbol": "BTC", "url": "https://coinmarketcap.com/currencies/bitcoin/", "weight": 0.54},
+				{"id": 5426, "name": "Solana", "symbol": "SOL", "url": "https://coinmarketcap.com/currencies/solana/", "weight": 0.05}
+			]
+		}
+	]`
+
+	var history []IndexValue
+	if err := json.Unmarshal([]byte(jsonData), &history); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if len(history) != 2 {
+		t.Fatalf("expected 2 values, got %d", len(history))
+	}
+	if history[0].Value != 200.5 {
+		t.Errorf("expected value 200.5, got %f", history[0].Value)
+	}
+	if w := history[0].Weights()[1027]; w != 0.25 {
+		t.Errorf("expected ETH weight 0.25, got %f", w)
+	}
+	if !history[1].Time().Equal(time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected time 2024-01-08, got %v", history[1].Time())
+	}
+
+	rebalances := IndexRebalances(history)
+	if len(rebalances) != 1 {
+		t.Fatalf("expected 1 rebalance, got %d", len(rebalances))
+	}
+	if len(rebalances[0].Added) != 1 || rebalances[0].Added[0].Symbol != "SOL" {
+		t.Errorf("expected SOL to be added, got %+v", rebalances[0].Added)
+	}
+	if len(rebalances[0].Removed) != 1 || rebalances[0].Removed[0].Symbol != "ETH" {
+		t.Errorf("expected ETH to be removed, got %+v", rebalances[0].Removed)
+	}
+}
+
+func TestPostUnmarshaling(t *testing.T) {
+	jsonData := `{
+		"list": [
+			{
+				"post_id": "325670123",
+				"owner": {"nickname": "satoshi", "avatar_url": "https://example.com/a.png"},
+				"text_content": "$BTC looking strong",
+				"photos": [],
+				"comment_count": "12",
+				"like_count": 40,
+				"repost_count": "0",
+				"post_time": "1704067200000",
+				"language_code": "en",
+				"currencies": [{"id": 1, "symbol": "BTC", "slug": "bitcoin"}]
+			}
+		],
+		"last_score": "1704067200000"
+	}`
+
+	var list PostList
+	if err := json.Unmarshal([]byte(jsonData), &list); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if list.LastScore != "1704067200000" || len(list.List) != 1 {
+		t.Fatalf("unexpected post list: %+v", list)
+	}
+
+	post := list.List[0]
+	if post.Owner.Nickname != "satoshi" {
+		t.Errorf("expected owner satoshi, got %s", post.Owner.Nickname)
+	}
+	if post.CommentCount != 12 || post.LikeCount != 40 || post.RepostCount != 0 {
+		t.Errorf("unexpected counts: comments=%d likes=%d reposts=%d", post.CommentCount, post.LikeCount, post.RepostCount)
+	}
+	if !post.PostTime.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected post time 2024-01-01, got %v", post.PostTime)
+	}
+	if len(post.Currencies) != 1 || post.Currencies[0].Symbol != "BTC" {
+		t.Errorf("expected BTC currency reference, got %+v", post.Currencies)
+	}
+
+	var comments []Comment
+	if err := json.Unmarshal([]byte(`[{"post_id": "1", "comment_count": "3", "post_time": "1704067200000"}]`), &comments); err != nil {
+		t.Fatalf("failed to unmarshal comments: %v", err)
+	}
+	if comments[0].CommentCount != 3 || comments[0].PostTime.IsZero() {
+		t.Errorf("unexpected comment: %+v", comments[0])
+	}
+}
+
+func TestNewsArticleUnmarshaling(t *testing.T) {
+	jsonData := `{
+		"cover": "https://example.com/cover.png",
+		"assets": [{"id": 1027, "name": "Ethereum", "symbol": "ETH", "slug": "ethereum"}],
+		"created_at": "2024-01-01T10:00:00.000Z",
+		"released_at": "2024-01-01T11:00:00.000Z",
+		"meta": {
+			"id": "abc",
+			"title": "Ethereum upgrade ships",
+			"sourceName": "CoinMarketCap",
+			"sourceUrl": "https://coinmarketcap.com/alexandria",
+			"language": "en",
+			"type": "news"
+		}
+	}`
+
+	var article NewsArticle
+	if err := json.Unmarshal([]byte(jsonData), &article); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if article.Meta.Title != "Ethereum upgrade ships" || article.Meta.SourceName != "CoinMarketCap" {
+		t.Errorf("unexpected meta: %+v", article.Meta)
+	}
+	if len(article.Assets) != 1 || article.Assets[0].ID != 1027 {
+		t.Errorf("expected ETH asset reference, got %+v", article.Assets)
+	}
+	if article.ReleasedAt == nil || article.ReleasedAt.Hour() != 11 {
+		t.Errorf("expected release time 11:00, got %v", article.ReleasedAt)
+	}
+}
+
+func TestExchangeAssetReserves(t *testing.T) {
+	jsonData := `[
+		{"wallet_address": "0xa", "balance": 2, "platform": {"crypto_id": 1027, "symbol": "ETH", "name": "Ethereum"}, "currency": {"crypto_id": 1027, "price_usd": 2000, "symbol": "ETH", "name": "Ethereum"}},
+		{"wallet_address": "0xb", "balance": 1000, "platform": {"crypto_id": 1027, "symbol": "ETH", "name": "Ethereum"}, "currency": {"crypto_id": 825, "price_usd": 1, "symbol": "USDT", "name": "Tether"}},
+		{"wallet_address": "Tc", "balance": 500, "platform": {"crypto_id": 1958, "symbol": "TRX", "name": "Tron"}, "currency": {"crypto_id": 825, "price_usd": 1, "symbol": "USDT", "name": "Tether"}}
+	]`
+
+	var assets []ExchangeAsset
+	if err := json.Unmarshal([]byte(jsonData), &assets); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if assets[0].WalletAddress != "0xa" || assets[0].Platform.Name != "Ethereum" || assets[0].ValueUSD() != 4000 {
+		t.Errorf("unexpected asset: %+v", assets[0])
+	}
+
+	byCurrency := ReservesByCurrency(assets)
+	if usdt := byCurrency["USDT"]; usdt.Balance != 1500 || usdt.ValueUSD != 1500 || usdt.Wallets != 2 {
+		t.Errorf("unexpected USDT reserves: %+v", usdt)
+	}
+
+	byChain := ReservesByChain(assets)
+	if eth := byChain["ETH"]; eth.ValueUSD != 5000 || eth.Wallets != 2 || eth.Currencies["USDT"].Balance != 1000 {
+		t.Errorf("unexpected ETH chain reserves: %+v", eth)
+	}
+	if trx := byChain["TRX"]; trx.ValueUSD != 500 || len(trx.Currencies) != 1 {
+		t.Errorf("unexpected TRX chain reserves: %+v", trx)
+	}
+
+	if total := TotalReservesUSD(assets); total != 5500 {
+		t.Errorf("expected 5500 USD in reserves, got %v", total)
+	}
+}